@@ -0,0 +1,230 @@
+// Package diff computes shortest edit scripts between sequences with Myers' O(ND) algorithm
+//	http://www.xmailserver.org/diff2.pdf
+//	it formats them as unified diffs and applies unified diffs back
+package diff
+
+import (
+	"strings"
+)
+
+// Op is the kind of an edit
+type Op int
+
+const (
+	// Equal keeps elements of both sequences
+	Equal Op = iota
+	// Delete removes elements of the first sequence
+	Delete
+	// Insert adds elements of the second sequence
+	Insert
+)
+
+func (op Op) String() string {
+	switch op {
+	case Equal:
+		return "equal"
+	case Delete:
+		return "delete"
+	case Insert:
+		return "insert"
+	default:
+		return "unknown"
+	}
+}
+
+// Edit is a run of elements with the same Op, a[A0:A1] are deleted or kept, b[B0:B1] are inserted or kept
+//	A0 == A1 for Insert, B0 == B1 for Delete, A1-A0 == B1-B0 for Equal
+type Edit struct {
+	Op     Op
+	A0, A1 int
+	B0, B1 int
+}
+
+// Diff returns the shortest edit script turning a of length n into b of length m
+//	equal(i, j) returns true if a[i] equals b[j], edits cover both sequences in order, a Delete goes before an Insert
+//	at the same place
+//	time complexity O((n+m)D), D is the number of deleted and inserted elements, space complexity O(n+m)
+func Diff(n, m int, equal func(i, j int) bool) []Edit {
+	d := &differ{
+		equal:    equal,
+		deleted:  make([]bool, n),
+		inserted: make([]bool, m),
+	}
+	size := (n+m+1)/2 + 1
+	d.offset = size
+	d.vf = make([]int, 2*size+1)
+	d.vb = make([]int, 2*size+1)
+	d.compare(0, n, 0, m)
+	return d.edits()
+}
+
+// Lines returns edits between lines of a and b, indexes refer to SplitLines(a) and SplitLines(b)
+func Lines(a, b string) []Edit {
+	return Strings(SplitLines(a), SplitLines(b))
+}
+
+// Runes returns edits between runes of a and b, indexes refer to []rune(a) and []rune(b)
+func Runes(a, b string) []Edit {
+	ra, rb := []rune(a), []rune(b)
+	return Diff(len(ra), len(rb), func(i, j int) bool {
+		return ra[i] == rb[j]
+	})
+}
+
+// Strings returns edits between a and b
+//	strings are replaced by ids first, so every comparison is an int comparison
+func Strings(a, b []string) []Edit {
+	ids := make(map[string]int)
+	id := func(ss []string) []int {
+		res := make([]int, len(ss))
+		for i, s := range ss {
+			v, ok := ids[s]
+			if !ok {
+				v = len(ids)
+				ids[s] = v
+			}
+			res[i] = v
+		}
+		return res
+	}
+	ia, ib := id(a), id(b)
+	return Diff(len(ia), len(ib), func(i, j int) bool {
+		return ia[i] == ib[j]
+	})
+}
+
+// SplitLines splits s after every "\n", the last line has no "\n" if s does not end with it
+func SplitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// differ marks deleted and inserted elements with linear space Myers' algorithm
+type differ struct {
+	equal             func(i, j int) bool
+	deleted, inserted []bool
+	// furthest x reaching every diagonal k = x - y, indexed by k + offset, forward and backward from the end
+	vf, vb []int
+	offset int
+}
+
+// compare marks edits between a[aLo:aHi] and b[bLo:bHi]
+//	the middle snake of the shortest path splits the problem into two halves of about D/2 edits each
+func (d *differ) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.equal(aLo, bLo) {
+		aLo++
+		bLo++
+	}
+	for aLo < aHi && bLo < bHi && d.equal(aHi-1, bHi-1) {
+		aHi--
+		bHi--
+	}
+	switch {
+	case aLo == aHi:
+		for j := bLo; j < bHi; j++ {
+			d.inserted[j] = true
+		}
+	case bLo == bHi:
+		for i := aLo; i < aHi; i++ {
+			d.deleted[i] = true
+		}
+	default:
+		x, y, u, v := d.middleSnake(aLo, aHi, bLo, bHi)
+		d.compare(aLo, x, bLo, y)
+		d.compare(u, aHi, v, bHi)
+	}
+}
+
+// middleSnake returns the snake (x, y) -> (u, v) in the middle of a shortest path from (aLo, bLo) to (aHi, bHi)
+//	paths are extended from both ends by one edit at a time until a forward path overlaps a backward path
+func (d *differ) middleSnake(aLo, aHi, bLo, bHi int) (x, y, u, v int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta&1 != 0
+	vf, vb, off := d.vf, d.vb, d.offset
+	vf[off+1], vb[off+1] = 0, 0
+
+	for D := 0; D <= (n+m+1)/2; D++ {
+		for k := -D; k <= D; k += 2 {
+			var x int
+			if k == -D || k != D && vf[off+k-1] < vf[off+k+1] {
+				// down, insertion
+				x = vf[off+k+1]
+			} else {
+				// right, deletion
+				x = vf[off+k-1] + 1
+			}
+			y := x - k
+			sx, sy := x, y
+			for x < n && y < m && d.equal(aLo+x, bLo+y) {
+				x++
+				y++
+			}
+			vf[off+k] = x
+			// backward diagonal delta-k was reached with D-1 edits
+			if odd && k >= delta-(D-1) && k <= delta+(D-1) && x+vb[off+delta-k] >= n {
+				return aLo + sx, bLo + sy, aLo + x, bLo + y
+			}
+		}
+
+		// backward x and y count from the ends
+		for k := -D; k <= D; k += 2 {
+			var x int
+			if k == -D || k != D && vb[off+k-1] < vb[off+k+1] {
+				x = vb[off+k+1]
+			} else {
+				x = vb[off+k-1] + 1
+			}
+			y := x - k
+			sx, sy := x, y
+			for x < n && y < m && d.equal(aHi-1-x, bHi-1-y) {
+				x++
+				y++
+			}
+			vb[off+k] = x
+			// forward diagonal delta-k was reached with D edits
+			if !odd && k >= delta-D && k <= delta+D && x+vf[off+delta-k] >= n {
+				return aHi - x, bHi - y, aHi - sx, bHi - sy
+			}
+		}
+	}
+	// unreachable, paths always overlap when D reaches (n+m+1)/2
+	return aLo, bLo, aHi, bHi
+}
+
+// edits turns marks into runs
+func (d *differ) edits() []Edit {
+	var edits []Edit
+	n, m := len(d.deleted), len(d.inserted)
+	i, j := 0, 0
+	for i < n || j < m {
+		e := Edit{A0: i, B0: j}
+		switch {
+		case i < n && d.deleted[i]:
+			e.Op = Delete
+			for i < n && d.deleted[i] {
+				i++
+			}
+		case j < m && d.inserted[j]:
+			e.Op = Insert
+			for j < m && d.inserted[j] {
+				j++
+			}
+		default:
+			e.Op = Equal
+			for i < n && j < m && !d.deleted[i] && !d.inserted[j] {
+				i++
+				j++
+			}
+		}
+		e.A1, e.B1 = i, j
+		edits = append(edits, e)
+	}
+	return edits
+}
@@ -0,0 +1,191 @@
+package diff
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// checkEdits checks edits turn a into b and cover both sequences in order
+func checkEdits(t *testing.T, a, b []rune, edits []Edit) {
+	i, j := 0, 0
+	var res []rune
+	for _, e := range edits {
+		if e.A0 != i || e.B0 != j {
+			t.Fatal(e, i, j)
+		}
+		switch e.Op {
+		case Equal:
+			if e.A1-e.A0 != e.B1-e.B0 || string(a[e.A0:e.A1]) != string(b[e.B0:e.B1]) {
+				t.Fatal(e)
+			}
+			res = append(res, a[e.A0:e.A1]...)
+		case Delete:
+			if e.B0 != e.B1 || e.A0 >= e.A1 {
+				t.Fatal(e)
+			}
+		case Insert:
+			if e.A0 != e.A1 || e.B0 >= e.B1 {
+				t.Fatal(e)
+			}
+			res = append(res, b[e.B0:e.B1]...)
+		}
+		i, j = e.A1, e.B1
+	}
+	if i != len(a) || j != len(b) || string(res) != string(b) {
+		t.Fatal(string(a), string(b), string(res))
+	}
+}
+
+// lcs returns length of the longest common subsequence
+func lcs(a, b []rune) int {
+	f := make([]int, len(b)+1)
+	for i := range a {
+		prev := 0
+		for j := range b {
+			cur := f[j+1]
+			if a[i] == b[j] {
+				f[j+1] = prev + 1
+			} else if f[j] > f[j+1] {
+				f[j+1] = f[j]
+			}
+			prev = cur
+		}
+	}
+	return f[len(b)]
+}
+
+func changes(edits []Edit) int {
+	d := 0
+	for _, e := range edits {
+		if e.Op != Equal {
+			d += e.A1 - e.A0 + e.B1 - e.B0
+		}
+	}
+	return d
+}
+
+func randomRunes(n int, alphabet string) []rune {
+	res := make([]rune, n)
+	for i := range res {
+		res[i] = rune(alphabet[rand.Intn(len(alphabet))])
+	}
+	return res
+}
+
+func TestDiff(t *testing.T) {
+	for _, alphabet := range []string{"a", "ab", "abc", "abcdefghij"} {
+		for n := 0; n < 20; n++ {
+			for round := 0; round < 20; round++ {
+				a, b := randomRunes(n, alphabet), randomRunes(rand.Intn(20), alphabet)
+				edits := Runes(string(a), string(b))
+				checkEdits(t, a, b, edits)
+				// shortest
+				if d := changes(edits); d != len(a)+len(b)-2*lcs(a, b) {
+					t.Fatal(string(a), string(b), d)
+				}
+			}
+		}
+	}
+}
+
+func TestDiff_Edited(t *testing.T) {
+	// few edits in long sequences
+	a := randomRunes(5000, "abcdefghijklmnopqrstuvwxyz")
+	b := append([]rune(nil), a...)
+	for i := 0; i < 20; i++ {
+		k := rand.Intn(len(b))
+		switch rand.Intn(3) {
+		case 0:
+			b = append(b[:k], b[k+1:]...)
+		case 1:
+			b = append(b[:k], append([]rune{'#'}, b[k:]...)...)
+		default:
+			b[k] = '#'
+		}
+	}
+	edits := Runes(string(a), string(b))
+	checkEdits(t, a, b, edits)
+	if d := changes(edits); d != len(a)+len(b)-2*lcs(a, b) {
+		t.Fatal(d)
+	}
+}
+
+func TestDiff_Ops(t *testing.T) {
+	edits := Runes("abcabba", "cbabac")
+	if changes(edits) != 5 {
+		t.Fatal(edits)
+	}
+	if edits := Runes("", ""); len(edits) != 0 {
+		t.Fatal(edits)
+	}
+	edits = Runes("", "ab")
+	if len(edits) != 1 || edits[0] != (Edit{Op: Insert, B1: 2}) {
+		t.Fatal(edits)
+	}
+	edits = Runes("ab", "")
+	if len(edits) != 1 || edits[0] != (Edit{Op: Delete, A1: 2}) {
+		t.Fatal(edits)
+	}
+	// delete before insert
+	edits = Runes("axc", "ayc")
+	want := []Edit{
+		{Op: Equal, A0: 0, A1: 1, B0: 0, B1: 1},
+		{Op: Delete, A0: 1, A1: 2, B0: 1, B1: 1},
+		{Op: Insert, A0: 2, A1: 2, B0: 1, B1: 2},
+		{Op: Equal, A0: 2, A1: 3, B0: 2, B1: 3},
+	}
+	if len(edits) != len(want) {
+		t.Fatal(edits)
+	}
+	for i := range want {
+		if edits[i] != want[i] {
+			t.Fatal(i, edits[i])
+		}
+	}
+	if Insert.String() != "insert" || Op(9).String() != "unknown" {
+		t.Fail()
+	}
+}
+
+func TestLines(t *testing.T) {
+	a := "host = a\nport = 80\nuser = root\n"
+	b := "host = b\nport = 80\nuser = root"
+	edits := Lines(a, b)
+	al, bl := SplitLines(a), SplitLines(b)
+	if len(al) != 3 || len(bl) != 3 || bl[2] != "user = root" {
+		t.Fatal(al, bl)
+	}
+	// "user = root" lost its "\n"
+	if changes(edits) != 4 {
+		t.Fatal(edits)
+	}
+	if SplitLines("") != nil || len(SplitLines("\n")) != 1 {
+		t.Fail()
+	}
+
+	// arbitrary slices
+	x, y := []int{1, 2, 3, 4}, []int{2, 3, 5}
+	edits = Diff(len(x), len(y), func(i, j int) bool {
+		return x[i] == y[j]
+	})
+	if changes(edits) != 3 {
+		t.Fatal(edits)
+	}
+}
+
+func BenchmarkLines(b *testing.B) {
+	lines := make([]string, 10000)
+	for i := range lines {
+		lines[i] = string(randomRunes(40, "abcdefghijklmnopqrstuvwxyz")) + "\n"
+	}
+	x := strings.Join(lines, "")
+	for i := 0; i < 100; i++ {
+		lines[rand.Intn(len(lines))] = "changed\n"
+	}
+	y := strings.Join(lines, "")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Lines(x, y)
+	}
+}
@@ -0,0 +1,200 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrMalformed means patch is not a unified diff
+	ErrMalformed = errors.New("diff: malformed patch")
+	// ErrConflict means removed or context lines of a hunk are not found in text
+	ErrConflict = errors.New("diff: hunk does not apply")
+)
+
+// PatchError reports line of patch where applying stopped, Err is ErrMalformed or ErrConflict
+type PatchError struct {
+	Line int
+	Err  error
+}
+
+func (e *PatchError) Error() string {
+	return e.Err.Error() + " at line " + strconv.Itoa(e.Line)
+}
+
+// Unwrap returns Err
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// patchHunk is a parsed hunk, old lines at a[start:] are replaced by new lines
+type patchHunk struct {
+	line     int
+	start    int
+	old, new []string
+}
+
+// Apply applies unified diff patch to text a and returns the patched text
+//	see ApplyLines
+func Apply(a, patch string) (string, error) {
+	lines, err := ApplyLines(SplitLines(a), patch)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(lines, ""), nil
+}
+
+// ApplyLines applies unified diff patch to lines a and returns the patched lines, a is not modified
+//	lines before the first "@@" such as "---" and "+++" are ignored, a hunk not found where its header says is
+//	searched at the nearest position after previous hunk, as patch does when a was edited elsewhere
+//	the error is a *PatchError
+func ApplyLines(a []string, patch string) ([]string, error) {
+	hunks, err := parsePatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]string, 0, len(a))
+	// pos is the first line of a not yet copied, offset is how far hunks moved so far
+	pos, offset := 0, 0
+	for _, h := range hunks {
+		at := findHunk(a, h.old, pos, h.start+offset)
+		if at < 0 {
+			return nil, &PatchError{Line: h.line, Err: ErrConflict}
+		}
+		res = append(res, a[pos:at]...)
+		res = append(res, h.new...)
+		pos = at + len(h.old)
+		offset = at - h.start
+	}
+	return append(res, a[pos:]...), nil
+}
+
+// findHunk returns index of old in a[pos:] closest to expected, -1 if not found
+func findHunk(a, old []string, pos, expected int) int {
+	last := len(a) - len(old)
+	if expected < pos {
+		expected = pos
+	}
+	if expected > last {
+		expected = last
+	}
+	for d := 0; expected-d >= pos || expected+d <= last; d++ {
+		if i := expected - d; i >= pos && i <= last && matchAt(a, old, i) {
+			return i
+		}
+		if i := expected + d; d > 0 && i >= pos && i <= last && matchAt(a, old, i) {
+			return i
+		}
+	}
+	return -1
+}
+
+func matchAt(a, old []string, i int) bool {
+	for j, s := range old {
+		if a[i+j] != s {
+			return false
+		}
+	}
+	return true
+}
+
+// parsePatch parses hunks of a unified diff, line counts of headers tell where hunks end,
+// so removed lines starting with "--" are not taken as headers
+func parsePatch(patch string) ([]patchHunk, error) {
+	lines := SplitLines(patch)
+	var hunks []patchHunk
+	for i := 0; i < len(lines); {
+		if !strings.HasPrefix(lines[i], "@@ ") {
+			i++
+			continue
+		}
+		h := patchHunk{line: i + 1}
+		oldLen, newLen, err := parseHunkHeader(lines[i], &h.start)
+		if err != nil {
+			return nil, &PatchError{Line: i + 1, Err: ErrMalformed}
+		}
+		i++
+		// last is the side of the previous line, a following "\ No newline at end of file" trims its "\n"
+		var last byte
+		for len(h.old) < oldLen || len(h.new) < newLen || i < len(lines) && strings.HasPrefix(lines[i], `\`) {
+			if i == len(lines) {
+				return nil, &PatchError{Line: i, Err: ErrMalformed}
+			}
+			l := lines[i]
+			kind := l[0]
+			if l == "\n" {
+				// editors may strip the space of an empty context line
+				kind, l = ' ', " \n"
+			}
+			switch kind {
+			case ' ':
+				h.old = append(h.old, l[1:])
+				h.new = append(h.new, l[1:])
+			case '-':
+				h.old = append(h.old, l[1:])
+			case '+':
+				h.new = append(h.new, l[1:])
+			case '\\':
+				if last == ' ' || last == '-' {
+					h.old[len(h.old)-1] = strings.TrimSuffix(h.old[len(h.old)-1], "\n")
+				}
+				if last == ' ' || last == '+' {
+					h.new[len(h.new)-1] = strings.TrimSuffix(h.new[len(h.new)-1], "\n")
+				}
+			default:
+				return nil, &PatchError{Line: i + 1, Err: ErrMalformed}
+			}
+			if len(h.old) > oldLen || len(h.new) > newLen {
+				return nil, &PatchError{Line: i + 1, Err: ErrMalformed}
+			}
+			last = kind
+			i++
+		}
+		if len(hunks) > 0 && h.start < hunks[len(hunks)-1].start+len(hunks[len(hunks)-1].old) {
+			// hunks overlap or are out of order
+			return nil, &PatchError{Line: h.line, Err: ErrMalformed}
+		}
+		hunks = append(hunks, h)
+	}
+	return hunks, nil
+}
+
+// parseHunkHeader parses "@@ -l,s +l,s @@", start is the 0-based index of the first old line
+func parseHunkHeader(header string, start *int) (oldLen, newLen int, err error) {
+	var oldRange, newRange string
+	if _, err = fmt.Sscanf(header, "@@ -%s +%s @@", &oldRange, &newRange); err != nil {
+		return 0, 0, err
+	}
+	oldStart, oldLen, err := parseRange(oldRange)
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, newLen, err = parseRange(newRange); err != nil {
+		return 0, 0, err
+	}
+	*start = oldStart
+	return oldLen, newLen, nil
+}
+
+// parseRange parses "l,s" or "l" which means "l,1", an empty range starts after line l
+func parseRange(s string) (start, length int, err error) {
+	length = 1
+	if i := strings.IndexByte(s, ','); i >= 0 {
+		if length, err = strconv.Atoi(s[i+1:]); err != nil {
+			return 0, 0, err
+		}
+		s = s[:i]
+	}
+	if start, err = strconv.Atoi(s); err != nil {
+		return 0, 0, err
+	}
+	if start < 0 || length < 0 || start == 0 && length > 0 {
+		return 0, 0, ErrMalformed
+	}
+	if length > 0 {
+		start--
+	}
+	return start, length, nil
+}
@@ -0,0 +1,87 @@
+package diff
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func randomText(n int) string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = string(randomRunes(rand.Intn(3), "ab-+ \\@"))
+	}
+	text := strings.Join(lines, "\n")
+	if rand.Intn(2) == 0 {
+		text += "\n"
+	}
+	return text
+}
+
+func TestApply(t *testing.T) {
+	for round := 0; round < 2000; round++ {
+		a, b := randomText(rand.Intn(30)), randomText(rand.Intn(30))
+		patch := Unified("a", "b", a, b)
+		got, err := Apply(a, patch)
+		if err != nil || got != b {
+			t.Fatalf("%q %q %q %v", a, b, got, err)
+		}
+	}
+}
+
+func TestApply_Offset(t *testing.T) {
+	a := "[server]\nhost = a\nport = 80\nlog = info\ncache = on\nworkers = 4\nqueue = 64\n\n[client]\nretry = 3\ntimeout = 10\n"
+	b := "[server]\nhost = b\nport = 80\nlog = info\ncache = on\nworkers = 4\nqueue = 64\n\n[client]\nretry = 5\ntimeout = 10\n"
+	patch := Unified("a", "b", a, b)
+	if strings.Count(patch, "@@ -") != 2 {
+		t.Fatal(patch)
+	}
+	// lines added before and between hunks
+	edited := "# config\n# v2\n" + strings.Replace(a, "cache = on\n", "cache = on\n# clients\n", 1)
+	want := "# config\n# v2\n" + strings.Replace(b, "cache = on\n", "cache = on\n# clients\n", 1)
+	got, err := Apply(edited, patch)
+	if err != nil || got != want {
+		t.Fatalf("%q %v", got, err)
+	}
+
+	// hunk not found
+	_, err = Apply(strings.Replace(a, "retry = 3", "retry = 4", 1), patch)
+	if pe, ok := err.(*PatchError); !ok || pe.Err != ErrConflict || pe.Line != 10 || pe.Unwrap() != ErrConflict {
+		t.Fatal(err)
+	}
+	if err.Error() != "diff: hunk does not apply at line 10" {
+		t.Fatal(err)
+	}
+}
+
+func TestApply_Malformed(t *testing.T) {
+	for _, patch := range []string{
+		"@@ -1 +1 @@\n-a\n",
+		"@@ -1,2 +1 @@\n-a\n+b\n+c\n",
+		"@@ -1 +1 @@\n*a\n+b\n",
+		"@@ -x +1 @@\n-a\n+b\n",
+		"@@ -0,1 +1 @@\n-a\n+b\n",
+		"@@ -2 +2 @@\n-b\n+c\n@@ -1 +1 @@\n-a\n+b\n",
+	} {
+		_, err := Apply("a\nb\n", patch)
+		if pe, ok := err.(*PatchError); !ok || pe.Err != ErrMalformed {
+			t.Fatal(patch, err)
+		}
+	}
+
+	// headers are skipped, an empty context line may lose its space
+	got, err := Apply("a\n\nb\n", "diff -u x y\n--- x\n+++ y\n@@ -1,3 +1,3 @@\n a\n\n-b\n+c\n")
+	if err != nil || got != "a\n\nc\n" {
+		t.Fatalf("%q %v", got, err)
+	}
+	// a removed line starting with "--"
+	got, err = Apply("-- a\n", Unified("x", "y", "-- a\n", "++ b\n"))
+	if err != nil || got != "++ b\n" {
+		t.Fatalf("%q %v", got, err)
+	}
+	// no hunks
+	got, err = Apply("a\n", "")
+	if err != nil || got != "a\n" {
+		t.Fatal(got, err)
+	}
+}
@@ -0,0 +1,137 @@
+package diff
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DefaultContext is the number of unchanged lines around changes, as diff -u
+const DefaultContext = 3
+
+// Hunk is a group of edits with unchanged lines around, a[A0:A1] turns into b[B0:B1]
+//	hunks closer than twice the context are merged, as between "@@" lines of a unified diff
+type Hunk struct {
+	A0, A1 int
+	B0, B1 int
+	Edits  []Edit
+}
+
+// Hunks groups changes of edits with at most context Equal elements before and after each group
+//	Equal edits are cut to context, no hunks are returned if edits have no changes
+func Hunks(edits []Edit, context int) []Hunk {
+	if context < 0 {
+		context = 0
+	}
+	var hunks []Hunk
+	var h *Hunk
+	flush := func() {
+		last := h.Edits[len(h.Edits)-1]
+		h.A1, h.B1 = last.A1, last.B1
+		hunks = append(hunks, *h)
+		h = nil
+	}
+	for i, e := range edits {
+		if e.Op != Equal {
+			if h == nil {
+				h = &Hunk{A0: e.A0, B0: e.B0}
+				if i > 0 {
+					if c := minInt(context, edits[i-1].A1-edits[i-1].A0); c > 0 {
+						h.A0 -= c
+						h.B0 -= c
+						h.Edits = append(h.Edits, Edit{Op: Equal, A0: e.A0 - c, A1: e.A0, B0: e.B0 - c, B1: e.B0})
+					}
+				}
+			}
+			h.Edits = append(h.Edits, e)
+			continue
+		}
+		if h == nil {
+			continue
+		}
+		size := e.A1 - e.A0
+		if i < len(edits)-1 && size <= 2*context {
+			h.Edits = append(h.Edits, e)
+			continue
+		}
+		if c := minInt(context, size); c > 0 {
+			h.Edits = append(h.Edits, Edit{Op: Equal, A0: e.A0, A1: e.A0 + c, B0: e.B0, B1: e.B0 + c})
+		}
+		flush()
+	}
+	if h != nil {
+		flush()
+	}
+	return hunks
+}
+
+// Unified returns the unified diff turning text a into text b with DefaultContext lines, "" if they are equal
+//	fromName and toName follow "---" and "+++"
+func Unified(fromName, toName string, a, b string) string {
+	al, bl := SplitLines(a), SplitLines(b)
+	var sb strings.Builder
+	// strings.Builder never fails
+	_ = WriteUnified(&sb, fromName, toName, al, bl, Hunks(Strings(al, bl), DefaultContext))
+	return sb.String()
+}
+
+// WriteUnified writes hunks between lines a and b in unified format to w, nothing is written without hunks
+//	lines keep their "\n" as returned by SplitLines, a last line without "\n" is marked
+//	"\ No newline at end of file" as diff does
+func WriteUnified(w io.Writer, fromName, toName string, a, b []string, hunks []Hunk) error {
+	if len(hunks) == 0 {
+		return nil
+	}
+	bw := bufio.NewWriter(w)
+	bw.WriteString("--- " + fromName + "\n")
+	bw.WriteString("+++ " + toName + "\n")
+	line := func(prefix byte, s string) {
+		bw.WriteByte(prefix)
+		bw.WriteString(s)
+		if !strings.HasSuffix(s, "\n") {
+			bw.WriteString("\n" + noNewline + "\n")
+		}
+	}
+	for _, h := range hunks {
+		bw.WriteString("@@ -" + unifiedRange(h.A0, h.A1-h.A0) + " +" + unifiedRange(h.B0, h.B1-h.B0) + " @@\n")
+		for _, e := range h.Edits {
+			switch e.Op {
+			case Equal:
+				for _, s := range a[e.A0:e.A1] {
+					line(' ', s)
+				}
+			case Delete:
+				for _, s := range a[e.A0:e.A1] {
+					line('-', s)
+				}
+			case Insert:
+				for _, s := range b[e.B0:e.B1] {
+					line('+', s)
+				}
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+const noNewline = `\ No newline at end of file`
+
+// unifiedRange formats lines [start, start+length) 1-based, an empty range starts at the line before it
+func unifiedRange(start, length int) string {
+	switch length {
+	case 0:
+		return strconv.Itoa(start) + ",0"
+	case 1:
+		return strconv.Itoa(start + 1)
+	default:
+		return strconv.Itoa(start+1) + "," + strconv.Itoa(length)
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
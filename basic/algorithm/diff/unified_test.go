@@ -0,0 +1,85 @@
+package diff
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	a := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n"
+	b := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn"
+	// as diff -u
+	want := `--- x
++++ y
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -11,3 +11,4 @@
+ k
+ l
+ m
++n
+\ No newline at end of file
+`
+	if got := Unified("x", "y", a, b); got != want {
+		t.Fatal(got)
+	}
+	if got := Unified("x", "y", a, a); got != "" {
+		t.Fatal(got)
+	}
+
+	// empty ranges start at the line before
+	want = `--- x
++++ y
+@@ -0,0 +1,2 @@
++a
++b
+`
+	if got := Unified("x", "y", "", "a\nb\n"); got != want {
+		t.Fatal(got)
+	}
+	want = `--- x
++++ y
+@@ -1,2 +1 @@
+ a
+-b
+`
+	if got := Unified("x", "y", "a\nb\n", "a\n"); got != want {
+		t.Fatal(got)
+	}
+}
+
+func TestHunks(t *testing.T) {
+	a := SplitLines("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n")
+	b := SplitLines("1\n2\nx\n4\n5\n6\n7\n8\ny\n10\n")
+	edits := Strings(a, b)
+	// 5 unchanged lines between changes are merged with context 3 and split with context 2
+	hunks := Hunks(edits, 3)
+	if len(hunks) != 1 || hunks[0].A0 != 0 || hunks[0].A1 != 10 || hunks[0].B0 != 0 || hunks[0].B1 != 10 {
+		t.Fatal(hunks)
+	}
+	hunks = Hunks(edits, 2)
+	if len(hunks) != 2 || hunks[0].A0 != 0 || hunks[0].A1 != 5 || hunks[1].A0 != 6 || hunks[1].A1 != 10 {
+		t.Fatal(hunks)
+	}
+	hunks = Hunks(edits, 0)
+	if len(hunks) != 2 || len(hunks[0].Edits) != 2 || hunks[0].A0 != 2 || hunks[0].A1 != 3 {
+		t.Fatal(hunks)
+	}
+	if Hunks(Strings(a, a), 3) != nil {
+		t.Fail()
+	}
+
+	var buf bytes.Buffer
+	if err := WriteUnified(&buf, "a", "b", a, b, Hunks(edits, 0)); err != nil {
+		t.Fatal(err)
+	}
+	want := "--- a\n+++ b\n@@ -3 +3 @@\n-3\n+x\n@@ -9 +9 @@\n-9\n+y\n"
+	if buf.String() != want {
+		t.Fatal(buf.String())
+	}
+}
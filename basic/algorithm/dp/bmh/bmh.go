@@ -0,0 +1,73 @@
+// Package bmh is Boyer-Moore-Horspool string search
+//	https://en.wikipedia.org/wiki/Boyer%E2%80%93Moore%E2%80%93Horspool_algorithm
+//	a window of text is compared with pattern from its last byte, then it shifts by the distance from the last
+//	occurrence of that byte in pattern to the end of pattern, so a byte absent from pattern skips len(pattern) bytes
+//	O(n/m) on average for large alphabets, O(nm) worst case, the shift table has 256 entries whatever the pattern
+package bmh
+
+import (
+	"bytes"
+	"godev/basic/algorithm/dp/matcher"
+	"io"
+)
+
+// BMH searches a pattern with the bad character rule of Horspool
+//	bytes are compared, so UTF-8 patterns and binary data work
+type BMH struct {
+	pattern []byte
+	shift   [256]int
+}
+
+// New creates a BMH for pattern
+func New(pattern string) *BMH {
+	b := &BMH{pattern: []byte(pattern)}
+	m := len(pattern)
+	for i := range b.shift {
+		b.shift[i] = m
+	}
+	// the last byte keeps the shift of its previous occurrence, so every shift is > 0
+	for i := 0; i < m-1; i++ {
+		b.shift[pattern[i]] = m - 1 - i
+	}
+	return b
+}
+
+// FindAll returns offsets of all matches in text in ascending order, overlapping matches included
+func (b *BMH) FindAll(text []byte) []int {
+	var ret []int
+	m, n := len(b.pattern), len(text)
+	if m == 0 {
+		return ret
+	}
+	last := b.pattern[m-1]
+	for i := 0; i <= n-m; {
+		c := text[i+m-1]
+		if c == last && bytes.Equal(text[i:i+m-1], b.pattern[:m-1]) {
+			ret = append(ret, i)
+		}
+		i += b.shift[c]
+	}
+	return ret
+}
+
+// FindAllReader returns offsets of all matches in r in ascending order
+func (b *BMH) FindAllReader(r io.Reader) ([]int64, error) {
+	return matcher.FindAllReader(r, len(b.pattern), b.FindAll)
+}
+
+// Index returns offset of the first match in text, -1 if not found
+func (b *BMH) Index(text []byte) int {
+	m, n := len(b.pattern), len(text)
+	if m == 0 {
+		return -1
+	}
+	last := b.pattern[m-1]
+	for i := 0; i <= n-m; {
+		c := text[i+m-1]
+		if c == last && bytes.Equal(text[i:i+m-1], b.pattern[:m-1]) {
+			return i
+		}
+		i += b.shift[c]
+	}
+	return -1
+}
@@ -0,0 +1,32 @@
+package bmh
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBMH(t *testing.T) {
+	b := New("ABABC")
+	txt := []byte("ABABDABABCABABCABCABABC")
+	assert.Equal(t, []int{5, 10, 18}, b.FindAll(txt))
+	assert.Equal(t, 5, b.Index(txt))
+	assert.Equal(t, -1, b.Index([]byte("ABAB")))
+
+	// overlapping
+	assert.Equal(t, []int{0, 1, 2}, New("aa").FindAll([]byte("aaaa")))
+	assert.Equal(t, []int{0, 2, 4}, New("aba").FindAll([]byte("abababa")))
+	assert.Equal(t, []int{3}, New("世界").FindAll([]byte("世世界")))
+	assert.Equal(t, []int{0, 2}, New("x").FindAll([]byte("xyx")))
+	assert.Empty(t, New("").FindAll([]byte("abc")))
+	assert.Equal(t, -1, New("").Index([]byte("abc")))
+	assert.Empty(t, New("abc").FindAll([]byte("ab")))
+}
+
+func TestNew(t *testing.T) {
+	b := New("abcab")
+	// last byte keeps the shift of its previous occurrence
+	assert.Equal(t, 3, b.shift['b'])
+	assert.Equal(t, 1, b.shift['a'])
+	assert.Equal(t, 2, b.shift['c'])
+	assert.Equal(t, 5, b.shift['z'])
+}
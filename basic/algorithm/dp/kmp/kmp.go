@@ -1,6 +1,12 @@
 package kmp
 
+import (
+	"godev/basic/algorithm/dp/matcher"
+	"io"
+)
+
 // my naive implementation
+//	notice: a table of 256 states per pattern position, see KMP
 type kmp struct {
 	states  [][]int32
 	pattern string
@@ -48,6 +54,7 @@ func (k *kmp) search(txt string) int {
 
 // KMP ...
 //	https://en.wikipedia.org/wiki/Knuth%E2%80%93Morris%E2%80%93Pratt_algorithm
+//	bytes are compared, so UTF-8 patterns and binary data work, next has len(pattern)+1 entries
 type KMP struct {
 	next    []int
 	pattern string
@@ -63,13 +70,14 @@ func New(pattern string) *KMP {
 	next := make([]int, m+1)
 	i, j := 0, -1
 	next[0] = -1
-	for i < m-1 {
+	// next[m] is the longest border of pattern, where search resumes after a match
+	for i < m {
 		for j > -1 && pattern[i] != pattern[j] {
 			j = next[j]
 		}
 		i++
 		j++
-		if pattern[i] == pattern[j] {
+		if i < m && pattern[i] == pattern[j] {
 			next[i] = next[j]
 		} else {
 			next[i] = j
@@ -82,9 +90,14 @@ func New(pattern string) *KMP {
 // Search ...
 //	all match idx array
 func (k *KMP) Search(txt string) []int {
+	return k.FindAll([]byte(txt))
+}
+
+// FindAll returns offsets of all matches in text in ascending order, overlapping matches included
+func (k *KMP) FindAll(text []byte) []int {
 	i, j := 0, 0
-	m, n := len(k.pattern), len(txt)
-	x, y := []byte(k.pattern), []byte(txt)
+	m, n := len(k.pattern), len(text)
+	x, y := k.pattern, text
 	var ret []int
 
 	if m == 0 || n == 0 || n < m {
@@ -106,6 +119,11 @@ func (k *KMP) Search(txt string) []int {
 	return ret
 }
 
+// FindAllReader returns offsets of all matches in r in ascending order
+func (k *KMP) FindAllReader(r io.Reader) ([]int64, error) {
+	return matcher.FindAllReader(r, len(k.pattern), k.FindAll)
+}
+
 // SearchFirst ...
 //	first position (first match)
 func (k *KMP) SearchFirst(txt string) int {
@@ -25,3 +25,12 @@ func TestNew(t *testing.T) {
 	kmp.SearchFirst("DDD")
 	kmp.SearchLast("DDD")
 }
+
+func TestKMP_FindAll(t *testing.T) {
+	// overlapping matches resume at the longest border
+	assert.Equal(t, []int{0, 1, 2}, New("aa").FindAll([]byte("aaaa")))
+	assert.Equal(t, []int{0, 2, 4}, New("aba").FindAll([]byte("abababa")))
+	assert.Equal(t, []int{3}, New("世界").FindAll([]byte("世世界")))
+	assert.Empty(t, New("").FindAll([]byte("abc")))
+	assert.Empty(t, New("abc").FindAll(nil))
+}
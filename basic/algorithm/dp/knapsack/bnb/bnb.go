@@ -0,0 +1,111 @@
+package bnb
+
+import "sort"
+
+// branch and bound for 0-1 knapsack problem, useful when capacity is too large for dynamic programming
+//	https://en.wikipedia.org/wiki/Branch_and_bound
+//	items are sorted by value density (v[i] / w[i]), depth first search tries to put in every item first, then not,
+//	a branch is cut when its bound, the value of filling the rest greedily with a fraction of the first item which
+//	does not fit (fractional knapsack, optimal when items can be split), is not greater than the best value found
+//	time consumption does not depend on capacity but is exponential in n in the worst case
+
+// BranchAndBound returns max value of items with a limited capacity and indexes of chosen items in ascending order
+//	items with negative weight or value <= 0 are never chosen, items with zero weight and positive value always are
+func BranchAndBound(weights []int, values []int, capacity int) (value int, chosen []int) {
+	if capacity < 0 {
+		return 0, nil
+	}
+	s := &solver{}
+	for i := range weights {
+		switch {
+		case weights[i] < 0 || values[i] <= 0 || weights[i] > capacity:
+		case weights[i] == 0:
+			s.base += values[i]
+			s.free = append(s.free, i)
+		default:
+			s.items = append(s.items, item{idx: i, weight: weights[i], value: values[i]})
+		}
+	}
+	sort.SliceStable(s.items, func(i, j int) bool {
+		// v[i] / w[i] > v[j] / w[j] without division
+		a, b := s.items[i], s.items[j]
+		return float64(a.value)*float64(b.weight) > float64(b.value)*float64(a.weight)
+	})
+
+	s.taken = make([]bool, len(s.items))
+	s.bestTaken = make([]bool, len(s.items))
+	s.greedy(capacity)
+	s.search(0, capacity, 0)
+
+	chosen = append(chosen, s.free...)
+	for i, taken := range s.bestTaken {
+		if taken {
+			chosen = append(chosen, s.items[i].idx)
+		}
+	}
+	sort.Ints(chosen)
+	return s.base + s.best, chosen
+}
+
+type item struct {
+	idx, weight, value int
+}
+
+type solver struct {
+	// items sorted by value density
+	items []item
+	// zero weight items and their value
+	free []int
+	base int
+	// current branch and the best solution found
+	taken     []bool
+	best      int
+	bestTaken []bool
+}
+
+// greedy puts in items by density, it is the first solution to prune branches with
+func (s *solver) greedy(capacity int) {
+	for i, it := range s.items {
+		if it.weight <= capacity {
+			capacity -= it.weight
+			s.best += it.value
+			s.bestTaken[i] = true
+		}
+	}
+}
+
+// bound returns the fractional knapsack value of items[i:] with capacity left
+func (s *solver) bound(i, capacity int) float64 {
+	bound := 0.0
+	for ; i < len(s.items); i++ {
+		it := s.items[i]
+		if it.weight > capacity {
+			return bound + float64(it.value)*float64(capacity)/float64(it.weight)
+		}
+		capacity -= it.weight
+		bound += float64(it.value)
+	}
+	return bound
+}
+
+// search decides items[i:] with capacity left and value of items decided
+func (s *solver) search(i, capacity, value int) {
+	if value > s.best {
+		s.best = value
+		copy(s.bestTaken, s.taken)
+	}
+	if i == len(s.items) || capacity == 0 {
+		return
+	}
+	// values are ints, so a bound below best + 1 can not improve
+	if float64(value)+s.bound(i, capacity) < float64(s.best+1) {
+		return
+	}
+
+	if it := s.items[i]; it.weight <= capacity {
+		s.taken[i] = true
+		s.search(i+1, capacity-it.weight, value+it.value)
+		s.taken[i] = false
+	}
+	s.search(i+1, capacity, value)
+}
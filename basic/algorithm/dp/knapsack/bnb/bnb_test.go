@@ -0,0 +1,102 @@
+package bnb
+
+import (
+	"godev/basic/algorithm/dp/knapsack/knapsack01"
+	"math/rand"
+	"testing"
+)
+
+func check(t *testing.T, weights []int, values []int, capacity, value int, chosen []int) {
+	w, v := 0, 0
+	for k, i := range chosen {
+		if k > 0 && chosen[k-1] >= i {
+			t.Fatal(chosen)
+		}
+		w += weights[i]
+		v += values[i]
+	}
+	if w > capacity || v != value {
+		t.Fatal(chosen, w, v, value)
+	}
+}
+
+func TestBranchAndBound(t *testing.T) {
+	weights := []int{3, 5, 6, 2, 7}
+	values := []int{6, 4, 9, 3, 8}
+	value, chosen := BranchAndBound(weights, values, 13)
+	if value != 18 {
+		t.Fatal(value, chosen)
+	}
+	check(t, weights, values, 13, value, chosen)
+
+	if value, chosen := BranchAndBound(nil, nil, 10); value != 0 || chosen != nil {
+		t.Fail()
+	}
+	if value, chosen := BranchAndBound(weights, values, -1); value != 0 || chosen != nil {
+		t.Fail()
+	}
+	// zero weight and non positive values
+	value, chosen = BranchAndBound([]int{0, 1, 1, -1}, []int{5, 0, -3, 100}, 10)
+	if value != 5 || len(chosen) != 1 || chosen[0] != 0 {
+		t.Fatal(value, chosen)
+	}
+
+	for round := 0; round < 300; round++ {
+		n := 1 + rand.Intn(20)
+		weights, values := make([]int, n), make([]int, n)
+		for i := range weights {
+			weights[i] = rand.Intn(30)
+			values[i] = rand.Intn(40)
+		}
+		capacity := rand.Intn(150)
+		value, chosen := BranchAndBound(weights, values, capacity)
+		if expected, _ := knapsack01.ZeroOnePackItems(weights, values, capacity); value != expected {
+			t.Fatal(weights, values, capacity, value, expected)
+		}
+		check(t, weights, values, capacity, value, chosen)
+	}
+}
+
+// TestBranchAndBound_LargeCapacity checks capacities far too large for a DP table
+func TestBranchAndBound_LargeCapacity(t *testing.T) {
+	n := 60
+	weights, values := make([]int, n), make([]int, n)
+	total := 0
+	for i := range weights {
+		// correlated weights and values are harder to prune
+		weights[i] = 1e9 + rand.Intn(1e9)
+		values[i] = weights[i]/1000 + rand.Intn(1e5)
+		total += weights[i]
+	}
+	capacity := total / 2
+	value, chosen := BranchAndBound(weights, values, capacity)
+	check(t, weights, values, capacity, value, chosen)
+
+	// scaled down instance with the same items keeps the same optimum if weights are multiples of scale
+	scale := int(1e6)
+	for i := range weights {
+		weights[i] = weights[i] / scale * scale
+	}
+	value, chosen = BranchAndBound(weights, values, capacity/scale*scale)
+	scaled := make([]int, n)
+	for i := range weights {
+		scaled[i] = weights[i] / scale
+	}
+	if expected, _ := knapsack01.ZeroOnePackItems(scaled, values, capacity/scale); value != expected {
+		t.Fatal(value, expected)
+	}
+	check(t, weights, values, capacity/scale*scale, value, chosen)
+}
+
+func BenchmarkBranchAndBound(b *testing.B) {
+	n := 40
+	weights, values := make([]int, n), make([]int, n)
+	for i := range weights {
+		weights[i] = 1 + rand.Intn(1e6)
+		values[i] = 1 + rand.Intn(1e6)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BranchAndBound(weights, values, 1e7)
+	}
+}
@@ -0,0 +1,52 @@
+package bounded
+
+import "godev/basic/algorithm/dp/knapsack/knapsack01"
+
+// bounded (multiple) knapsack problem, i-th item can be put in at most c[i] times
+//	https://en.wikipedia.org/wiki/Knapsack_problem#Definition
+//	c[i] copies are split into pieces of 1, 2, 4, ..., 2^(k-1) and the rest c[i] - 2^k + 1 copies,
+//	every count in [0, c[i]] is a sum of some pieces, so it becomes 0-1 knapsack of O(sum(log(c[i]))) items
+
+// piece is copies of an item packed as one 0-1 item
+type piece struct {
+	item, copies int
+}
+
+// BoundedPack returns max value of items with a limited capacity and how many copies of every item are chosen
+//	time consumption is O(V * sum(log(c[i]))), see knapsack01.ZeroOnePackItems for reconstruction
+//	items with negative weight or count <= 0 are never chosen
+func BoundedPack(weights []int, values []int, counts []int, capacity int) (value int, chosen []int) {
+	itemNum := len(weights)
+	if itemNum == 0 || capacity < 0 {
+		return 0, nil
+	}
+
+	var pieces []piece
+	var pieceWeights, pieceValues []int
+	for i := 0; i < itemNum; i++ {
+		if weights[i] < 0 {
+			continue
+		}
+		left := counts[i]
+		// more copies than capacity allows are useless
+		if weights[i] > 0 && left > capacity/weights[i] {
+			left = capacity / weights[i]
+		}
+		for k := 1; left > 0; k <<= 1 {
+			if k > left {
+				k = left
+			}
+			pieces = append(pieces, piece{item: i, copies: k})
+			pieceWeights = append(pieceWeights, k*weights[i])
+			pieceValues = append(pieceValues, k*values[i])
+			left -= k
+		}
+	}
+
+	value, chosenPieces := knapsack01.ZeroOnePackItems(pieceWeights, pieceValues, capacity)
+	chosen = make([]int, itemNum)
+	for _, p := range chosenPieces {
+		chosen[pieces[p].item] += pieces[p].copies
+	}
+	return value, chosen
+}
@@ -0,0 +1,62 @@
+package bounded
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// bruteForce returns max value by trying every count of the first item recursively
+func bruteForce(weights []int, values []int, counts []int, capacity int) int {
+	if len(weights) == 0 {
+		return 0
+	}
+	best := 0
+	for k := 0; k <= counts[0] && k*weights[0] <= capacity; k++ {
+		if v := k*values[0] + bruteForce(weights[1:], values[1:], counts[1:], capacity-k*weights[0]); v > best {
+			best = v
+		}
+	}
+	return best
+}
+
+func TestBoundedPack(t *testing.T) {
+	value, chosen := BoundedPack([]int{5, 3, 4}, []int{10, 7, 9}, []int{1, 2, 2}, 17)
+	// 5 + 3 + 4 + 4 -> 10 + 7 + 9 * 2
+	if value != 35 || chosen[0] != 1 || chosen[1] != 1 || chosen[2] != 2 {
+		t.Fatal(value, chosen)
+	}
+
+	if value, chosen := BoundedPack(nil, nil, nil, 10); value != 0 || chosen != nil {
+		t.Fail()
+	}
+	// large counts are bounded by capacity
+	if value, chosen := BoundedPack([]int{1}, []int{2}, []int{1 << 40}, 1000); value != 2000 || chosen[0] != 1000 {
+		t.Fatal(value, chosen)
+	}
+
+	for round := 0; round < 300; round++ {
+		n := 1 + rand.Intn(5)
+		weights, values, counts := make([]int, n), make([]int, n), make([]int, n)
+		for i := range weights {
+			weights[i] = rand.Intn(15)
+			values[i] = rand.Intn(30)
+			counts[i] = rand.Intn(8)
+		}
+		capacity := rand.Intn(60)
+		value, chosen := BoundedPack(weights, values, counts, capacity)
+		if expected := bruteForce(weights, values, counts, capacity); value != expected {
+			t.Fatal(weights, values, counts, capacity, value, expected)
+		}
+		w, v := 0, 0
+		for i, c := range chosen {
+			if c > counts[i] {
+				t.Fatal(chosen, counts)
+			}
+			w += c * weights[i]
+			v += c * values[i]
+		}
+		if w > capacity || v != value {
+			t.Fatal(chosen, w, v)
+		}
+	}
+}
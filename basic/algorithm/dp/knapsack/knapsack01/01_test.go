@@ -1,6 +1,9 @@
 package knapsack01
 
-import "testing"
+import (
+	"math/rand"
+	"testing"
+)
 
 func TestZeroOnePack(t *testing.T) {
 	items := [][]int{
@@ -30,3 +33,65 @@ func TestZeroOnePack(t *testing.T) {
 		t.Fatal("ZeroOnePackSpaceOpt1: ", res)
 	}
 }
+
+// bruteForce returns max value over all subsets of items
+func bruteForce(weights []int, values []int, capacity int) int {
+	best := 0
+	for set := 0; set < 1<<uint(len(weights)); set++ {
+		w, v := 0, 0
+		for i := range weights {
+			if set&(1<<uint(i)) != 0 {
+				w += weights[i]
+				v += values[i]
+			}
+		}
+		if w <= capacity && v > best {
+			best = v
+		}
+	}
+	return best
+}
+
+func TestZeroOnePackItems(t *testing.T) {
+	weights := []int{3, 5, 6, 2, 7}
+	values := []int{6, 4, 9, 3, 8}
+	value, chosen := ZeroOnePackItems(weights, values, 13)
+	if value != 18 || len(chosen) != 3 || chosen[0] != 0 || chosen[1] != 2 || chosen[2] != 3 {
+		t.Fatal(value, chosen)
+	}
+
+	if value, chosen := ZeroOnePackItems(nil, nil, 10); value != 0 || chosen != nil {
+		t.Fail()
+	}
+	if value, chosen := ZeroOnePackItems(weights, values, 1); value != 0 || len(chosen) != 0 {
+		t.Fail()
+	}
+
+	for round := 0; round < 200; round++ {
+		n := 1 + rand.Intn(12)
+		weights, values := make([]int, n), make([]int, n)
+		for i := range weights {
+			weights[i] = rand.Intn(20)
+			values[i] = rand.Intn(30)
+		}
+		capacity := rand.Intn(60)
+		value, chosen := ZeroOnePackItems(weights, values, capacity)
+		if expected := bruteForce(weights, values, capacity); value != expected {
+			t.Fatal(weights, values, capacity, value, expected)
+		}
+		if value != ZeroOnePackSpaceOpt1(weights, values, capacity) {
+			t.Fatal(value)
+		}
+		w, v := 0, 0
+		for k, i := range chosen {
+			if k > 0 && chosen[k-1] >= i {
+				t.Fatal(chosen)
+			}
+			w += weights[i]
+			v += values[i]
+		}
+		if w > capacity || v != value {
+			t.Fatal(chosen, w, v)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package knapsack01
+
+import "godev/basic/datastructure/bitmap/bitset"
+
+// ZeroOnePackItems returns max value of items with a limited capacity and indexes of chosen items in ascending order
+//	f is the 1D array of ZeroOnePackSpaceOpt1, so time consumption is O(nV) and value space is O(V),
+//	besides every decision "i-th item put in at volume j" is kept in a bitset of n*(V+1) bits,
+//	walking back from i = n-1, j = V: if i-th item was put in at j, it is chosen and j -= w[i]
+//	items with negative weight are never chosen
+func ZeroOnePackItems(weights []int, values []int, capacity int) (value int, chosen []int) {
+	itemNum := len(weights)
+	if itemNum == 0 || capacity < 0 {
+		return 0, nil
+	}
+
+	f := make([]int, capacity+1)
+	width := capacity + 1
+	put := bitset.NewBitSet(uint(itemNum * width))
+	for i := 0; i < itemNum; i++ {
+		if weights[i] < 0 {
+			continue
+		}
+		for j := capacity; j >= weights[i]; j-- {
+			// `>` keeps the item out on ties, so fewer items are chosen
+			if v := f[j-weights[i]] + values[i]; v > f[j] {
+				f[j] = v
+				put.Set(uint(i*width + j))
+			}
+		}
+	}
+
+	j := capacity
+	for i := itemNum - 1; i >= 0; i-- {
+		if put.Test(uint(i*width + j)) {
+			chosen = append(chosen, i)
+			j -= weights[i]
+		}
+	}
+	for l, r := 0, len(chosen)-1; l < r; l, r = l+1, r-1 {
+		chosen[l], chosen[r] = chosen[r], chosen[l]
+	}
+	return f[capacity], chosen
+}
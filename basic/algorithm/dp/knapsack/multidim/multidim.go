@@ -0,0 +1,95 @@
+package multidim
+
+import "godev/basic/datastructure/bitmap/bitset"
+
+// multi-dimensional 0-1 knapsack problem, every item costs d resources (e.g. cpu, memory, disk) with d capacities
+//	https://en.wikipedia.org/wiki/Knapsack_problem#Multi-dimensional_knapsack_problem
+//	state transfer function: f[i][V1]...[Vd] = max{f[i-1][V1]...[Vd], f[i-1][V1-w[i][1]]...[Vd-w[i][d]] + v[i]}
+//	f is one array indexed by mixed radix (V1, ..., Vd), Vk ranges in [0, Ck],
+//	so going from the last index down reuses it like the 1D 0-1 knapsack
+
+// MultiDimPack returns max value of items with limited capacities and indexes of chosen items in ascending order
+//	weights[i][k] is i-th item weight in k-th dimension, len(weights[i]) == len(capacities)
+//	time consumption is O(n * d * S), space consumption is O(S) ints plus n*S bits for reconstruction,
+//	S = (C1+1) * ... * (Cd+1)
+//	items with a negative weight are never chosen
+func MultiDimPack(weights [][]int, values []int, capacities []int) (value int, chosen []int) {
+	itemNum, dims := len(weights), len(capacities)
+	if itemNum == 0 {
+		return 0, nil
+	}
+	// strides of mixed radix
+	strides := make([]int, dims)
+	size := 1
+	for k := dims - 1; k >= 0; k-- {
+		if capacities[k] < 0 {
+			return 0, nil
+		}
+		strides[k] = size
+		size *= capacities[k] + 1
+	}
+
+	f := make([]int, size)
+	put := bitset.NewBitSet(uint(itemNum * size))
+	digits := make([]int, dims)
+	for i := 0; i < itemNum; i++ {
+		// offset of item i in f, -1 if it is never chosen
+		offset := 0
+		for k := 0; k < dims; k++ {
+			if weights[i][k] < 0 || weights[i][k] > capacities[k] {
+				offset = -1
+				break
+			}
+			offset += weights[i][k] * strides[k]
+		}
+		if offset < 0 {
+			continue
+		}
+
+		// digits of idx, decremented along with idx
+		for k := range digits {
+			digits[k] = capacities[k]
+		}
+		for idx := size - 1; idx >= offset; idx-- {
+			fits := true
+			for k := 0; k < dims; k++ {
+				if digits[k] < weights[i][k] {
+					fits = false
+					break
+				}
+			}
+			if fits {
+				if v := f[idx-offset] + values[i]; v > f[idx] {
+					f[idx] = v
+					put.Set(uint(i*size + idx))
+				}
+			}
+			decrement(digits, capacities)
+		}
+	}
+
+	idx := size - 1
+	for i := itemNum - 1; i >= 0; i-- {
+		if put.Test(uint(i*size + idx)) {
+			chosen = append(chosen, i)
+			for k := 0; k < dims; k++ {
+				idx -= weights[i][k] * strides[k]
+			}
+		}
+	}
+	for l, r := 0, len(chosen)-1; l < r; l, r = l+1, r-1 {
+		chosen[l], chosen[r] = chosen[r], chosen[l]
+	}
+	return f[size-1], chosen
+}
+
+// decrement decrements mixed radix digits by one, the last digit is the lowest
+func decrement(digits, capacities []int) {
+	for k := len(digits) - 1; k >= 0; k-- {
+		if digits[k] > 0 {
+			digits[k]--
+			return
+		}
+		digits[k] = capacities[k]
+	}
+}
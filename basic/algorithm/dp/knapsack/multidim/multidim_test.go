@@ -0,0 +1,87 @@
+package multidim
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// bruteForce returns max value over all subsets of items
+func bruteForce(weights [][]int, values []int, capacities []int) int {
+	best := 0
+	for set := 0; set < 1<<uint(len(weights)); set++ {
+		used := make([]int, len(capacities))
+		v, fits := 0, true
+		for i := range weights {
+			if set&(1<<uint(i)) == 0 {
+				continue
+			}
+			v += values[i]
+			for k := range capacities {
+				used[k] += weights[i][k]
+				if used[k] > capacities[k] {
+					fits = false
+				}
+			}
+		}
+		if fits && v > best {
+			best = v
+		}
+	}
+	return best
+}
+
+func TestMultiDimPack(t *testing.T) {
+	// cpu, memory
+	weights := [][]int{{2, 4}, {1, 8}, {3, 2}, {2, 2}}
+	values := []int{10, 12, 9, 6}
+	value, chosen := MultiDimPack(weights, values, []int{5, 8})
+	// {2, 4} + {3, 2} -> 19, {2, 4} + {2, 2} -> 16, {1, 8} alone -> 12
+	if value != 19 || len(chosen) != 2 || chosen[0] != 0 || chosen[1] != 2 {
+		t.Fatal(value, chosen)
+	}
+
+	if value, chosen := MultiDimPack(nil, nil, []int{1}); value != 0 || chosen != nil {
+		t.Fail()
+	}
+	if value, _ := MultiDimPack(weights, values, []int{5, -1}); value != 0 {
+		t.Fail()
+	}
+	// one dimension is plain 0-1 knapsack
+	if value, _ := MultiDimPack([][]int{{3}, {5}, {6}, {2}, {7}}, []int{6, 4, 9, 3, 8}, []int{13}); value != 18 {
+		t.Fatal(value)
+	}
+
+	for round := 0; round < 200; round++ {
+		n, dims := 1+rand.Intn(10), 1+rand.Intn(3)
+		weights, values, capacities := make([][]int, n), make([]int, n), make([]int, dims)
+		for i := range weights {
+			weights[i] = make([]int, dims)
+			for k := range weights[i] {
+				weights[i][k] = rand.Intn(8)
+			}
+			values[i] = rand.Intn(30)
+		}
+		for k := range capacities {
+			capacities[k] = rand.Intn(15)
+		}
+		value, chosen := MultiDimPack(weights, values, capacities)
+		if expected := bruteForce(weights, values, capacities); value != expected {
+			t.Fatal(weights, values, capacities, value, expected)
+		}
+		used, v := make([]int, dims), 0
+		for _, i := range chosen {
+			v += values[i]
+			for k := range used {
+				used[k] += weights[i][k]
+			}
+		}
+		for k := range used {
+			if used[k] > capacities[k] {
+				t.Fatal(chosen, used)
+			}
+		}
+		if v != value {
+			t.Fatal(chosen, v)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package unbounded
+
+// unbounded (complete) knapsack problem, every item can be put in any number of times
+//	https://en.wikipedia.org/wiki/Knapsack_problem#Unbounded_knapsack_problem
+//	state transfer function: f[V] = max{f[V-1], f[V-w[i]] + v[i] for every i}
+//	f: total value function, f[V-1] means volume V is not fully used
+//	V: total volume
+//	w[i]: i-th item weight (volume cost)
+//	v[i]: i-th item value
+
+// noItem marks f[j] inheriting f[j-1]
+const noItem = -1
+
+// UnboundedPack returns max value of items with a limited capacity and how many times every item is chosen
+//	time consumption is O(nV), space consumption is O(V): last[j] keeps the item put in last to reach f[j],
+//	so walking back from V gives counts of chosen items
+//	items whose weight <= 0 are ignored, since they could be put in infinitely
+func UnboundedPack(weights []int, values []int, capacity int) (value int, counts []int) {
+	itemNum := len(weights)
+	if itemNum == 0 || capacity < 0 {
+		return 0, nil
+	}
+
+	f := make([]int, capacity+1)
+	last := make([]int, capacity+1)
+	last[0] = noItem
+	for j := 1; j <= capacity; j++ {
+		f[j], last[j] = f[j-1], noItem
+		for i := 0; i < itemNum; i++ {
+			if weights[i] <= 0 || weights[i] > j {
+				continue
+			}
+			if v := f[j-weights[i]] + values[i]; v > f[j] {
+				f[j], last[j] = v, i
+			}
+		}
+	}
+
+	counts = make([]int, itemNum)
+	for j := capacity; j > 0; {
+		if last[j] == noItem {
+			j--
+			continue
+		}
+		counts[last[j]]++
+		j -= weights[last[j]]
+	}
+	return f[capacity], counts
+}
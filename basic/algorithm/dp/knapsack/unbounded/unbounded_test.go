@@ -0,0 +1,58 @@
+package unbounded
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// bruteForce returns max value by trying every count of the first item recursively
+func bruteForce(weights []int, values []int, capacity int) int {
+	if len(weights) == 0 {
+		return 0
+	}
+	best := 0
+	for k := 0; k*weights[0] <= capacity; k++ {
+		if v := k*values[0] + bruteForce(weights[1:], values[1:], capacity-k*weights[0]); v > best {
+			best = v
+		}
+	}
+	return best
+}
+
+func TestUnboundedPack(t *testing.T) {
+	value, counts := UnboundedPack([]int{5, 3, 4}, []int{10, 7, 9}, 17)
+	// 3 + 3 + 3 + 4 + 4 -> 7 * 3 + 9 * 2
+	if value != 39 || counts[0] != 0 || counts[1] != 3 || counts[2] != 2 {
+		t.Fatal(value, counts)
+	}
+
+	if value, counts := UnboundedPack(nil, nil, 10); value != 0 || counts != nil {
+		t.Fail()
+	}
+	// zero weight is ignored
+	if value, counts := UnboundedPack([]int{0, 2}, []int{100, 1}, 5); value != 2 || counts[0] != 0 || counts[1] != 2 {
+		t.Fatal(value, counts)
+	}
+
+	for round := 0; round < 200; round++ {
+		n := 1 + rand.Intn(5)
+		weights, values := make([]int, n), make([]int, n)
+		for i := range weights {
+			weights[i] = 1 + rand.Intn(15)
+			values[i] = rand.Intn(30)
+		}
+		capacity := rand.Intn(50)
+		value, counts := UnboundedPack(weights, values, capacity)
+		if expected := bruteForce(weights, values, capacity); value != expected {
+			t.Fatal(weights, values, capacity, value, expected)
+		}
+		w, v := 0, 0
+		for i, c := range counts {
+			w += c * weights[i]
+			v += c * values[i]
+		}
+		if w > capacity || v != value {
+			t.Fatal(counts, w, v)
+		}
+	}
+}
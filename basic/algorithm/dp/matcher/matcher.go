@@ -0,0 +1,62 @@
+// Package matcher is the common interface of exact string search algorithms, see kmp, bmh and rabinkarp
+//	benchmarks against strings.Index are in matcher_test.go: bmh is the fastest for large alphabets such as text,
+//	rabinkarp is steady for small alphabets such as DNA, rabinkarp.Multi finds many patterns in one pass
+//	and kmp never moves back in text
+package matcher
+
+import (
+	"io"
+)
+
+// ChunkSize is the number of bytes FindAllReader searches at once
+const ChunkSize = 64 << 10
+
+// Matcher finds all occurrences of one or more patterns, an empty pattern matches nothing
+type Matcher interface {
+	// FindAll returns offsets of all matches in text in ascending order, overlapping matches included
+	FindAll(text []byte) []int
+	// FindAllReader returns offsets of all matches in r in ascending order, r is read until io.EOF
+	FindAllReader(r io.Reader) ([]int64, error)
+}
+
+// FindAllReader reads r in chunks and returns offsets found by findAll, maxLen is the length of the longest pattern
+//	the last maxLen-1 bytes of a chunk are searched again in front of the next chunk, an offset is only kept when
+//	it is followed by maxLen bytes or by the end of r, so every match is found once with all the bytes it covers
+//	memory is O(ChunkSize + maxLen) whatever the size of r, offsets read before an error are returned with it
+func FindAllReader(r io.Reader, maxLen int, findAll func(text []byte) []int) ([]int64, error) {
+	var offsets []int64
+	if maxLen < 1 {
+		maxLen = 1
+	}
+	keep := maxLen - 1
+	buf := make([]byte, 0, ChunkSize+keep)
+	// offset of buf[0] in r
+	var base int64
+	for {
+		n, err := r.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		eof := err == io.EOF
+		if err != nil && !eof {
+			return offsets, err
+		}
+		if !eof && len(buf) < cap(buf) {
+			continue
+		}
+
+		limit := len(buf) - keep
+		if eof {
+			limit = len(buf)
+		}
+		for _, i := range findAll(buf) {
+			if i >= limit {
+				break
+			}
+			offsets = append(offsets, base+int64(i))
+		}
+		if eof {
+			return offsets, nil
+		}
+		base += int64(limit)
+		buf = buf[:copy(buf, buf[limit:])]
+	}
+}
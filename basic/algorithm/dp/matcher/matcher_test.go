@@ -0,0 +1,208 @@
+package matcher_test
+
+import (
+	"bytes"
+	"errors"
+	"godev/basic/algorithm/dp/bmh"
+	"godev/basic/algorithm/dp/kmp"
+	"godev/basic/algorithm/dp/matcher"
+	"godev/basic/algorithm/dp/rabinkarp"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var matchers = []struct {
+	name string
+	new  func(pattern string) matcher.Matcher
+}{
+	{"kmp", func(p string) matcher.Matcher { return kmp.New(p) }},
+	{"bmh", func(p string) matcher.Matcher { return bmh.New(p) }},
+	{"rabinkarp", func(p string) matcher.Matcher { return rabinkarp.New(p) }},
+	{"multi", func(p string) matcher.Matcher { return rabinkarp.NewMulti(p) }},
+}
+
+// naive returns offsets where any pattern matches
+func naive(text []byte, patterns ...string) []int {
+	var ret []int
+	for i := range text {
+		for _, p := range patterns {
+			if p != "" && bytes.HasPrefix(text[i:], []byte(p)) {
+				ret = append(ret, i)
+				break
+			}
+		}
+	}
+	return ret
+}
+
+func randomBytes(n int, alphabet string) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = alphabet[rand.Intn(len(alphabet))]
+	}
+	return b
+}
+
+func equal64(a []int, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if int64(a[i]) != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFindAll(t *testing.T) {
+	for _, m := range matchers {
+		for _, alphabet := range []string{"a", "ab", "abcd", "\x00\xff"} {
+			for round := 0; round < 200; round++ {
+				text := randomBytes(rand.Intn(100), alphabet)
+				pattern := string(randomBytes(rand.Intn(6), alphabet))
+				if got, want := m.new(pattern).FindAll(text), naive(text, pattern); len(got) != len(want) {
+					t.Fatal(m.name, string(text), pattern, got, want)
+				} else {
+					for i := range got {
+						if got[i] != want[i] {
+							t.Fatal(m.name, string(text), pattern, got, want)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestFindAllReader(t *testing.T) {
+	// matches cross chunk boundaries
+	text := randomBytes(3*matcher.ChunkSize+1234, "ab")
+	for _, m := range matchers {
+		for _, pattern := range []string{"a", "ab", "abba", "babbbaab", "ababababababababab"} {
+			want := naive(text, pattern)
+			for _, r := range []io.Reader{
+				bytes.NewReader(text),
+				iotest.HalfReader(bytes.NewReader(text)),
+				iotest.DataErrReader(bytes.NewReader(text)),
+			} {
+				got, err := m.new(pattern).FindAllReader(r)
+				if err != nil || !equal64(want, got) {
+					t.Fatal(m.name, pattern, len(got), len(want), err)
+				}
+			}
+		}
+		got, err := m.new("a").FindAllReader(strings.NewReader(""))
+		if err != nil || len(got) != 0 {
+			t.Fatal(m.name, got, err)
+		}
+	}
+
+	// patterns of different lengths
+	patterns := []string{"ba", "abbab", "aaaaaaaaaaaa"}
+	got, err := rabinkarp.NewMulti(patterns...).FindAllReader(bytes.NewReader(text))
+	if want := naive(text, patterns...); err != nil || !equal64(want, got) {
+		t.Fatal(len(got), len(want), err)
+	}
+}
+
+type failReader struct {
+	r io.Reader
+	n int
+}
+
+var errRead = errors.New("read failed")
+
+func (f *failReader) Read(p []byte) (int, error) {
+	if f.n <= 0 {
+		return 0, errRead
+	}
+	if len(p) > f.n {
+		p = p[:f.n]
+	}
+	n, err := f.r.Read(p)
+	f.n -= n
+	return n, err
+}
+
+func TestFindAllReader_Error(t *testing.T) {
+	text := bytes.Repeat([]byte("ab"), matcher.ChunkSize)
+	for _, m := range matchers {
+		got, err := m.new("ab").FindAllReader(&failReader{r: bytes.NewReader(text), n: matcher.ChunkSize + 10})
+		// the first chunk is searched before the error
+		if err != errRead || len(got) != matcher.ChunkSize/2 {
+			t.Fatal(m.name, len(got), err)
+		}
+	}
+}
+
+// allIndex returns offsets of all matches with strings.Index
+func allIndex(text, pattern string) []int {
+	var ret []int
+	for i := 0; ; {
+		j := strings.Index(text[i:], pattern)
+		if j < 0 {
+			return ret
+		}
+		ret = append(ret, i+j)
+		i += j + 1
+	}
+}
+
+func benchmarkFind(b *testing.B, text []byte, pattern string) {
+	s := string(text)
+	b.Run("strings.Index", func(b *testing.B) {
+		b.SetBytes(int64(len(text)))
+		for i := 0; i < b.N; i++ {
+			allIndex(s, pattern)
+		}
+	})
+	for _, m := range matchers {
+		mt := m.new(pattern)
+		b.Run(m.name, func(b *testing.B) {
+			b.SetBytes(int64(len(text)))
+			for i := 0; i < b.N; i++ {
+				mt.FindAll(text)
+			}
+		})
+	}
+}
+
+func BenchmarkFindAll_Letters(b *testing.B) {
+	text := randomBytes(1<<20, "abcdefghijklmnopqrstuvwxyz")
+	pattern := string(randomBytes(16, "abcdefghijklmnopqrstuvwxyz"))
+	copy(text[len(text)-16:], pattern)
+	benchmarkFind(b, text, pattern)
+}
+
+func BenchmarkFindAll_DNA(b *testing.B) {
+	text := randomBytes(1<<20, "ACGT")
+	benchmarkFind(b, text, string(randomBytes(16, "ACGT")))
+}
+
+func BenchmarkFindAll_Multi(b *testing.B) {
+	text := randomBytes(1<<20, "abcdefghijklmnopqrstuvwxyz")
+	patterns := make([]string, 100)
+	for i := range patterns {
+		patterns[i] = string(randomBytes(8+rand.Intn(8), "abcdefghijklmnopqrstuvwxyz"))
+	}
+	s := string(text)
+	b.Run("strings.Index", func(b *testing.B) {
+		b.SetBytes(int64(len(text)))
+		for i := 0; i < b.N; i++ {
+			for _, p := range patterns {
+				allIndex(s, p)
+			}
+		}
+	})
+	mu := rabinkarp.NewMulti(patterns...)
+	b.Run("multi", func(b *testing.B) {
+		b.SetBytes(int64(len(text)))
+		for i := 0; i < b.N; i++ {
+			mu.FindAll(text)
+		}
+	})
+}
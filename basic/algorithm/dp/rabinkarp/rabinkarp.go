@@ -0,0 +1,164 @@
+// Package rabinkarp is Rabin-Karp string search with a rolling hash
+//	https://en.wikipedia.org/wiki/Rabin%E2%80%93Karp_algorithm
+//	hash of a window is a polynomial of its bytes modulo 2^32, sliding by one byte adds the new byte and removes the
+//	old one in O(1), only windows with the hash of a pattern are compared
+//	O(n+m) on average, O(nm) worst case, several patterns are searched in one pass
+package rabinkarp
+
+import (
+	"bytes"
+	"godev/basic/algorithm/dp/matcher"
+	"io"
+)
+
+const (
+	// prime is the base of the polynomial hash, as strings.Index
+	prime = 16777619
+	// filterBits is the size of the bitmap of prefix hashes of Multi
+	filterBits = 1 << 16
+)
+
+// hash returns the hash of p and prime^len(p) to remove the first byte of a window of len(p) bytes
+func hash(p []byte) (h, pow uint32) {
+	pow = 1
+	for _, c := range p {
+		h = h*prime + uint32(c)
+		pow *= prime
+	}
+	return h, pow
+}
+
+// RabinKarp searches a pattern
+//	bytes are compared, so UTF-8 patterns and binary data work
+type RabinKarp struct {
+	pattern []byte
+	hash    uint32
+	pow     uint32
+}
+
+// New creates a RabinKarp for pattern
+func New(pattern string) *RabinKarp {
+	rk := &RabinKarp{pattern: []byte(pattern)}
+	rk.hash, rk.pow = hash(rk.pattern)
+	return rk
+}
+
+// FindAll returns offsets of all matches in text in ascending order, overlapping matches included
+func (rk *RabinKarp) FindAll(text []byte) []int {
+	var ret []int
+	m, n := len(rk.pattern), len(text)
+	if m == 0 || n < m {
+		return ret
+	}
+	h, _ := hash(text[:m])
+	for i := 0; ; i++ {
+		if h == rk.hash && bytes.Equal(text[i:i+m], rk.pattern) {
+			ret = append(ret, i)
+		}
+		if i+m == n {
+			return ret
+		}
+		h = h*prime + uint32(text[i+m]) - rk.pow*uint32(text[i])
+	}
+}
+
+// FindAllReader returns offsets of all matches in r in ascending order
+func (rk *RabinKarp) FindAllReader(r io.Reader) ([]int64, error) {
+	return matcher.FindAllReader(r, len(rk.pattern), rk.FindAll)
+}
+
+// Match is an occurrence of the Pattern-th pattern of Multi at Offset
+type Match struct {
+	Offset  int
+	Pattern int
+}
+
+// Multi searches several patterns in one pass
+//	windows are as long as the shortest pattern and hashes of pattern prefixes of that length are looked up in a
+//	map, candidates are compared with whole patterns, so cost hardly depends on the number of patterns
+//	a bitmap of prefix hashes rejects most windows before the map lookup
+type Multi struct {
+	patterns [][]byte
+	// window is the length of the shortest non empty pattern, maxLen of the longest
+	window, maxLen int
+	pow            uint32
+	// hash of prefix -> indexes of patterns
+	prefixes map[uint32][]int
+	filter   [filterBits / 64]uint64
+}
+
+// NewMulti creates a Multi for patterns, empty patterns match nothing
+func NewMulti(patterns ...string) *Multi {
+	mu := &Multi{prefixes: make(map[uint32][]int)}
+	for _, p := range patterns {
+		mu.patterns = append(mu.patterns, []byte(p))
+		if p == "" {
+			continue
+		}
+		if mu.window == 0 || len(p) < mu.window {
+			mu.window = len(p)
+		}
+		if len(p) > mu.maxLen {
+			mu.maxLen = len(p)
+		}
+	}
+	for i, p := range mu.patterns {
+		if len(p) == 0 {
+			continue
+		}
+		h, pow := hash(p[:mu.window])
+		mu.pow = pow
+		mu.prefixes[h] = append(mu.prefixes[h], i)
+		f := h % filterBits
+		mu.filter[f/64] |= 1 << (f % 64)
+	}
+	return mu
+}
+
+// FindAllPatterns returns all matches in text sorted by offset then pattern, overlapping matches included
+func (mu *Multi) FindAllPatterns(text []byte) []Match {
+	var ret []Match
+	mu.find(text, func(offset, pattern int) {
+		ret = append(ret, Match{Offset: offset, Pattern: pattern})
+	})
+	return ret
+}
+
+// FindAll returns offsets where any pattern matches in text in ascending order, every offset once
+func (mu *Multi) FindAll(text []byte) []int {
+	var ret []int
+	mu.find(text, func(offset, pattern int) {
+		if len(ret) == 0 || ret[len(ret)-1] != offset {
+			ret = append(ret, offset)
+		}
+	})
+	return ret
+}
+
+// FindAllReader returns offsets where any pattern matches in r in ascending order, every offset once
+func (mu *Multi) FindAllReader(r io.Reader) ([]int64, error) {
+	return matcher.FindAllReader(r, mu.maxLen, mu.FindAll)
+}
+
+// find calls f for every match by offset then pattern
+func (mu *Multi) find(text []byte, f func(offset, pattern int)) {
+	w, n := mu.window, len(text)
+	if w == 0 || n < w {
+		return
+	}
+	h, _ := hash(text[:w])
+	for i := 0; ; i++ {
+		if b := h % filterBits; mu.filter[b/64]&(1<<(b%64)) != 0 {
+			// candidates are in ascending order, patterns with different prefixes may collide
+			for _, p := range mu.prefixes[h] {
+				if pattern := mu.patterns[p]; i+len(pattern) <= n && bytes.Equal(text[i:i+len(pattern)], pattern) {
+					f(i, p)
+				}
+			}
+		}
+		if i+w == n {
+			return
+		}
+		h = h*prime + uint32(text[i+w]) - mu.pow*uint32(text[i])
+	}
+}
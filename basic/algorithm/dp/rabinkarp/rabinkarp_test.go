@@ -0,0 +1,41 @@
+package rabinkarp
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRabinKarp(t *testing.T) {
+	rk := New("ABABC")
+	assert.Equal(t, []int{5, 10, 18}, rk.FindAll([]byte("ABABDABABCABABCABCABABC")))
+	assert.Equal(t, []int{0, 1, 2}, New("aa").FindAll([]byte("aaaa")))
+	assert.Equal(t, []int{3}, New("世界").FindAll([]byte("世世界")))
+	assert.Equal(t, []int{0}, New("abc").FindAll([]byte("abc")))
+	assert.Empty(t, New("").FindAll([]byte("abc")))
+	assert.Empty(t, New("abc").FindAll([]byte("ab")))
+}
+
+func TestMulti(t *testing.T) {
+	mu := NewMulti("he", "she", "his", "hers", "")
+	text := []byte("ushers his")
+	assert.Equal(t, []Match{{1, 1}, {2, 0}, {2, 3}, {7, 2}}, mu.FindAllPatterns(text))
+	assert.Equal(t, []int{1, 2, 7}, mu.FindAll(text))
+	// a pattern longer than the rest of text
+	assert.Equal(t, []Match{{0, 0}}, NewMulti("ab", "abcd").FindAllPatterns([]byte("abc")))
+	assert.Empty(t, NewMulti().FindAll(text))
+	assert.Empty(t, NewMulti("").FindAll(text))
+	assert.Empty(t, NewMulti("ushers his!").FindAll(text))
+}
+
+func TestHash(t *testing.T) {
+	// rolling equals hashing from scratch
+	text := []byte("the quick brown fox jumps over the lazy dog")
+	for m := 1; m < 10; m++ {
+		h, pow := hash(text[:m])
+		for i := 0; i+m < len(text); i++ {
+			h = h*prime + uint32(text[i+m]) - pow*uint32(text[i])
+			want, _ := hash(text[i+1 : i+1+m])
+			assert.Equal(t, want, h)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+// Package quantile estimates quantiles of unbounded streams in bounded memory
+//	https://arxiv.org/pdf/1902.04023.pdf
+package quantile
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// DefaultCompression keeps about 120 centroids, for 1e5 values rank errors are about 2e-3 around the median
+	// and 3e-4 at p99 and p999
+	DefaultCompression = 200
+	// bufferFactor times compression values are buffered before being merged into centroids
+	bufferFactor = 5
+)
+
+// centroid is the mean of weight values
+type centroid struct {
+	mean, weight float64
+}
+
+// TDigest is a merging t-digest
+//	values are buffered, sorted and merged into centroids whose weights are bounded by the k1 scale function,
+//	k(q) = compression / 2π * asin(2q - 1), so centroids near q = 0 and q = 1 stay small and tail quantiles like p99
+//	and p999 are accurate, memory is O(compression) whatever the number of values
+//	notice: not thread-safe
+type TDigest struct {
+	compression float64
+	centroids   []centroid
+	// weight of centroids
+	weight   float64
+	buffer   []centroid
+	min, max float64
+}
+
+// NewTDigest creates a t-digest, compression <= 0 means DefaultCompression
+func NewTDigest(compression float64) *TDigest {
+	if compression <= 0 {
+		compression = DefaultCompression
+	}
+	td := &TDigest{compression: compression}
+	td.Reset()
+	return td
+}
+
+// Reset drops all values
+func (td *TDigest) Reset() {
+	td.centroids = td.centroids[:0]
+	td.buffer = td.buffer[:0]
+	td.weight = 0
+	td.min, td.max = math.Inf(1), math.Inf(-1)
+}
+
+// Add adds value x, NaN is ignored
+func (td *TDigest) Add(x float64) {
+	td.AddWeighted(x, 1)
+}
+
+// AddWeighted adds value x counted weight times, NaN and weight <= 0 are ignored
+func (td *TDigest) AddWeighted(x, weight float64) {
+	if math.IsNaN(x) || !(weight > 0) {
+		return
+	}
+	td.buffer = append(td.buffer, centroid{mean: x, weight: weight})
+	if x < td.min {
+		td.min = x
+	}
+	if x > td.max {
+		td.max = x
+	}
+	if len(td.buffer) >= int(bufferFactor*td.compression) {
+		td.compress()
+	}
+}
+
+// Merge adds all values of other into td, other is not modified
+//	digests of several goroutines or hosts can be merged for global quantiles
+func (td *TDigest) Merge(other *TDigest) {
+	for _, c := range other.centroids {
+		td.AddWeighted(c.mean, c.weight)
+	}
+	for _, c := range other.buffer {
+		td.AddWeighted(c.mean, c.weight)
+	}
+	// centroid means lose extremes
+	if other.min < td.min {
+		td.min = other.min
+	}
+	if other.max > td.max {
+		td.max = other.max
+	}
+}
+
+// Count returns total weight of values added
+func (td *TDigest) Count() float64 {
+	count := td.weight
+	for _, c := range td.buffer {
+		count += c.weight
+	}
+	return count
+}
+
+// Min returns the smallest value added, +Inf if empty
+func (td *TDigest) Min() float64 {
+	return td.min
+}
+
+// Max returns the greatest value added, -Inf if empty
+func (td *TDigest) Max() float64 {
+	return td.max
+}
+
+// Centroids returns number of centroids, which bounds memory
+func (td *TDigest) Centroids() int {
+	td.compress()
+	return len(td.centroids)
+}
+
+// compress merges buffer into centroids
+func (td *TDigest) compress() {
+	if len(td.buffer) == 0 {
+		return
+	}
+	total := td.weight
+	for _, c := range td.buffer {
+		total += c.weight
+	}
+	// all reuses buffer
+	all := append(td.buffer, td.centroids...)
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].mean < all[j].mean
+	})
+
+	merged := make([]centroid, 0, len(td.centroids)+1)
+	merged = append(merged, all[0])
+	// weight before the last merged centroid
+	before := 0.0
+	limit := td.qLimit(0)
+	for _, c := range all[1:] {
+		last := &merged[len(merged)-1]
+		if (before+last.weight+c.weight)/total <= limit {
+			last.weight += c.weight
+			last.mean += (c.mean - last.mean) * c.weight / last.weight
+			continue
+		}
+		before += last.weight
+		limit = td.qLimit(before / total)
+		merged = append(merged, c)
+	}
+
+	td.centroids = merged
+	td.weight = total
+	td.buffer = td.buffer[:0]
+}
+
+// qLimit returns the greatest quantile a centroid starting at quantile q may reach, k(limit) = k(q) + 1
+func (td *TDigest) qLimit(q float64) float64 {
+	k := td.compression/(2*math.Pi)*math.Asin(2*q-1) + 1
+	if k >= td.compression/4 {
+		return 1
+	}
+	return (math.Sin(k*2*math.Pi/td.compression) + 1) / 2
+}
+
+// Quantile returns the estimated value below which q of values fall, q is in [0, 1], NaN if empty
+//	values are interpolated linearly between centers of adjacent centroids, min and max are exact
+func (td *TDigest) Quantile(q float64) float64 {
+	td.compress()
+	n := len(td.centroids)
+	if n == 0 || q < 0 || q > 1 || math.IsNaN(q) {
+		return math.NaN()
+	}
+	if q == 0 {
+		return td.min
+	}
+	if q == 1 {
+		return td.max
+	}
+	target := q * td.weight
+
+	// between min and center of the first centroid
+	first := td.centroids[0]
+	if target < first.weight/2 {
+		return td.min + (first.mean-td.min)*target/(first.weight/2)
+	}
+	before := 0.0
+	for i := 0; i < n-1; i++ {
+		c, next := td.centroids[i], td.centroids[i+1]
+		left := before + c.weight/2
+		right := before + c.weight + next.weight/2
+		if target <= right {
+			return c.mean + (next.mean-c.mean)*(target-left)/(right-left)
+		}
+		before += c.weight
+	}
+	// between center of the last centroid and max
+	last := td.centroids[n-1]
+	left := td.weight - last.weight/2
+	return last.mean + (td.max-last.mean)*(target-left)/(last.weight/2)
+}
+
+// CDF returns the estimated fraction of values <= x, NaN if empty
+//	it is the inverse of Quantile, e.g. fraction of requests served within an SLA
+func (td *TDigest) CDF(x float64) float64 {
+	td.compress()
+	n := len(td.centroids)
+	if n == 0 || math.IsNaN(x) {
+		return math.NaN()
+	}
+	if x < td.min {
+		return 0
+	}
+	if x >= td.max {
+		return 1
+	}
+
+	first := td.centroids[0]
+	if x < first.mean {
+		return (x - td.min) / (first.mean - td.min) * first.weight / 2 / td.weight
+	}
+	before := 0.0
+	for i := 0; i < n-1; i++ {
+		c, next := td.centroids[i], td.centroids[i+1]
+		if x < next.mean {
+			left := before + c.weight/2
+			right := before + c.weight + next.weight/2
+			return (left + (right-left)*(x-c.mean)/(next.mean-c.mean)) / td.weight
+		}
+		before += c.weight
+	}
+	last := td.centroids[n-1]
+	left := td.weight - last.weight/2
+	return (left + last.weight/2*(x-last.mean)/(td.max-last.mean)) / td.weight
+}
@@ -0,0 +1,181 @@
+package quantile
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// distributions returns n values of different shapes
+func distributions(n int) map[string][]float64 {
+	res := map[string][]float64{}
+	gen := map[string]func(i int) float64{
+		"uniform":     func(i int) float64 { return rand.Float64() },
+		"normal":      func(i int) float64 { return rand.NormFloat64() },
+		"exponential": func(i int) float64 { return rand.ExpFloat64() },
+		// latencies have long tails
+		"lognormal": func(i int) float64 { return math.Exp(rand.NormFloat64() * 2) },
+		// worst case for merging digests
+		"sorted":   func(i int) float64 { return float64(i) },
+		"reversed": func(i int) float64 { return float64(n - i) },
+		"discrete": func(i int) float64 { return float64(rand.Intn(10)) },
+	}
+	for name, f := range gen {
+		values := make([]float64, n)
+		for i := range values {
+			values[i] = f(i)
+		}
+		res[name] = values
+	}
+	return res
+}
+
+// rank returns fraction of sorted values <= x
+func rank(sorted []float64, x float64) float64 {
+	return float64(sort.Search(len(sorted), func(i int) bool { return sorted[i] > x })) / float64(len(sorted))
+}
+
+// tolerances of ranks by quantile, centroids near the tails hold fewer values
+var tolerances = map[float64]float64{
+	0.001: 0.0005,
+	0.01:  0.001,
+	0.1:   0.005,
+	0.25:  0.01,
+	0.5:   0.01,
+	0.75:  0.01,
+	0.9:   0.005,
+	0.99:  0.001,
+	0.999: 0.0005,
+}
+
+func TestTDigest_Quantile(t *testing.T) {
+	n := 100000
+	for name, values := range distributions(n) {
+		td := NewTDigest(0)
+		for _, v := range values {
+			td.Add(v)
+		}
+		sorted := append([]float64(nil), values...)
+		sort.Float64s(sorted)
+
+		if td.Count() != float64(n) || td.Min() != sorted[0] || td.Max() != sorted[n-1] {
+			t.Fatal(name)
+		}
+		if c := td.Centroids(); c > 2*DefaultCompression {
+			t.Fatal(name, c)
+		}
+		if td.Quantile(0) != sorted[0] || td.Quantile(1) != sorted[n-1] {
+			t.Fatal(name)
+		}
+		for q, tolerance := range tolerances {
+			x := td.Quantile(q)
+			// discrete values cover ranges of ranks
+			lo := rank(sorted, math.Nextafter(x, math.Inf(-1)))
+			hi := rank(sorted, x)
+			if q < lo-tolerance || q > hi+tolerance {
+				t.Fatal(name, q, x, lo, hi)
+			}
+		}
+	}
+}
+
+func TestTDigest_CDF(t *testing.T) {
+	n := 100000
+	for name, values := range distributions(n) {
+		if name == "discrete" {
+			continue
+		}
+		td := NewTDigest(0)
+		for _, v := range values {
+			td.Add(v)
+		}
+		sorted := append([]float64(nil), values...)
+		sort.Float64s(sorted)
+		for q, tolerance := range tolerances {
+			x := sorted[int(q*float64(n))]
+			if cdf := td.CDF(x); math.Abs(cdf-q) > tolerance {
+				t.Fatal(name, q, cdf)
+			}
+		}
+		if td.CDF(sorted[0]-1) != 0 || td.CDF(sorted[n-1]) != 1 {
+			t.Fatal(name)
+		}
+	}
+}
+
+func TestTDigest_Merge(t *testing.T) {
+	n := 100000
+	values := distributions(n)["lognormal"]
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	// one digest per shard
+	merged := NewTDigest(0)
+	for shard := 0; shard < 10; shard++ {
+		td := NewTDigest(0)
+		for _, v := range values[shard*n/10 : (shard+1)*n/10] {
+			td.Add(v)
+		}
+		merged.Merge(td)
+	}
+	if merged.Count() != float64(n) || merged.Min() != sorted[0] || merged.Max() != sorted[n-1] {
+		t.Fatal(merged.Count())
+	}
+	for _, q := range []float64{0.5, 0.99, 0.999} {
+		if r := rank(sorted, merged.Quantile(q)); math.Abs(r-q) > 0.005 {
+			t.Fatal(q, r)
+		}
+	}
+}
+
+func TestTDigest_Edge(t *testing.T) {
+	td := NewTDigest(50)
+	if !math.IsNaN(td.Quantile(0.5)) || !math.IsNaN(td.CDF(0)) || td.Count() != 0 {
+		t.Fail()
+	}
+	td.Add(math.NaN())
+	td.AddWeighted(1, 0)
+	td.AddWeighted(1, -1)
+	if td.Count() != 0 {
+		t.Fail()
+	}
+
+	td.Add(5)
+	if td.Quantile(0.5) != 5 || td.Quantile(0.01) != 5 || td.CDF(4) != 0 || td.CDF(5) != 1 {
+		t.Fatal(td.Quantile(0.5))
+	}
+	if !math.IsNaN(td.Quantile(-0.1)) || !math.IsNaN(td.Quantile(1.1)) {
+		t.Fail()
+	}
+
+	td.AddWeighted(10, 3)
+	if td.Count() != 4 || td.Quantile(0.9) != 10 || td.Quantile(0.1) != 5 {
+		t.Fatal(td.Quantile(0.9), td.Quantile(0.1))
+	}
+
+	td.Reset()
+	if td.Count() != 0 || td.Centroids() != 0 || !math.IsInf(td.Min(), 1) {
+		t.Fail()
+	}
+}
+
+func BenchmarkTDigest_Add(b *testing.B) {
+	td := NewTDigest(0)
+	values := distributions(1 << 16)["lognormal"]
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		td.Add(values[i&(1<<16-1)])
+	}
+}
+
+func BenchmarkTDigest_Quantile(b *testing.B) {
+	td := NewTDigest(0)
+	for _, v := range distributions(1 << 16)["lognormal"] {
+		td.Add(v)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		td.Quantile(0.99)
+	}
+}
@@ -0,0 +1,129 @@
+package quickselect
+
+import (
+	"sort"
+)
+
+const (
+	// selectInsertionLen is the length under which ranges are sorted by insertion sort
+	selectInsertionLen = 12
+	// selectWorkFactor bounds elements partitioned with median of three pivots to a multiple of n
+	selectWorkFactor = 3
+)
+
+// NthElement rearranges data so that data[k] is the element which would be there if data were sorted,
+// data[:k] <= data[k] <= data[k+1:]
+//	notice: k starts from 0, nothing happens if k is out of range
+//	introselect: quick select with median of three pivots and three way partitioning (so duplicates are cheap),
+//	when partitions stop shrinking fast enough it switches to median of medians pivots, so it is O(n) even for
+//	adversarial data
+//	https://en.wikipedia.org/wiki/Introselect
+func NthElement(data sort.Interface, k int) {
+	n := data.Len()
+	if k < 0 || k >= n {
+		return
+	}
+	nthElement(data, 0, n, k, false)
+}
+
+// Select is deterministic selection with median of medians pivots, it rearranges data like NthElement
+//	notice: k starts from 0, returns k or -1 if k is out of range
+//	https://en.wikipedia.org/wiki/Median_of_medians
+//	time complexity O(n) in the worst case, slower than NthElement on average
+func Select(data sort.Interface, k int) (idx int) {
+	n := data.Len()
+	if k < 0 || k >= n {
+		return -1
+	}
+	nthElement(data, 0, n, k, true)
+	return k
+}
+
+// nthElement selects k inside data[lo, hi)
+func nthElement(data sort.Interface, lo, hi, k int, deterministic bool) {
+	// once partitioned elements add up to limit, median of three pivots are considered defeated
+	limit := selectWorkFactor * (hi - lo)
+	work := 0
+	for hi-lo > selectInsertionLen {
+		var pivot int
+		if deterministic || work > limit {
+			pivot = medianOfMediansPivot(data, lo, hi)
+		} else {
+			quarter := (hi - lo) / 4
+			pivot = medianOfThree(data, lo+quarter, lo+2*quarter, lo+3*quarter)
+		}
+		work += hi - lo
+		lt, gt := partition3(data, lo, hi, pivot)
+		switch {
+		case k < lt:
+			hi = lt
+		case k >= gt:
+			lo = gt
+		default:
+			// data[k] equals to pivot
+			return
+		}
+	}
+	insertionSort(data, lo, hi)
+}
+
+// partition3 partitions data[lo, hi) around data[pivot] with Dijkstra's three way partitioning
+//	returns lt, gt, data[lo, lt) < pivot, data[lt, gt) == pivot, data[gt, hi) > pivot
+func partition3(data sort.Interface, lo, hi, pivot int) (lt, gt int) {
+	data.Swap(lo, pivot)
+	// data[lt] is always equal to pivot
+	lt, gt = lo, hi
+	for i := lo + 1; i < gt; {
+		if data.Less(i, lt) {
+			data.Swap(lt, i)
+			lt++
+			i++
+		} else if data.Less(lt, i) {
+			gt--
+			data.Swap(i, gt)
+		} else {
+			i++
+		}
+	}
+	return lt, gt
+}
+
+// medianOfMediansPivot returns index of an element of data[lo, hi) with at least 3/10 of elements on each side
+//	medians of groups of 5 are moved to the front, then their median is selected recursively
+func medianOfMediansPivot(data sort.Interface, lo, hi int) int {
+	groups := 0
+	for i := lo; i < hi; i += 5 {
+		end := i + 5
+		if end > hi {
+			end = hi
+		}
+		insertionSort(data, i, end)
+		data.Swap(lo+groups, i+(end-i)/2)
+		groups++
+	}
+	mid := lo + groups/2
+	nthElement(data, lo, lo+groups, mid, true)
+	return mid
+}
+
+// medianOfThree returns index of the median of data[a], data[b], data[c]
+func medianOfThree(data sort.Interface, a, b, c int) int {
+	if data.Less(b, a) {
+		a, b = b, a
+	}
+	if data.Less(c, b) {
+		b = c
+		if data.Less(b, a) {
+			b = a
+		}
+	}
+	return b
+}
+
+func insertionSort(data sort.Interface, lo, hi int) {
+	for i := lo + 1; i < hi; i++ {
+		for j := i; j > lo && data.Less(j, j-1); j-- {
+			data.Swap(j, j-1)
+		}
+	}
+}
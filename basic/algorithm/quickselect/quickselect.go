@@ -78,21 +78,25 @@ func medianOfMedians(arrInt []int, k, r int) int {
 	pivot := medianOfMedians(medians, (len(medians)+1)/2, r)
 
 	var leftSide, rightSide []int
+	// elements equal to pivot, not only pivot itself
+	equal := 0
 
 	for i := range arrInt {
 		if arrInt[i] < pivot {
 			leftSide = append(leftSide, arrInt[i])
 		} else if arrInt[i] > pivot {
 			rightSide = append(rightSide, arrInt[i])
+		} else {
+			equal++
 		}
 	}
 
 	switch {
-	case k == (len(leftSide) + 1):
-		return pivot
 	case k <= len(leftSide):
 		return medianOfMedians(leftSide, k, r)
+	case k <= len(leftSide)+equal:
+		return pivot
 	default:
-		return medianOfMedians(rightSide, k-len(leftSide)-1, r)
+		return medianOfMedians(rightSide, k-len(leftSide)-equal, r)
 	}
 }
@@ -44,3 +44,122 @@ func BenchmarkQuickSelect(b *testing.B) {
 		}
 	})
 }
+
+func TestMOM_Duplicates(t *testing.T) {
+	arrInt := make([]int, 100)
+	for i := range arrInt {
+		arrInt[i] = i % 3
+	}
+	for k := 0; k < len(arrInt); k++ {
+		assert.Equal(t, k*3/len(arrInt), MOM(append([]int(nil), arrInt...), k))
+	}
+}
+
+// selectInputs returns inputs of length n with different distributions
+func selectInputs(n int) map[string]sort.IntSlice {
+	res := map[string]sort.IntSlice{
+		"random":   make(sort.IntSlice, n),
+		"sorted":   make(sort.IntSlice, n),
+		"reversed": make(sort.IntSlice, n),
+		"equal":    make(sort.IntSlice, n),
+		"few":      make(sort.IntSlice, n),
+		"organ":    make(sort.IntSlice, n),
+	}
+	for i := 0; i < n; i++ {
+		res["random"][i] = rand.Int()
+		res["sorted"][i] = i
+		res["reversed"][i] = n - i
+		res["equal"][i] = 7
+		res["few"][i] = rand.Intn(4)
+		if i < n/2 {
+			res["organ"][i] = i
+		} else {
+			res["organ"][i] = n - i
+		}
+	}
+	return res
+}
+
+func checkNth(t *testing.T, data, sorted sort.IntSlice, k int) {
+	assert.Equal(t, sorted[k], data[k])
+	for i := 0; i < k; i++ {
+		assert.True(t, data[i] <= data[k])
+	}
+	for i := k + 1; i < len(data); i++ {
+		assert.True(t, data[i] >= data[k])
+	}
+}
+
+func TestNthElement(t *testing.T) {
+	for _, n := range []int{1, 2, 5, 13, 100, 1000} {
+		for name, data := range selectInputs(n) {
+			sorted := append(sort.IntSlice(nil), data...)
+			sort.Sort(sorted)
+			for _, k := range []int{0, n / 4, n / 2, n - 1, rand.Intn(n)} {
+				a := append(sort.IntSlice(nil), data...)
+				NthElement(a, k)
+				checkNth(t, a, sorted, k)
+
+				b := append(sort.IntSlice(nil), data...)
+				assert.Equal(t, k, Select(b, k), name)
+				checkNth(t, b, sorted, k)
+			}
+		}
+	}
+
+	// out of range
+	is := sort.IntSlice{3, 2, 1}
+	NthElement(is, 3)
+	NthElement(is, -1)
+	assert.Equal(t, sort.IntSlice{3, 2, 1}, is)
+	assert.Equal(t, -1, Select(is, 3))
+	assert.Equal(t, -1, Select(sort.IntSlice{}, 0))
+}
+
+// counter counts calls of Less
+type counter struct {
+	sort.IntSlice
+	less int
+}
+
+func (c *counter) Less(i, j int) bool {
+	c.less++
+	return c.IntSlice.Less(i, j)
+}
+
+// TestNthElement_Linear checks comparisons grow linearly on data defeating median of three
+func TestNthElement_Linear(t *testing.T) {
+	for _, n := range []int{1000, 10000, 100000} {
+		for name, data := range selectInputs(n) {
+			a := &counter{IntSlice: append(sort.IntSlice(nil), data...)}
+			NthElement(a, n/2)
+			b := &counter{IntSlice: append(sort.IntSlice(nil), data...)}
+			Select(b, n/2)
+			assert.True(t, a.less < 30*n, name, n, a.less)
+			assert.True(t, b.less < 30*n, name, n, b.less)
+		}
+	}
+}
+
+func BenchmarkNthElement(b *testing.B) {
+	data := selectInputs(1 << 16)["random"]
+	a := make(sort.IntSlice, len(data))
+	for _, bench := range []struct {
+		name string
+		f    func(data sort.Interface, k int)
+	}{
+		{"NthElement", NthElement},
+		{"Select", func(data sort.Interface, k int) { Select(data, k) }},
+		{"QuickSelect", func(data sort.Interface, k int) { QuickSelect(data, k) }},
+		{"Sort", func(data sort.Interface, k int) { sort.Sort(data) }},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				copy(a, data)
+				b.StartTimer()
+				bench.f(a, len(a)/2)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+// Package external sorts record streams larger than memory
+//	records are read from an io.Reader into runs of bounded size, every run is sorted in memory and spilled to a temp
+//	file, then runs are k-way merged with a heap into an io.Writer
+//	https://en.wikipedia.org/wiki/External_sorting
+package external
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"godev/basic/algorithm/sort/parallel"
+	"godev/basic/datastructure/heap/dary"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+const (
+	// DefaultMaxRunBytes is the default memory budget of one in-memory run
+	DefaultMaxRunBytes = 64 << 20
+	// DefaultMaxRecordSize is the default size limit of one record
+	DefaultMaxRecordSize = 1 << 20
+	// DefaultFanIn is the default number of runs merged at once
+	DefaultFanIn = 64
+)
+
+// recordOverhead approximates memory used per record besides its bytes
+const recordOverhead = 16
+
+// ErrFanIn is returned when Options.FanIn is 1
+var ErrFanIn = errors.New("external: fan-in must be at least 2")
+
+// Options of external sorting, zero values mean defaults
+type Options struct {
+	// Split splits input into records, bufio.ScanLines if nil
+	Split bufio.SplitFunc
+	// Delimiter is written after every record, "\n" if nil
+	Delimiter []byte
+	// Less compares two records, bytes.Compare(a, b) < 0 if nil
+	Less func(a, b []byte) bool
+	// MaxRunBytes is the memory budget of one run, DefaultMaxRunBytes if <= 0
+	MaxRunBytes int
+	// MaxRecordSize is the size limit of one record, DefaultMaxRecordSize if <= 0
+	MaxRecordSize int
+	// FanIn is the maximum number of runs merged at once, more runs are merged in several passes, DefaultFanIn if 0
+	FanIn int
+	// TempDir is the directory for run files, os.TempDir() if empty
+	TempDir string
+	// Workers sorts runs in memory with parallel sample sort, see parallel.Options
+	Workers int
+}
+
+// Sorter sorts records, equal records keep their input order
+type Sorter struct {
+	split         bufio.SplitFunc
+	delimiter     []byte
+	less          func(a, b []byte) bool
+	maxRunBytes   int
+	maxRecordSize int
+	fanIn         int
+	tempDir       string
+	workers       int
+}
+
+// NewSorter creates a sorter with opts
+func NewSorter(opts Options) *Sorter {
+	s := &Sorter{
+		split:         opts.Split,
+		delimiter:     opts.Delimiter,
+		less:          opts.Less,
+		maxRunBytes:   opts.MaxRunBytes,
+		maxRecordSize: opts.MaxRecordSize,
+		fanIn:         opts.FanIn,
+		tempDir:       opts.TempDir,
+		workers:       opts.Workers,
+	}
+	if s.split == nil {
+		s.split = bufio.ScanLines
+	}
+	if s.delimiter == nil {
+		s.delimiter = []byte{'\n'}
+	}
+	if s.less == nil {
+		s.less = func(a, b []byte) bool {
+			return bytes.Compare(a, b) < 0
+		}
+	}
+	if s.maxRunBytes <= 0 {
+		s.maxRunBytes = DefaultMaxRunBytes
+	}
+	if s.maxRecordSize <= 0 {
+		s.maxRecordSize = DefaultMaxRecordSize
+	}
+	if s.fanIn == 0 {
+		s.fanIn = DefaultFanIn
+	}
+	return s
+}
+
+// Sort sorts records of r into w with opts
+func Sort(w io.Writer, r io.Reader, opts Options) error {
+	return NewSorter(opts).Sort(w, r)
+}
+
+// Sort reads all records of r, sorts them and writes them to w followed by delimiter
+//	input which fits in one run is never written to disk, temp files are removed before Sort returns
+func (s *Sorter) Sort(w io.Writer, r io.Reader) error {
+	if s.fanIn < 2 {
+		return ErrFanIn
+	}
+	temps := &tempFiles{dir: s.tempDir}
+	defer temps.removeAll()
+	// run files are closed once written and opened again by merge
+	var files []string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Split(s.split)
+	buf := s.maxRecordSize
+	if buf > 64<<10 {
+		buf = 64 << 10
+	}
+	scanner.Buffer(make([]byte, buf), s.maxRecordSize)
+
+	rn := &run{less: s.less}
+	for {
+		more := scanner.Scan()
+		if more {
+			rn.add(scanner.Bytes())
+		}
+		if rn.size() < s.maxRunBytes && more {
+			continue
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		parallel.SampleSort(rn, parallel.Options{Workers: s.workers})
+		// everything fits in memory
+		if !more && len(files) == 0 {
+			out := &recordWriter{w: bufio.NewWriter(w), delimiter: s.delimiter}
+			for i := range rn.records {
+				if err := out.write(rn.record(i)); err != nil {
+					return err
+				}
+			}
+			return out.flush()
+		}
+		if rn.Len() > 0 {
+			name, err := temps.create(func(f *os.File) error {
+				return spill(f, rn)
+			})
+			if err != nil {
+				return err
+			}
+			files = append(files, name)
+		}
+		if !more {
+			break
+		}
+		rn.reset()
+	}
+
+	// every merge opens at most fanIn runs, plus its output file in intermediate passes
+	for len(files) > s.fanIn {
+		var merged []string
+		for i := 0; i < len(files); i += s.fanIn {
+			end := i + s.fanIn
+			if end > len(files) {
+				end = len(files)
+			}
+			name, err := temps.create(func(f *os.File) error {
+				return s.merge(newRunWriter(f), files[i:end])
+			})
+			if err != nil {
+				return err
+			}
+			merged = append(merged, name)
+		}
+		for _, name := range files {
+			temps.remove(name)
+		}
+		files = merged
+	}
+	return s.merge(&recordWriter{w: bufio.NewWriter(w), delimiter: s.delimiter}, files)
+}
+
+// spill writes sorted run into f
+func spill(f *os.File, rn *run) error {
+	rw := newRunWriter(f)
+	for i := range rn.records {
+		if err := rw.write(rn.record(i)); err != nil {
+			return err
+		}
+	}
+	return rw.flush()
+}
+
+// tempFiles tracks names of run files of one Sort call
+type tempFiles struct {
+	dir   string
+	names map[string]struct{}
+}
+
+// create creates a run file, writes it with fill and closes it, returns its name
+func (tf *tempFiles) create(fill func(f *os.File) error) (string, error) {
+	f, err := ioutil.TempFile(tf.dir, "extsort-")
+	if err != nil {
+		return "", err
+	}
+	if tf.names == nil {
+		tf.names = make(map[string]struct{})
+	}
+	tf.names[f.Name()] = struct{}{}
+	err = fill(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return f.Name(), err
+}
+
+func (tf *tempFiles) remove(name string) {
+	_ = os.Remove(name)
+	delete(tf.names, name)
+}
+
+func (tf *tempFiles) removeAll() {
+	for name := range tf.names {
+		tf.remove(name)
+	}
+}
+
+// merge merges run files into out, ties are broken by file order so that merging is stable
+//	files are open only while they are merged
+func (s *Sorter) merge(out writer, files []string) error {
+	h := dary.NewHeap(dary.DefaultArity)
+	for i, name := range files {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		c := &cursor{idx: i, r: newRunReader(f), less: s.less}
+		ok, err := c.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			h.Insert(c)
+		}
+	}
+	for !h.Empty() {
+		c := h.DeleteMin().(*cursor)
+		if err := out.write(c.record); err != nil {
+			return err
+		}
+		ok, err := c.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			h.Insert(c)
+		}
+	}
+	return out.flush()
+}
@@ -0,0 +1,238 @@
+package external
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func tempDir(t testing.TB) (string, func()) {
+	dir, err := ioutil.TempDir("", "extsort")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, func() {
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func checkEmpty(t *testing.T, dir string) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Fatal("temp files left", len(files))
+	}
+}
+
+func randomLines(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		b := make([]byte, 1+rand.Intn(40))
+		for j := range b {
+			b[j] = byte('a' + rand.Intn(26))
+		}
+		lines[i] = string(b)
+	}
+	return lines
+}
+
+func TestSort(t *testing.T) {
+	dir, clean := tempDir(t)
+	defer clean()
+
+	lines := randomLines(20000)
+	input := strings.Join(lines, "\n")
+	sort.Strings(lines)
+	expected := strings.Join(lines, "\n") + "\n"
+
+	for _, opts := range []Options{
+		// one run in memory
+		{TempDir: dir},
+		// one merge pass
+		{TempDir: dir, MaxRunBytes: 32 << 10},
+		// several merge passes
+		{TempDir: dir, MaxRunBytes: 4 << 10, FanIn: 3, Workers: 2},
+	} {
+		var out bytes.Buffer
+		if err := Sort(&out, strings.NewReader(input), opts); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != expected {
+			t.Fatal("unsorted output", opts.MaxRunBytes, opts.FanIn)
+		}
+		checkEmpty(t, dir)
+	}
+
+	// empty input
+	var out bytes.Buffer
+	if err := Sort(&out, strings.NewReader(""), Options{TempDir: dir}); err != nil || out.Len() != 0 {
+		t.Fail()
+	}
+}
+
+// openFiles returns number of open file descriptors of the process, -1 if unknown
+func openFiles() int {
+	fds, err := ioutil.ReadDir("/proc/self/fd")
+	if err != nil {
+		return -1
+	}
+	return len(fds)
+}
+
+func TestSort_OpenFiles(t *testing.T) {
+	base := openFiles()
+	if base < 0 {
+		t.Skip("/proc/self/fd is not available")
+	}
+	dir, clean := tempDir(t)
+	defer clean()
+
+	lines := randomLines(20000)
+	input := strings.Join(lines, "\n")
+	sort.Strings(lines)
+	expected := strings.Join(lines, "\n") + "\n"
+
+	// sample open files while merging, hundreds of runs are spilled
+	var mu sync.Mutex
+	calls, maxOpen := 0, 0
+	less := func(a, b []byte) bool {
+		mu.Lock()
+		if calls++; calls%100 == 0 {
+			if n := openFiles(); n > maxOpen {
+				maxOpen = n
+			}
+		}
+		mu.Unlock()
+		return bytes.Compare(a, b) < 0
+	}
+	var out bytes.Buffer
+	if err := Sort(&out, strings.NewReader(input), Options{TempDir: dir, MaxRunBytes: 1 << 10, FanIn: 4, Less: less}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != expected {
+		t.Fatal("unsorted output")
+	}
+	// fanIn runs, the output run and the directory read by openFiles
+	if maxOpen-base > 4+1+1 {
+		t.Fatal("open files", maxOpen-base)
+	}
+	checkEmpty(t, dir)
+}
+
+func TestSort_Stable(t *testing.T) {
+	dir, clean := tempDir(t)
+	defer clean()
+
+	// key,seq records, compared by key only
+	var input bytes.Buffer
+	for i := 0; i < 10000; i++ {
+		fmt.Fprintf(&input, "%03d,%05d\n", rand.Intn(50), i)
+	}
+	key := func(b []byte) []byte {
+		return b[:bytes.IndexByte(b, ',')]
+	}
+	s := NewSorter(Options{
+		Less: func(a, b []byte) bool {
+			return bytes.Compare(key(a), key(b)) < 0
+		},
+		MaxRunBytes: 2 << 10,
+		FanIn:       4,
+		TempDir:     dir,
+	})
+	var out bytes.Buffer
+	if err := s.Sort(&out, &input); err != nil {
+		t.Fatal(err)
+	}
+	scanner := bufio.NewScanner(&out)
+	prev, n := "", 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		if prev != "" && (line[:3] < prev[:3] || line[:3] == prev[:3] && line[4:] < prev[4:]) {
+			t.Fatal(prev, line)
+		}
+		prev = line
+		n++
+	}
+	if n != 10000 {
+		t.Fatal(n)
+	}
+	checkEmpty(t, dir)
+}
+
+func TestSort_Split(t *testing.T) {
+	dir, clean := tempDir(t)
+	defer clean()
+
+	var out bytes.Buffer
+	err := Sort(&out, strings.NewReader("pear apple\nfig  banana\tcherry"), Options{
+		Split:       bufio.ScanWords,
+		Delimiter:   []byte(" "),
+		MaxRunBytes: 40,
+		TempDir:     dir,
+	})
+	if err != nil || out.String() != "apple banana cherry fig pear " {
+		t.Fatal(err, out.String())
+	}
+}
+
+type errReader struct {
+	data []byte
+}
+
+var errRead = errors.New("read error")
+
+func (r *errReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, errRead
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestSort_Errors(t *testing.T) {
+	dir, clean := tempDir(t)
+	defer clean()
+
+	var out bytes.Buffer
+	if err := Sort(&out, strings.NewReader("a"), Options{FanIn: 1}); err != ErrFanIn {
+		t.Fail()
+	}
+
+	long := strings.Repeat("x", 100)
+	if err := Sort(&out, strings.NewReader("a\n"+long+"\nb"), Options{MaxRecordSize: 50}); err != bufio.ErrTooLong {
+		t.Fatal(err)
+	}
+
+	// reader fails after some runs were spilled
+	input := []byte(strings.Join(randomLines(1000), "\n"))
+	if err := Sort(&out, &errReader{data: input}, Options{MaxRunBytes: 1 << 10, TempDir: dir}); err != errRead {
+		t.Fatal(err)
+	}
+	checkEmpty(t, dir)
+}
+
+// BenchmarkSort sorts 1M lines with 4MB runs
+func BenchmarkSort(b *testing.B) {
+	dir, clean := tempDir(b)
+	defer clean()
+
+	input := []byte(strings.Join(randomLines(1<<20), "\n"))
+	b.SetBytes(int64(len(input)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := Sort(ioutil.Discard, bytes.NewReader(input), Options{MaxRunBytes: 4 << 20, TempDir: dir}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
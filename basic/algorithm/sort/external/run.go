@@ -0,0 +1,172 @@
+package external
+
+import (
+	"bufio"
+	"encoding/binary"
+	"godev/basic/datastructure/heap"
+	"io"
+)
+
+// run holds records of one in-memory run in a single arena
+//	records are offsets into the arena, so sorting swaps two ints and growing the arena keeps them valid,
+//	offsets grow with input order, so breaking ties by offset makes any sorting algorithm stable
+type run struct {
+	arena   []byte
+	records []span
+	less    func(a, b []byte) bool
+}
+
+type span struct {
+	start, end int
+}
+
+func (rn *run) add(record []byte) {
+	start := len(rn.arena)
+	rn.arena = append(rn.arena, record...)
+	rn.records = append(rn.records, span{start: start, end: len(rn.arena)})
+}
+
+func (rn *run) record(i int) []byte {
+	return rn.arena[rn.records[i].start:rn.records[i].end]
+}
+
+// size returns approximate memory used by records
+func (rn *run) size() int {
+	return len(rn.arena) + len(rn.records)*recordOverhead
+}
+
+func (rn *run) reset() {
+	rn.arena = rn.arena[:0]
+	rn.records = rn.records[:0]
+}
+
+func (rn *run) Len() int {
+	return len(rn.records)
+}
+
+func (rn *run) Less(i, j int) bool {
+	a, b := rn.record(i), rn.record(j)
+	if rn.less(a, b) {
+		return true
+	}
+	if rn.less(b, a) {
+		return false
+	}
+	return rn.records[i].start < rn.records[j].start
+}
+
+func (rn *run) Swap(i, j int) {
+	rn.records[i], rn.records[j] = rn.records[j], rn.records[i]
+}
+
+// writer writes merged records
+type writer interface {
+	write(record []byte) error
+	flush() error
+}
+
+// recordWriter writes records followed by delimiter to the final output
+type recordWriter struct {
+	w         *bufio.Writer
+	delimiter []byte
+}
+
+func (rw *recordWriter) write(record []byte) error {
+	if _, err := rw.w.Write(record); err != nil {
+		return err
+	}
+	_, err := rw.w.Write(rw.delimiter)
+	return err
+}
+
+func (rw *recordWriter) flush() error {
+	return rw.w.Flush()
+}
+
+// runWriter writes length prefixed records to a run file, so records may contain any byte
+type runWriter struct {
+	w   *bufio.Writer
+	buf [binary.MaxVarintLen64]byte
+}
+
+func newRunWriter(w io.Writer) *runWriter {
+	return &runWriter{w: bufio.NewWriter(w)}
+}
+
+func (rw *runWriter) write(record []byte) error {
+	n := binary.PutUvarint(rw.buf[:], uint64(len(record)))
+	if _, err := rw.w.Write(rw.buf[:n]); err != nil {
+		return err
+	}
+	_, err := rw.w.Write(record)
+	return err
+}
+
+func (rw *runWriter) flush() error {
+	return rw.w.Flush()
+}
+
+type runReader struct {
+	r *bufio.Reader
+}
+
+func newRunReader(r io.Reader) *runReader {
+	return &runReader{r: bufio.NewReader(r)}
+}
+
+// read reads next record into buf, io.EOF at the end of run
+func (rr *runReader) read(buf []byte) ([]byte, error) {
+	n, err := binary.ReadUvarint(rr.r)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(cap(buf)) < n {
+		buf = make([]byte, n)
+	}
+	buf = buf[:n]
+	if _, err := io.ReadFull(rr.r, buf); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return buf, nil
+}
+
+// cursor is the current record of a run inside merge heap
+type cursor struct {
+	record []byte
+	idx    int
+	r      *runReader
+	less   func(a, b []byte) bool
+}
+
+// next advances to the next record, false at the end of run
+func (c *cursor) next() (bool, error) {
+	record, err := c.r.read(c.record)
+	if err == io.EOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	c.record = record
+	return true, nil
+}
+
+// Compare to meet heap.Item, records from earlier runs go first when equal
+func (c *cursor) Compare(item heap.Item) int {
+	other := item.(*cursor)
+	if c.less(c.record, other.record) {
+		return -1
+	}
+	if c.less(other.record, c.record) {
+		return 1
+	}
+	if c.idx < other.idx {
+		return -1
+	} else if c.idx > other.idx {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,181 @@
+package parallel
+
+import (
+	sort2 "godev/basic/algorithm/sort"
+	"sort"
+)
+
+// MergeSort sorts data stably in place with parallel merge sort
+//	both halves are sorted concurrently, then merged by SymMerge whose two sub-merges run concurrently as well,
+//	no extra memory is needed, O(n*log(n)) calls to Less and O(n*log(n)*log(n)) calls to Swap
+//	notice: Less and Swap are called concurrently on disjoint index ranges
+func MergeSort(data sort2.Interface, opts Options) {
+	p := newPool(opts)
+	p.stable(data, 0, data.Len())
+}
+
+func (p *pool) stable(data sort2.Interface, a, b int) {
+	if b-a <= p.cutoff {
+		sort.Stable(subInterface{data: data, off: a, n: b - a})
+		return
+	}
+	m := int(uint(a+b) >> 1)
+	p.both(func() { p.stable(data, a, m) }, func() { p.stable(data, m, b) })
+	p.symMerge(data, a, m, b)
+}
+
+// symMerge merges sorted data[a:m] and data[m:b] in place
+//	Pok-Son Kim and Arne Kutzner, "Stable Minimum Storage Merging by Symmetric Comparisons", same as the official one
+func (p *pool) symMerge(data sort2.Interface, a, m, b int) {
+	// insert data[a] into data[m:b]
+	if m-a == 1 {
+		i, j := m, b
+		for i < j {
+			h := int(uint(i+j) >> 1)
+			if data.Less(h, a) {
+				i = h + 1
+			} else {
+				j = h
+			}
+		}
+		for k := a; k < i-1; k++ {
+			data.Swap(k, k+1)
+		}
+		return
+	}
+	// insert data[m] into data[a:m]
+	if b-m == 1 {
+		i, j := a, m
+		for i < j {
+			h := int(uint(i+j) >> 1)
+			if !data.Less(m, h) {
+				i = h + 1
+			} else {
+				j = h
+			}
+		}
+		for k := m; k > i; k-- {
+			data.Swap(k, k-1)
+		}
+		return
+	}
+
+	mid := int(uint(a+b) >> 1)
+	n := mid + m
+	var start, r int
+	if m > mid {
+		start, r = n-b, mid
+	} else {
+		start, r = a, m
+	}
+	last := n - 1
+	for start < r {
+		c := int(uint(start+r) >> 1)
+		if !data.Less(last-c, c) {
+			start = c + 1
+		} else {
+			r = c
+		}
+	}
+	end := n - start
+	if start < m && m < end {
+		rotate(data, start, m, end)
+	}
+
+	left := func() {
+		if a < start && start < mid {
+			p.symMerge(data, a, start, mid)
+		}
+	}
+	right := func() {
+		if mid < end && end < b {
+			p.symMerge(data, mid, end, b)
+		}
+	}
+	if b-a <= p.cutoff {
+		left()
+		right()
+		return
+	}
+	p.both(left, right)
+}
+
+// swapRange swaps data[a:a+n] with data[b:b+n]
+func swapRange(data sort2.Interface, a, b, n int) {
+	for i := 0; i < n; i++ {
+		data.Swap(a+i, b+i)
+	}
+}
+
+// rotate rotates data[a:b] so that data[m:b] goes before data[a:m]
+func rotate(data sort2.Interface, a, m, b int) {
+	i, j := m-a, b-m
+	for i != j {
+		if i > j {
+			swapRange(data, m-i, m, j)
+			i -= j
+		} else {
+			swapRange(data, m-i, m+j-i, i)
+			j -= i
+		}
+	}
+	swapRange(data, m-i, m, i)
+}
+
+// MergeSortInts sorts data with parallel merge sort using a buffer of the same size
+//	halves are sorted concurrently into alternating buffers, then merged by a parallel merge which splits the
+//	larger run at its median and binary searches the position in the other run
+func MergeSortInts(data sort2.IntSlice, opts Options) {
+	p := newPool(opts)
+	buf := make([]int, len(data))
+	p.mergeSortInts(data, buf, false)
+}
+
+// mergeSortInts sorts src, the result lands in buf if toBuf, otherwise in src
+func (p *pool) mergeSortInts(src, buf []int, toBuf bool) {
+	if len(src) <= p.cutoff {
+		sort.Ints(src)
+		if toBuf {
+			copy(buf, src)
+		}
+		return
+	}
+	m := len(src) / 2
+	p.both(func() { p.mergeSortInts(src[:m], buf[:m], !toBuf) }, func() { p.mergeSortInts(src[m:], buf[m:], !toBuf) })
+	if toBuf {
+		p.mergeInts(src[:m], src[m:], buf)
+	} else {
+		p.mergeInts(buf[:m], buf[m:], src)
+	}
+}
+
+// mergeInts merges sorted a and b into out
+func (p *pool) mergeInts(a, b, out []int) {
+	if len(a)+len(b) <= p.cutoff {
+		mergeInts(a, b, out)
+		return
+	}
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	i := len(a) / 2
+	j := sort.SearchInts(b, a[i])
+	out[i+j] = a[i]
+	p.both(func() { p.mergeInts(a[:i], b[:j], out[:i+j]) }, func() { p.mergeInts(a[i+1:], b[j:], out[i+j+1:]) })
+}
+
+func mergeInts(a, b, out []int) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if b[j] < a[i] {
+			out[k] = b[j]
+			j++
+		} else {
+			out[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(out[k:], a[i:])
+	copy(out[k:], b[j:])
+}
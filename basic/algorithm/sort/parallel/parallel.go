@@ -0,0 +1,125 @@
+// Package parallel implements multi-goroutine merge sort and sample sort
+//	both split work into independent tasks down to Options.Cutoff elements, tasks are run by at most Options.Workers
+//	goroutines, a task which finds no idle worker is simply run by its caller, so recursion never blocks
+package parallel
+
+import (
+	sort2 "godev/basic/algorithm/sort"
+	"runtime"
+	"sync"
+)
+
+// DefaultCutoff is the default size under which sub-slices are sorted sequentially
+const DefaultCutoff = 1 << 13
+
+// minCutoff keeps tiny cutoffs from spawning a task per element
+const minCutoff = 16
+
+// Options of parallel sorting
+type Options struct {
+	// Workers is the maximum number of goroutines sorting at the same time, runtime.GOMAXPROCS(0) if <= 0
+	Workers int
+	// Cutoff is the size under which sub-slices are sorted sequentially, DefaultCutoff if <= 0
+	Cutoff int
+}
+
+func (o Options) workers() int {
+	if o.Workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return o.Workers
+}
+
+func (o Options) cutoff() int {
+	if o.Cutoff <= 0 {
+		return DefaultCutoff
+	}
+	if o.Cutoff < minCutoff {
+		return minCutoff
+	}
+	return o.Cutoff
+}
+
+// pool runs tasks on at most cap(sem) extra goroutines
+type pool struct {
+	sem    chan struct{}
+	cutoff int
+}
+
+func newPool(opts Options) *pool {
+	return &pool{
+		// the calling goroutine is a worker as well
+		sem:    make(chan struct{}, opts.workers()-1),
+		cutoff: opts.cutoff(),
+	}
+}
+
+// spawn runs f on an idle worker, or on the caller if all workers are busy
+func (p *pool) spawn(wg *sync.WaitGroup, f func()) {
+	select {
+	case p.sem <- struct{}{}:
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-p.sem
+				wg.Done()
+			}()
+			f()
+		}()
+	default:
+		f()
+	}
+}
+
+// both runs f and g concurrently if possible and waits for them
+func (p *pool) both(f, g func()) {
+	var wg sync.WaitGroup
+	p.spawn(&wg, f)
+	g()
+	wg.Wait()
+}
+
+// each runs f(i) for i in [0, n) on the pool and waits for them
+func (p *pool) each(n int, f func(i int)) {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		i := i
+		p.spawn(&wg, func() { f(i) })
+	}
+	wg.Wait()
+}
+
+// chunks splits [0, n) into at most workers ranges of at least cutoff elements
+func (p *pool) chunks(n int) [][2]int {
+	k := cap(p.sem) + 1
+	if max := (n + p.cutoff - 1) / p.cutoff; k > max {
+		k = max
+	}
+	if k < 1 {
+		k = 1
+	}
+	ranges := make([][2]int, k)
+	for i := range ranges {
+		ranges[i] = [2]int{i * n / k, (i + 1) * n / k}
+	}
+	return ranges
+}
+
+// subInterface is a view of data[off:off+n]
+type subInterface struct {
+	data sort2.Interface
+	off  int
+	n    int
+}
+
+func (s subInterface) Len() int {
+	return s.n
+}
+
+func (s subInterface) Less(i, j int) bool {
+	return s.data.Less(s.off+i, s.off+j)
+}
+
+func (s subInterface) Swap(i, j int) {
+	s.data.Swap(s.off+i, s.off+j)
+}
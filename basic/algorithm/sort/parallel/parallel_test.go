@@ -0,0 +1,165 @@
+package parallel
+
+import (
+	sort2 "godev/basic/algorithm/sort"
+	"godev/basic/algorithm/sort/stable/compare/merge"
+	"godev/basic/algorithm/sort/stable/linear/radix"
+	"godev/basic/algorithm/sort/unstable/compare/heap"
+	"godev/basic/algorithm/sort/unstable/compare/quick"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// inputs returns slices of length n with different distributions
+func inputs(n int) map[string]sort2.IntSlice {
+	res := map[string]sort2.IntSlice{
+		"random":   make(sort2.IntSlice, n),
+		"sorted":   make(sort2.IntSlice, n),
+		"reversed": make(sort2.IntSlice, n),
+		"equal":    make(sort2.IntSlice, n),
+		"few":      make(sort2.IntSlice, n),
+		"negative": make(sort2.IntSlice, n),
+	}
+	for i := 0; i < n; i++ {
+		res["random"][i] = rand.Int()
+		res["sorted"][i] = i
+		res["reversed"][i] = n - i
+		res["equal"][i] = 7
+		res["few"][i] = rand.Intn(4)
+		res["negative"][i] = rand.Intn(2*n+1) - n
+	}
+	return res
+}
+
+var sorts = map[string]func(data sort2.IntSlice, opts Options){
+	"MergeSort":      func(data sort2.IntSlice, opts Options) { MergeSort(data, opts) },
+	"MergeSortInts":  MergeSortInts,
+	"SampleSort":     func(data sort2.IntSlice, opts Options) { SampleSort(data, opts) },
+	"SampleSortInts": SampleSortInts,
+}
+
+func TestSorts(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 15, 100, 1000, 10000} {
+		for dist, data := range inputs(n) {
+			expected := append(sort2.IntSlice(nil), data...)
+			sort.Ints(expected)
+			for name, f := range sorts {
+				for _, opts := range []Options{{}, {Workers: 1, Cutoff: 16}, {Workers: 3, Cutoff: 50}, {Workers: 8, Cutoff: 1}} {
+					a := append(sort2.IntSlice(nil), data...)
+					f(a, opts)
+					if !sort2.Equal(a, expected) {
+						t.Fatal(name, dist, n, opts)
+					}
+				}
+			}
+		}
+	}
+}
+
+type record struct {
+	key, idx int
+}
+
+type records []record
+
+func (rs records) Len() int {
+	return len(rs)
+}
+
+func (rs records) Less(i, j int) bool {
+	return rs[i].key < rs[j].key
+}
+
+func (rs records) Swap(i, j int) {
+	rs[i], rs[j] = rs[j], rs[i]
+}
+
+func TestMergeSort_Stable(t *testing.T) {
+	rs := make(records, 20000)
+	for i := range rs {
+		rs[i] = record{key: rand.Intn(100), idx: i}
+	}
+	MergeSort(rs, Options{Workers: 4, Cutoff: 64})
+	for i := 1; i < len(rs); i++ {
+		if rs[i-1].key > rs[i].key || rs[i-1].key == rs[i].key && rs[i-1].idx > rs[i].idx {
+			t.Fatal(i, rs[i-1], rs[i])
+		}
+	}
+}
+
+func TestOptions(t *testing.T) {
+	var opts Options
+	if opts.workers() < 1 || opts.cutoff() != DefaultCutoff {
+		t.Fail()
+	}
+	opts = Options{Workers: 3, Cutoff: 2}
+	if opts.workers() != 3 || opts.cutoff() != minCutoff {
+		t.Fail()
+	}
+	p := newPool(Options{Workers: 4, Cutoff: 100})
+	if len(p.chunks(50)) != 1 || len(p.chunks(1000)) != 4 {
+		t.Fail()
+	}
+	covered := 0
+	for _, c := range p.chunks(1001) {
+		covered += c[1] - c[0]
+	}
+	if covered != 1001 {
+		t.Fail()
+	}
+}
+
+const benchSize = 1 << 20
+
+func benchmarkSort(b *testing.B, f func(data sort2.IntSlice)) {
+	data := make(sort2.IntSlice, benchSize)
+	a := make(sort2.IntSlice, benchSize)
+	for i := range data {
+		// non-negative for radix sort
+		data[i] = rand.Int()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		copy(a, data)
+		b.StartTimer()
+		f(a)
+	}
+}
+
+func BenchmarkMergeSort(b *testing.B) {
+	benchmarkSort(b, func(data sort2.IntSlice) { MergeSort(data, Options{}) })
+}
+
+func BenchmarkMergeSortInts(b *testing.B) {
+	benchmarkSort(b, func(data sort2.IntSlice) { MergeSortInts(data, Options{}) })
+}
+
+func BenchmarkSampleSort(b *testing.B) {
+	benchmarkSort(b, func(data sort2.IntSlice) { SampleSort(data, Options{}) })
+}
+
+func BenchmarkSampleSortInts(b *testing.B) {
+	benchmarkSort(b, func(data sort2.IntSlice) { SampleSortInts(data, Options{}) })
+}
+
+func BenchmarkSequentialMerge(b *testing.B) {
+	benchmarkSort(b, func(data sort2.IntSlice) { merge.Sort(data) })
+}
+
+func BenchmarkSequentialQuick(b *testing.B) {
+	benchmarkSort(b, func(data sort2.IntSlice) { quick.Sort(data) })
+}
+
+func BenchmarkSequentialHeap(b *testing.B) {
+	benchmarkSort(b, func(data sort2.IntSlice) { heap.Sort(data) })
+}
+
+func BenchmarkSequentialRadix(b *testing.B) {
+	benchmarkSort(b, func(data sort2.IntSlice) { radix.Sort(data) })
+}
+
+func BenchmarkOfficialSort(b *testing.B) {
+	benchmarkSort(b, func(data sort2.IntSlice) { sort.Sort(data) })
+}
@@ -0,0 +1,206 @@
+package parallel
+
+import (
+	sort2 "godev/basic/algorithm/sort"
+	"math/rand"
+	"sort"
+)
+
+const (
+	// oversampling is the number of samples drawn per bucket
+	oversampling = 16
+	// bucketsPerWorker gives idle workers something to steal when buckets are uneven
+	bucketsPerWorker = 4
+	// maxBuckets keeps bucket ids (splitters and equality buckets) inside uint16
+	maxBuckets = 1 << 14
+)
+
+// SampleSort sorts data in place with parallel sample sort, not stable
+//	k-1 splitters are picked from a random sample, every element is classified concurrently into one of 2k-1 buckets
+//	(k ranges between splitters plus k-1 buckets of elements equal to a splitter, so heavy duplicates need no sorting),
+//	elements are permuted into their buckets in place and the buckets are sorted concurrently
+//	it needs 2 bytes per element for bucket ids
+//	notice: Less is called concurrently on any indexes, Swap on disjoint index ranges
+func SampleSort(data sort2.Interface, opts Options) {
+	p := newPool(opts)
+	n := data.Len()
+	k := p.buckets(n)
+	if k < 2 {
+		sort.Sort(data)
+		return
+	}
+
+	// splitters are indexes of data, they stay valid since nothing moves until classification is done
+	r := rand.New(rand.NewSource(int64(n)))
+	sample := make([]int, k*oversampling)
+	for i := range sample {
+		sample[i] = r.Intn(n)
+	}
+	sort.Slice(sample, func(i, j int) bool {
+		return data.Less(sample[i], sample[j])
+	})
+	splitters := make([]int, k-1)
+	for i := range splitters {
+		splitters[i] = sample[(i+1)*oversampling]
+	}
+
+	classify := func(i int) int {
+		lo, hi := 0, len(splitters)
+		for lo < hi {
+			h := int(uint(lo+hi) >> 1)
+			if data.Less(i, splitters[h]) {
+				hi = h
+			} else {
+				lo = h + 1
+			}
+		}
+		// data[i] == splitters[lo-1]
+		if lo > 0 && !data.Less(splitters[lo-1], i) {
+			return 2*lo - 1
+		}
+		return 2 * lo
+	}
+
+	ids := make([]uint16, n)
+	chunks := p.chunks(n)
+	counts := make([][]int, len(chunks))
+	p.each(len(chunks), func(c int) {
+		cnt := make([]int, 2*k-1)
+		for i := chunks[c][0]; i < chunks[c][1]; i++ {
+			id := classify(i)
+			ids[i] = uint16(id)
+			cnt[id]++
+		}
+		counts[c] = cnt
+	})
+
+	// bucket b is data[starts[b]:starts[b+1]]
+	starts := make([]int, 2*k)
+	for b := 0; b < 2*k-1; b++ {
+		starts[b+1] = starts[b]
+		for c := range counts {
+			starts[b+1] += counts[c][b]
+		}
+	}
+
+	// american flag permutation, bucket ids travel with their elements
+	next := make([]int, 2*k-1)
+	copy(next, starts)
+	for b := range next {
+		for next[b] < starts[b+1] {
+			i := next[b]
+			id := int(ids[i])
+			if id == b {
+				next[b]++
+				continue
+			}
+			j := next[id]
+			data.Swap(i, j)
+			ids[i], ids[j] = ids[j], ids[i]
+			next[id]++
+		}
+	}
+
+	// odd buckets hold elements equal to a splitter
+	p.each(k, func(b int) {
+		lo, hi := starts[2*b], starts[2*b+1]
+		if hi-lo > 1 {
+			sort.Sort(subInterface{data: data, off: lo, n: hi - lo})
+		}
+	})
+}
+
+// buckets returns number of splitter ranges for n elements, < 2 means sorting sequentially
+func (p *pool) buckets(n int) int {
+	k := (cap(p.sem) + 1) * bucketsPerWorker
+	if max := n / p.cutoff; k > max {
+		k = max
+	}
+	if k > maxBuckets {
+		k = maxBuckets
+	}
+	return k
+}
+
+// SampleSortInts sorts data with parallel sample sort using a buffer of the same size
+//	chunks are classified and scattered into the buffer concurrently, then buckets are sorted and copied back concurrently
+func SampleSortInts(data sort2.IntSlice, opts Options) {
+	p := newPool(opts)
+	n := len(data)
+	k := p.buckets(n)
+	if k < 2 {
+		sort.Ints(data)
+		return
+	}
+
+	r := rand.New(rand.NewSource(int64(n)))
+	sample := make([]int, k*oversampling)
+	for i := range sample {
+		sample[i] = data[r.Intn(n)]
+	}
+	sort.Ints(sample)
+	splitters := make([]int, k-1)
+	for i := range splitters {
+		splitters[i] = sample[(i+1)*oversampling]
+	}
+
+	classify := func(x int) int {
+		lo, hi := 0, len(splitters)
+		for lo < hi {
+			h := int(uint(lo+hi) >> 1)
+			if x < splitters[h] {
+				hi = h
+			} else {
+				lo = h + 1
+			}
+		}
+		if lo > 0 && splitters[lo-1] == x {
+			return 2*lo - 1
+		}
+		return 2 * lo
+	}
+
+	chunks := p.chunks(n)
+	counts := make([][]int, len(chunks))
+	p.each(len(chunks), func(c int) {
+		cnt := make([]int, 2*k-1)
+		for _, x := range data[chunks[c][0]:chunks[c][1]] {
+			cnt[classify(x)]++
+		}
+		counts[c] = cnt
+	})
+
+	// offsets[c][b] is where chunk c writes its first element of bucket b
+	starts := make([]int, 2*k)
+	offsets := make([][]int, len(chunks))
+	for c := range offsets {
+		offsets[c] = make([]int, 2*k-1)
+	}
+	off := 0
+	for b := 0; b < 2*k-1; b++ {
+		starts[b] = off
+		for c := range chunks {
+			offsets[c][b] = off
+			off += counts[c][b]
+		}
+	}
+	starts[2*k-1] = off
+
+	buf := make([]int, n)
+	p.each(len(chunks), func(c int) {
+		next := offsets[c]
+		for _, x := range data[chunks[c][0]:chunks[c][1]] {
+			b := classify(x)
+			buf[next[b]] = x
+			next[b]++
+		}
+	})
+
+	p.each(2*k-1, func(b int) {
+		bucket := buf[starts[b]:starts[b+1]]
+		if b%2 == 0 {
+			sort.Ints(bucket)
+		}
+		copy(data[starts[b]:], bucket)
+	})
+}
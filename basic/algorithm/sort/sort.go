@@ -60,3 +60,19 @@ func Equal(is1, is2 IntSlice) bool {
 
 	return true
 }
+
+// Permute moves element at index perm[i] to index i for every i with Swap only, perm is reset to identity
+//	it follows cycles of the permutation, so it takes at most n-1 swaps,
+//	sorts computing a permutation (e.g. from extracted keys) use it to reorder data behind Interface
+func Permute(data Interface, perm []int) {
+	for i := range perm {
+		j := i
+		for perm[j] != i {
+			k := perm[j]
+			perm[j] = j
+			data.Swap(j, k)
+			j = k
+		}
+		perm[j] = j
+	}
+}
@@ -29,3 +29,22 @@ func TestEqual(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestPermute(t *testing.T) {
+	is := IntSlice{10, 11, 12, 13, 14, 15}
+	perm := []int{3, 0, 5, 1, 2, 4}
+	Permute(is, perm)
+	if !Equal(is, IntSlice{13, 10, 15, 11, 12, 14}) {
+		t.Fatal(is)
+	}
+	for i := range perm {
+		if perm[i] != i {
+			t.Fatal(perm)
+		}
+	}
+
+	Permute(is, []int{0, 1, 2, 3, 4, 5})
+	if !Equal(is, IntSlice{13, 10, 15, 11, 12, 14}) {
+		t.Fatal(is)
+	}
+}
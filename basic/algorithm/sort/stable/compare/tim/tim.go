@@ -0,0 +1,493 @@
+package tim
+
+import (
+	"godev/basic"
+)
+
+const (
+	// minMerge is the length under which data is sorted by binary insertion sort only
+	minMerge = 32
+	// minGallop is the initial number of consecutive wins of one run that switches merging into galloping mode
+	minGallop = 7
+)
+
+// Sort implements Timsort, stable
+//	https://en.wikipedia.org/wiki/Timsort
+//	https://github.com/python/cpython/blob/main/Objects/listsort.txt
+//	notice: this is slice type specified, merging needs a buffer of at most len(values)/2 elements
+//	natural runs are detected (strictly descending ones are reversed), short runs are extended by binary insertion sort,
+//	runs are pushed on a stack whose lengths grow like fibonacci numbers and merged with galloping,
+//	sorted or reversed data takes n-1 comparisons and data made of a few runs takes O(n)
+func Sort(values []interface{}, comparator basic.Comparator) {
+	n := len(values)
+	if n < 2 {
+		return
+	}
+	if n < minMerge {
+		r := countRun(values, comparator)
+		binaryInsertionSort(values, r, comparator)
+		return
+	}
+
+	ts := &timSort{values: values, comparator: comparator, minGallop: minGallop}
+	minRun := minRunLength(n)
+	for lo := 0; lo < n; {
+		r := countRun(values[lo:], comparator)
+		if r < minRun {
+			force := minRun
+			if force > n-lo {
+				force = n - lo
+			}
+			binaryInsertionSort(values[lo:lo+force], r, comparator)
+			r = force
+		}
+		ts.runs = append(ts.runs, run{base: lo, len: r})
+		ts.mergeCollapse()
+		lo += r
+	}
+	ts.mergeForceCollapse()
+}
+
+// minRunLength returns the minimum run length in [minMerge/2, minMerge], n/minRun is a power of 2 or a bit less
+func minRunLength(n int) int {
+	r := 0
+	for n >= minMerge {
+		r |= n & 1
+		n >>= 1
+	}
+	return n + r
+}
+
+// countRun returns length of the run at the beginning of values, a strictly descending run is reversed
+//	descending must be strict to keep sorting stable
+func countRun(values []interface{}, comparator basic.Comparator) int {
+	n := len(values)
+	if n < 2 {
+		return n
+	}
+	hi := 2
+	if comparator(values[1], values[0]) < 0 {
+		for hi < n && comparator(values[hi], values[hi-1]) < 0 {
+			hi++
+		}
+		for i, j := 0, hi-1; i < j; i, j = i+1, j-1 {
+			values[i], values[j] = values[j], values[i]
+		}
+	} else {
+		for hi < n && comparator(values[hi], values[hi-1]) >= 0 {
+			hi++
+		}
+	}
+	return hi
+}
+
+// binaryInsertionSort sorts values whose first sorted elements are already sorted
+func binaryInsertionSort(values []interface{}, sorted int, comparator basic.Comparator) {
+	if sorted == 0 {
+		sorted = 1
+	}
+	for i := sorted; i < len(values); i++ {
+		pivot := values[i]
+		// insert after equal elements for stability
+		lo, hi := 0, i
+		for lo < hi {
+			mid := int(uint(lo+hi) >> 1)
+			if comparator(pivot, values[mid]) < 0 {
+				hi = mid
+			} else {
+				lo = mid + 1
+			}
+		}
+		copy(values[lo+1:i+1], values[lo:i])
+		values[lo] = pivot
+	}
+}
+
+// gallopLeft returns the leftmost index to insert key into sorted values, values[k-1] < key <= values[k]
+//	searching starts at hint by exponential steps, then binary search narrows down the last step
+func gallopLeft(key interface{}, values []interface{}, hint int, comparator basic.Comparator) int {
+	n := len(values)
+	last, ofs := 0, 1
+	if comparator(key, values[hint]) > 0 {
+		// values[hint+last] < key <= values[hint+ofs]
+		max := n - hint
+		for ofs < max && comparator(key, values[hint+ofs]) > 0 {
+			last = ofs
+			ofs = ofs<<1 + 1
+		}
+		if ofs > max {
+			ofs = max
+		}
+		last, ofs = last+hint, ofs+hint
+	} else {
+		// values[hint-ofs] < key <= values[hint-last]
+		max := hint + 1
+		for ofs < max && comparator(key, values[hint-ofs]) <= 0 {
+			last = ofs
+			ofs = ofs<<1 + 1
+		}
+		if ofs > max {
+			ofs = max
+		}
+		last, ofs = hint-ofs, hint-last
+	}
+
+	// values[last] < key <= values[ofs]
+	last++
+	for last < ofs {
+		mid := last + (ofs-last)>>1
+		if comparator(key, values[mid]) > 0 {
+			last = mid + 1
+		} else {
+			ofs = mid
+		}
+	}
+	return ofs
+}
+
+// gallopRight returns the rightmost index to insert key into sorted values, values[k-1] <= key < values[k]
+func gallopRight(key interface{}, values []interface{}, hint int, comparator basic.Comparator) int {
+	n := len(values)
+	last, ofs := 0, 1
+	if comparator(key, values[hint]) < 0 {
+		// values[hint-ofs] <= key < values[hint-last]
+		max := hint + 1
+		for ofs < max && comparator(key, values[hint-ofs]) < 0 {
+			last = ofs
+			ofs = ofs<<1 + 1
+		}
+		if ofs > max {
+			ofs = max
+		}
+		last, ofs = hint-ofs, hint-last
+	} else {
+		// values[hint+last] <= key < values[hint+ofs]
+		max := n - hint
+		for ofs < max && comparator(key, values[hint+ofs]) >= 0 {
+			last = ofs
+			ofs = ofs<<1 + 1
+		}
+		if ofs > max {
+			ofs = max
+		}
+		last, ofs = last+hint, ofs+hint
+	}
+
+	// values[last] <= key < values[ofs]
+	last++
+	for last < ofs {
+		mid := last + (ofs-last)>>1
+		if comparator(key, values[mid]) < 0 {
+			ofs = mid
+		} else {
+			last = mid + 1
+		}
+	}
+	return ofs
+}
+
+// run is values[base, base+len)
+type run struct {
+	base, len int
+}
+
+type timSort struct {
+	values     []interface{}
+	comparator basic.Comparator
+	// minGallop adapts to data, it grows when galloping does not pay off
+	minGallop int
+	runs      []run
+	buf       []interface{}
+}
+
+// mergeCollapse merges runs until the invariants hold for the last 4 runs
+//	runs[i-2].len > runs[i-1].len + runs[i].len and runs[i-1].len > runs[i].len
+//	https://envisage-project.eu/wp-content/uploads/2015/02/sorting.pdf
+func (ts *timSort) mergeCollapse() {
+	for len(ts.runs) > 1 {
+		n := len(ts.runs) - 2
+		if n > 0 && ts.runs[n-1].len <= ts.runs[n].len+ts.runs[n+1].len ||
+			n > 1 && ts.runs[n-2].len <= ts.runs[n-1].len+ts.runs[n].len {
+			if ts.runs[n-1].len < ts.runs[n+1].len {
+				n--
+			}
+		} else if ts.runs[n].len > ts.runs[n+1].len {
+			return
+		}
+		ts.mergeAt(n)
+	}
+}
+
+// mergeForceCollapse merges all runs left
+func (ts *timSort) mergeForceCollapse() {
+	for len(ts.runs) > 1 {
+		n := len(ts.runs) - 2
+		if n > 0 && ts.runs[n-1].len < ts.runs[n+1].len {
+			n--
+		}
+		ts.mergeAt(n)
+	}
+}
+
+// mergeAt merges runs[i] with runs[i+1]
+func (ts *timSort) mergeAt(i int) {
+	base1, len1 := ts.runs[i].base, ts.runs[i].len
+	base2, len2 := ts.runs[i+1].base, ts.runs[i+1].len
+	ts.runs[i].len = len1 + len2
+	copy(ts.runs[i+1:], ts.runs[i+2:])
+	ts.runs = ts.runs[:len(ts.runs)-1]
+
+	// elements of run1 before the first element of run2 are in place
+	k := gallopRight(ts.values[base2], ts.values[base1:base1+len1], 0, ts.comparator)
+	base1 += k
+	len1 -= k
+	if len1 == 0 {
+		return
+	}
+	// elements of run2 after the last element of run1 are in place
+	len2 = gallopLeft(ts.values[base1+len1-1], ts.values[base2:base2+len2], len2-1, ts.comparator)
+	if len2 == 0 {
+		return
+	}
+
+	if len1 <= len2 {
+		ts.mergeLo(base1, len1, base2, len2)
+	} else {
+		ts.mergeHi(base1, len1, base2, len2)
+	}
+}
+
+func (ts *timSort) buffer(n int) []interface{} {
+	if cap(ts.buf) < n {
+		ts.buf = make([]interface{}, n)
+	}
+	return ts.buf[:n]
+}
+
+// mergeLo merges adjacent runs from left to right with run1 copied into buffer, len1 <= len2
+//	first element of run2 < first element of run1, last element of run1 > all elements of run2
+func (ts *timSort) mergeLo(base1, len1, base2, len2 int) {
+	values, comparator := ts.values, ts.comparator
+	tmp := ts.buffer(len1)
+	copy(tmp, values[base1:base1+len1])
+
+	c1, c2, dest := 0, base2, base1
+	values[dest] = values[c2]
+	dest++
+	c2++
+	len2--
+	if len2 == 0 {
+		copy(values[dest:], tmp[c1:c1+len1])
+		return
+	}
+	if len1 == 1 {
+		copy(values[dest:], values[c2:c2+len2])
+		values[dest+len2] = tmp[c1]
+		return
+	}
+
+	gallop := ts.minGallop
+outer:
+	for {
+		// count of consecutive wins of each run
+		count1, count2 := 0, 0
+		for count1|count2 < gallop {
+			if comparator(values[c2], tmp[c1]) < 0 {
+				values[dest] = values[c2]
+				dest++
+				c2++
+				count2++
+				count1 = 0
+				len2--
+				if len2 == 0 {
+					break outer
+				}
+			} else {
+				values[dest] = tmp[c1]
+				dest++
+				c1++
+				count1++
+				count2 = 0
+				len1--
+				if len1 == 1 {
+					break outer
+				}
+			}
+		}
+
+		// one run keeps winning, gallop
+		for {
+			count1 = gallopRight(values[c2], tmp[c1:c1+len1], 0, comparator)
+			if count1 != 0 {
+				copy(values[dest:], tmp[c1:c1+count1])
+				dest += count1
+				c1 += count1
+				len1 -= count1
+				if len1 <= 1 {
+					break outer
+				}
+			}
+			values[dest] = values[c2]
+			dest++
+			c2++
+			len2--
+			if len2 == 0 {
+				break outer
+			}
+
+			count2 = gallopLeft(tmp[c1], values[c2:c2+len2], 0, comparator)
+			if count2 != 0 {
+				copy(values[dest:], values[c2:c2+count2])
+				dest += count2
+				c2 += count2
+				len2 -= count2
+				if len2 == 0 {
+					break outer
+				}
+			}
+			values[dest] = tmp[c1]
+			dest++
+			c1++
+			len1--
+			if len1 == 1 {
+				break outer
+			}
+
+			gallop--
+			if count1 < minGallop && count2 < minGallop {
+				break
+			}
+		}
+		if gallop < 0 {
+			gallop = 0
+		}
+		// penalize leaving galloping mode
+		gallop += 2
+	}
+	if gallop < 1 {
+		gallop = 1
+	}
+	ts.minGallop = gallop
+
+	if len1 == 1 {
+		copy(values[dest:], values[c2:c2+len2])
+		values[dest+len2] = tmp[c1]
+	} else {
+		// len1 is 0 only with an inconsistent comparator
+		copy(values[dest:], tmp[c1:c1+len1])
+	}
+}
+
+// mergeHi merges adjacent runs from right to left with run2 copied into buffer, len1 >= len2
+//	first element of run2 < first element of run1, last element of run1 > all elements of run2
+func (ts *timSort) mergeHi(base1, len1, base2, len2 int) {
+	values, comparator := ts.values, ts.comparator
+	tmp := ts.buffer(len2)
+	copy(tmp, values[base2:base2+len2])
+
+	c1, c2, dest := base1+len1-1, len2-1, base2+len2-1
+	values[dest] = values[c1]
+	dest--
+	c1--
+	len1--
+	if len1 == 0 {
+		copy(values[dest-len2+1:], tmp[:len2])
+		return
+	}
+	if len2 == 1 {
+		dest -= len1
+		c1 -= len1
+		copy(values[dest+1:], values[c1+1:c1+1+len1])
+		values[dest] = tmp[c2]
+		return
+	}
+
+	gallop := ts.minGallop
+outer:
+	for {
+		count1, count2 := 0, 0
+		for count1|count2 < gallop {
+			if comparator(tmp[c2], values[c1]) < 0 {
+				values[dest] = values[c1]
+				dest--
+				c1--
+				count1++
+				count2 = 0
+				len1--
+				if len1 == 0 {
+					break outer
+				}
+			} else {
+				values[dest] = tmp[c2]
+				dest--
+				c2--
+				count2++
+				count1 = 0
+				len2--
+				if len2 == 1 {
+					break outer
+				}
+			}
+		}
+
+		for {
+			count1 = len1 - gallopRight(tmp[c2], values[base1:base1+len1], len1-1, comparator)
+			if count1 != 0 {
+				dest -= count1
+				c1 -= count1
+				len1 -= count1
+				copy(values[dest+1:], values[c1+1:c1+1+count1])
+				if len1 == 0 {
+					break outer
+				}
+			}
+			values[dest] = tmp[c2]
+			dest--
+			c2--
+			len2--
+			if len2 == 1 {
+				break outer
+			}
+
+			count2 = len2 - gallopLeft(values[c1], tmp[:len2], len2-1, comparator)
+			if count2 != 0 {
+				dest -= count2
+				c2 -= count2
+				len2 -= count2
+				copy(values[dest+1:], tmp[c2+1:c2+1+count2])
+				if len2 <= 1 {
+					break outer
+				}
+			}
+			values[dest] = values[c1]
+			dest--
+			c1--
+			len1--
+			if len1 == 0 {
+				break outer
+			}
+
+			gallop--
+			if count1 < minGallop && count2 < minGallop {
+				break
+			}
+		}
+		if gallop < 0 {
+			gallop = 0
+		}
+		gallop += 2
+	}
+	if gallop < 1 {
+		gallop = 1
+	}
+	ts.minGallop = gallop
+
+	if len2 == 1 {
+		dest -= len1
+		c1 -= len1
+		copy(values[dest+1:], values[c1+1:c1+1+len1])
+		values[dest] = tmp[c2]
+	} else {
+		copy(values[dest-len2+1:], tmp[:len2])
+	}
+}
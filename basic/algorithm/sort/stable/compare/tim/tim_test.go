@@ -0,0 +1,243 @@
+package tim
+
+import (
+	"godev/basic"
+	"godev/utils"
+	"math/bits"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// patterns returns inputs of length n, mostly made of runs
+func patterns(n int) map[string][]int {
+	res := map[string][]int{}
+	add := func(name string, f func(i int) int) {
+		data := make([]int, n)
+		for i := range data {
+			data[i] = f(i)
+		}
+		res[name] = data
+	}
+	add("random", func(i int) int { return utils.GenerateRandomInt() })
+	add("sorted", func(i int) int { return i })
+	add("reversed", func(i int) int { return n - i })
+	add("equal", func(i int) int { return 7 })
+	add("few", func(i int) int { return rand.Intn(4) })
+	add("sawtooth", func(i int) int { return i % 100 })
+	add("reversedSawtooth", func(i int) int { return 100 - i%100 })
+	add("organPipe", func(i int) int {
+		if i < n/2 {
+			return i
+		}
+		return n - i
+	})
+	add("rotated", func(i int) int { return (i + n/3) % (n + 1) })
+	add("interleaved", func(i int) int {
+		if i%2 == 0 {
+			return i
+		}
+		return n - i
+	})
+	nearly := make([]int, n)
+	for i := range nearly {
+		nearly[i] = i
+	}
+	for i := 0; i < n/100+1 && n > 1; i++ {
+		j, k := rand.Intn(n), rand.Intn(n)
+		nearly[j], nearly[k] = nearly[k], nearly[j]
+	}
+	res["nearlySorted"] = nearly
+	// sorted with random tail appended
+	tail := make([]int, n)
+	for i := range tail {
+		if i < n*9/10 {
+			tail[i] = i
+		} else {
+			tail[i] = rand.Intn(n)
+		}
+	}
+	res["sortedRandomTail"] = tail
+	return res
+}
+
+type record struct {
+	key, idx int
+}
+
+func recordComparator(a, b interface{}) int {
+	return basic.IntComparator(a.(record).key, b.(record).key)
+}
+
+// counter counts calls of comparator
+type counter struct {
+	comparator basic.Comparator
+	n          int
+}
+
+func (c *counter) compare(a, b interface{}) int {
+	c.n++
+	return c.comparator(a, b)
+}
+
+func TestSort(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 5, 31, 32, 33, 64, 100, 1000, 10000, 100000} {
+		for name, data := range patterns(n) {
+			values := make([]interface{}, n)
+			expected := make([]int, n)
+			for i, v := range data {
+				values[i] = v
+				expected[i] = v
+			}
+			sort.Ints(expected)
+			c := &counter{comparator: basic.IntComparator}
+			Sort(values, c.compare)
+			for i := range values {
+				if values[i].(int) != expected[i] {
+					t.Fatal(name, n, i)
+				}
+			}
+			if limit := 2 * (n + 1) * (bits.Len(uint(n)) + 1); c.n > limit {
+				t.Fatal(name, n, c.n, limit)
+			}
+		}
+	}
+}
+
+func TestSort_Stable(t *testing.T) {
+	for _, n := range []int{10, 100, 1000, 100000} {
+		for _, keys := range []int{2, 10, n} {
+			for name, data := range patterns(n) {
+				values := make([]interface{}, n)
+				for i, v := range data {
+					values[i] = record{key: v % keys, idx: i}
+				}
+				Sort(values, recordComparator)
+				for i := 1; i < n; i++ {
+					prev, cur := values[i-1].(record), values[i].(record)
+					if prev.key > cur.key || prev.key == cur.key && prev.idx > cur.idx {
+						t.Fatal(name, n, keys, prev, cur)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestSort_Linear checks inputs made of few runs take O(n) comparisons
+func TestSort_Linear(t *testing.T) {
+	n := 100000
+	for name, limit := range map[string]int{
+		"sorted":   n - 1,
+		"reversed": n - 1,
+		"equal":    n - 1,
+		// two interleaving runs
+		"organPipe": 2 * n,
+		// two runs, galloping skips most of them
+		"rotated": n + 100,
+	} {
+		values := make([]interface{}, n)
+		for i, v := range patterns(n)[name] {
+			values[i] = v
+		}
+		c := &counter{comparator: basic.IntComparator}
+		Sort(values, c.compare)
+		if c.n > limit {
+			t.Fatal(name, c.n, limit)
+		}
+	}
+}
+
+// TestMergeCollapse checks run lengths on the stack after every push
+//	https://envisage-project.eu/wp-content/uploads/2015/02/sorting.pdf
+func TestMergeCollapse(t *testing.T) {
+	ts := &timSort{comparator: basic.IntComparator, minGallop: minGallop}
+	lo := 0
+	for _, l := range []int{120, 80, 25, 20, 30, 33, 16, 17, 50, 1000, 32, 32, 32, 32, 32, 33, 5} {
+		ts.values = append(ts.values, make([]interface{}, l)...)
+		for i := lo; i < lo+l; i++ {
+			ts.values[i] = i - lo
+		}
+		ts.runs = append(ts.runs, run{base: lo, len: l})
+		lo += l
+		ts.mergeCollapse()
+		for i := 2; i < len(ts.runs); i++ {
+			if ts.runs[i-2].len <= ts.runs[i-1].len+ts.runs[i].len {
+				t.Fatal(ts.runs)
+			}
+		}
+		for i := 1; i < len(ts.runs); i++ {
+			if ts.runs[i-1].len <= ts.runs[i].len {
+				t.Fatal(ts.runs)
+			}
+		}
+	}
+	ts.mergeForceCollapse()
+	if len(ts.runs) != 1 || ts.runs[0].len != lo {
+		t.Fatal(ts.runs)
+	}
+}
+
+func TestMinRunLength(t *testing.T) {
+	if minRunLength(31) != 31 || minRunLength(64) != 16 || minRunLength(65) != 17 {
+		t.Fail()
+	}
+	for n := minMerge; n < 100000; n += 97 {
+		if r := minRunLength(n); r < minMerge/2 || r > minMerge {
+			t.Fatal(n, r)
+		}
+	}
+}
+
+func TestGallop(t *testing.T) {
+	values := []interface{}{1, 2, 2, 2, 3, 5, 5, 8}
+	for hint := range values {
+		if gallopLeft(2, values, hint, basic.IntComparator) != 1 || gallopRight(2, values, hint, basic.IntComparator) != 4 {
+			t.Fatal(hint)
+		}
+		if gallopLeft(0, values, hint, basic.IntComparator) != 0 || gallopRight(9, values, hint, basic.IntComparator) != 8 {
+			t.Fatal(hint)
+		}
+		if gallopLeft(4, values, hint, basic.IntComparator) != 5 || gallopRight(4, values, hint, basic.IntComparator) != 5 {
+			t.Fatal(hint)
+		}
+	}
+}
+
+func benchmarkSort(b *testing.B, name string, f func(values []interface{})) {
+	data := patterns(1 << 16)[name]
+	values := make([]interface{}, len(data))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		for j, v := range data {
+			values[j] = v
+		}
+		b.StartTimer()
+		f(values)
+	}
+}
+
+func BenchmarkSort_Random(b *testing.B) {
+	benchmarkSort(b, "random", func(values []interface{}) { Sort(values, basic.IntComparator) })
+}
+
+func BenchmarkSort_NearlySorted(b *testing.B) {
+	benchmarkSort(b, "nearlySorted", func(values []interface{}) { Sort(values, basic.IntComparator) })
+}
+
+func BenchmarkSort_SortedRandomTail(b *testing.B) {
+	benchmarkSort(b, "sortedRandomTail", func(values []interface{}) { Sort(values, basic.IntComparator) })
+}
+
+func BenchmarkOfficialStable_Random(b *testing.B) {
+	benchmarkSort(b, "random", func(values []interface{}) { basic.SortStable(values, basic.IntComparator) })
+}
+
+func BenchmarkOfficialStable_NearlySorted(b *testing.B) {
+	benchmarkSort(b, "nearlySorted", func(values []interface{}) { basic.SortStable(values, basic.IntComparator) })
+}
+
+func BenchmarkOfficialStable_SortedRandomTail(b *testing.B) {
+	benchmarkSort(b, "sortedRandomTail", func(values []interface{}) { basic.SortStable(values, basic.IntComparator) })
+}
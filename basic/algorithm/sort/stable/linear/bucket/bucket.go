@@ -18,6 +18,9 @@ func Sort(data sort.IntSlice) {
 	// bucket sort breaks continuous data into discrete data
 	// for other bucketSize, its complexity is proportional to sort algorithm complexity used in bucket
 	bucketSize := 10 //int(math.Sqrt(float64(data.Len())))
+	if data.Len() < 2 {
+		return
+	}
 	min, max := data.MinMax()
 	bucketNum := (max-min)/bucketSize + 1
 	buckets := make([]sort.IntSlice, bucketNum)
@@ -22,6 +22,24 @@ func TestSort(t *testing.T) {
 	}
 }
 
+func TestSort_Negative(t *testing.T) {
+	a, b := make(sort2.IntSlice, 100), make(sort2.IntSlice, 100)
+	v := 0
+	for i := range a {
+		v = utils.GenerateRandomIntInRange(-100, 100)
+		a[i], b[i] = v, v
+	}
+	sort.Stable(a)
+	Sort(b)
+
+	if !sort2.Equal(a, b) {
+		t.Fatal(a, b)
+	}
+
+	// empty
+	Sort(sort2.IntSlice{})
+}
+
 // BenchmarkSort-8   	20000000	       162 ns/op
 func BenchmarkSort(b *testing.B) {
 	data := make(sort2.IntSlice, b.N)
@@ -8,7 +8,11 @@ import (
 //	https://en.wikipedia.org/wiki/Counting_sort
 //	original K range is [0, max+1), here shifts it to [min, max+1), min >= 0 to reduce count array size
 //	notice: this is type specified, only for IntSlice here
+//	negative ints are shifted by min as well
 func Sort(data sort.IntSlice) sort.IntSlice {
+	if data.Len() == 0 {
+		return sort.IntSlice{}
+	}
 	min, max := data.MinMax()
 	// max+1 - min instead of max+1 to reduce array size
 	count := make(sort.IntSlice, max+1-min)
@@ -33,3 +37,37 @@ func Sort(data sort.IntSlice) sort.IntSlice {
 
 	return sorted
 }
+
+// SortByKey sorts records of data by int keys in a small range with counting sort, stable
+//	key(i) is called once per record before data is moved, records are moved by Swap at most n-1 times
+//	time complexity O(n + k), k is max key - min key + 1, it needs k + 2n ints, see radix.SortByKey for wide keys
+func SortByKey(data sort.Interface, key func(i int) int) {
+	n := data.Len()
+	if n < 2 {
+		return
+	}
+	keys := make(sort.IntSlice, n)
+	for i := range keys {
+		keys[i] = key(i)
+	}
+	min, max := keys.MinMax()
+	count := make([]int, max+1-min)
+	for _, k := range keys {
+		count[k-min]++
+	}
+
+	// count becomes index of the first record of every key
+	sum := 0
+	for k, c := range count {
+		count[k] = sum
+		sum += c
+	}
+
+	// perm[j] is the index of record which goes to j
+	perm := make([]int, n)
+	for i, k := range keys {
+		perm[count[k-min]] = i
+		count[k-min]++
+	}
+	sort.Permute(data, perm)
+}
@@ -22,6 +22,65 @@ func TestSort(t *testing.T) {
 	}
 }
 
+func TestSort_Negative(t *testing.T) {
+	a, b := make(sort2.IntSlice, 100), make(sort2.IntSlice, 100)
+	v := 0
+	for i := range a {
+		v = utils.GenerateRandomIntInRange(-100, 100)
+		a[i], b[i] = v, v
+	}
+	sort.Stable(a)
+	b = Sort(b)
+
+	if !sort2.Equal(a, b) {
+		t.Fatal(a, b)
+	}
+
+	if Sort(sort2.IntSlice{}).Len() != 0 {
+		t.Fail()
+	}
+}
+
+type record struct {
+	key, idx int
+}
+
+type records []record
+
+func (rs records) Len() int {
+	return len(rs)
+}
+
+func (rs records) Less(i, j int) bool {
+	return rs[i].key < rs[j].key
+}
+
+func (rs records) Swap(i, j int) {
+	rs[i], rs[j] = rs[j], rs[i]
+}
+
+func TestSortByKey(t *testing.T) {
+	rs := make(records, 1000)
+	for i := range rs {
+		rs[i] = record{key: utils.GenerateRandomIntInRange(-20, 20), idx: i}
+	}
+	expected := append(records(nil), rs...)
+	sort.Stable(expected)
+
+	SortByKey(rs, func(i int) int {
+		return rs[i].key
+	})
+	for i := range rs {
+		if rs[i] != expected[i] {
+			t.Fatal(i, rs[i], expected[i])
+		}
+	}
+
+	SortByKey(records{}, func(i int) int {
+		return 0
+	})
+}
+
 // BenchmarkSort-8   	20000000	       150 ns/op
 func BenchmarkSort(b *testing.B) {
 	data := make(sort2.IntSlice, b.N)
@@ -0,0 +1,164 @@
+package radix
+
+import (
+	"godev/basic/algorithm/sort"
+	"math"
+)
+
+// signBit flips order of two's complement ints into order of unsigned ints
+const signBit = 1 << 63
+
+// Ints sorts ints in place with binary LSD radix sort, stable
+//	keys are split into 8 bytes sorted from lowest to highest by counting, a byte shared by all keys is skipped,
+//	so small ranges (e.g. timestamps of one day) take only a few passes
+//	time complexity O(n * 8), it needs 2 buffers of n uint64s
+func Ints(data []int) {
+	keys := make([]uint64, len(data))
+	for i, x := range data {
+		keys[i] = uint64(x) ^ signBit
+	}
+	lsd(keys, nil)
+	for i, k := range keys {
+		data[i] = int(k ^ signBit)
+	}
+}
+
+// Int64s sorts int64s in place with binary LSD radix sort, stable, see Ints
+func Int64s(data []int64) {
+	keys := make([]uint64, len(data))
+	for i, x := range data {
+		keys[i] = uint64(x) ^ signBit
+	}
+	lsd(keys, nil)
+	for i, k := range keys {
+		data[i] = int64(k ^ signBit)
+	}
+}
+
+// Uint64s sorts uint64s in place with binary LSD radix sort, stable, see Ints
+func Uint64s(data []uint64) {
+	lsd(data, nil)
+}
+
+// Float64s sorts float64s in place with binary LSD radix sort, stable
+//	IEEE 754 bits of positive floats are ordered like unsigned ints, bits of negative floats are ordered reversely,
+//	so setting the sign bit of positive floats and flipping all bits of negative ones gives ordered keys
+//	notice: NaNs go first like sort.Float64s and lose their payload, -0 goes before +0
+func Float64s(data []float64) {
+	keys := make([]uint64, len(data))
+	for i, f := range data {
+		keys[i] = float64Key(f)
+	}
+	lsd(keys, nil)
+	for i, k := range keys {
+		data[i] = keyFloat64(k)
+	}
+}
+
+func float64Key(f float64) uint64 {
+	if f != f {
+		// NaN
+		return 0
+	}
+	b := math.Float64bits(f)
+	if b&signBit != 0 {
+		return ^b
+	}
+	return b | signBit
+}
+
+func keyFloat64(k uint64) float64 {
+	if k == 0 {
+		return math.NaN()
+	}
+	if k&signBit != 0 {
+		return math.Float64frombits(k &^ signBit)
+	}
+	return math.Float64frombits(^k)
+}
+
+// SortByKey sorts records of data by int64 keys with binary LSD radix sort, stable
+//	key(i) is called once per record before data is moved, records are moved by Swap at most n-1 times,
+//	useful for records sorted by timestamps, see Ints
+func SortByKey(data sort.Interface, key func(i int) int64) {
+	n := data.Len()
+	keys := make([]uint64, n)
+	for i := range keys {
+		keys[i] = uint64(key(i)) ^ signBit
+	}
+	sortByKeys(data, keys)
+}
+
+// SortByUint64Key sorts records of data by uint64 keys with binary LSD radix sort, stable, see SortByKey
+//	useful for records sorted by IDs
+func SortByUint64Key(data sort.Interface, key func(i int) uint64) {
+	n := data.Len()
+	keys := make([]uint64, n)
+	for i := range keys {
+		keys[i] = key(i)
+	}
+	sortByKeys(data, keys)
+}
+
+func sortByKeys(data sort.Interface, keys []uint64) {
+	perm := make([]int, len(keys))
+	for i := range perm {
+		perm[i] = i
+	}
+	lsd(keys, perm)
+	sort.Permute(data, perm)
+}
+
+// lsd sorts keys with least significant digit radix sort on bytes, perm is moved along with keys if not nil
+func lsd(keys []uint64, perm []int) {
+	n := len(keys)
+	if n < 2 {
+		return
+	}
+	// histograms of all bytes in one pass
+	var counts [8][256]int
+	for _, k := range keys {
+		for d := uint(0); d < 8; d++ {
+			counts[d][byte(k>>(8*d))]++
+		}
+	}
+
+	src, dst := keys, make([]uint64, n)
+	var permSrc, permDst []int
+	if perm != nil {
+		permSrc, permDst = perm, make([]int, n)
+	}
+	for d := uint(0); d < 8; d++ {
+		shift := 8 * d
+		count := &counts[d]
+		// all keys share this byte
+		if count[byte(src[0]>>shift)] == n {
+			continue
+		}
+
+		var offsets [256]int
+		sum := 0
+		for b, c := range count {
+			offsets[b] = sum
+			sum += c
+		}
+		for i, k := range src {
+			b := byte(k >> shift)
+			dst[offsets[b]] = k
+			if perm != nil {
+				permDst[offsets[b]] = permSrc[i]
+			}
+			offsets[b]++
+		}
+		src, dst = dst, src
+		permSrc, permDst = permDst, permSrc
+	}
+
+	// odd number of passes leaves result in buffer
+	if &src[0] != &keys[0] {
+		copy(keys, src)
+		if perm != nil {
+			copy(perm, permSrc)
+		}
+	}
+}
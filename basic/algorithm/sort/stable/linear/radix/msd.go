@@ -0,0 +1,74 @@
+package radix
+
+// msdCutoff is the length under which strings are sorted by insertion sort
+const msdCutoff = 32
+
+// Strings sorts strings in place by bytes with MSD radix sort, stable
+//	https://en.wikipedia.org/wiki/Radix_sort#Most_significant_digit
+//	strings are distributed by their byte at depth d into 257 buckets (strings ending before d go first),
+//	every bucket is sorted recursively at depth d+1, a byte shared by all strings is skipped without moving them
+//	time complexity O(total length of distinguishing prefixes), it needs a buffer of n strings
+func Strings(data []string) {
+	msd(data, make([]string, len(data)), 0)
+}
+
+// digit returns byte of s at depth d plus 1, 0 if s is shorter
+func digit(s string, d int) int {
+	if d < len(s) {
+		return int(s[d]) + 1
+	}
+	return 0
+}
+
+// msd sorts data whose strings share first depth bytes
+func msd(data, buf []string, depth int) {
+	n := len(data)
+	var counts [257]int
+	for {
+		if n < msdCutoff {
+			insertionSort(data, depth)
+			return
+		}
+		counts = [257]int{}
+		for _, s := range data {
+			counts[digit(s, depth)]++
+		}
+		// all strings end here, they are equal
+		if counts[0] == n {
+			return
+		}
+		if counts[digit(data[0], depth)] != n {
+			break
+		}
+		depth++
+	}
+
+	var offsets [258]int
+	for b, c := range counts {
+		offsets[b+1] = offsets[b] + c
+	}
+	next := offsets
+	for _, s := range data {
+		b := digit(s, depth)
+		buf[next[b]] = s
+		next[b]++
+	}
+	copy(data, buf)
+
+	// bucket 0 holds equal strings
+	for b := 1; b < 257; b++ {
+		lo, hi := offsets[b], offsets[b+1]
+		if hi-lo > 1 {
+			msd(data[lo:hi], buf[lo:hi], depth+1)
+		}
+	}
+}
+
+// insertionSort sorts data whose strings share first depth bytes
+func insertionSort(data []string, depth int) {
+	for i := 1; i < len(data); i++ {
+		for j := i; j > 0 && data[j][depth:] < data[j-1][depth:]; j-- {
+			data[j], data[j-1] = data[j-1], data[j]
+		}
+	}
+}
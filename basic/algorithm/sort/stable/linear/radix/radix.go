@@ -2,18 +2,25 @@ package radix
 
 import (
 	"godev/basic/algorithm/sort"
-	"math"
 )
 
 // Sort implements radix sort, useful for uniformly distributed data over a range
 //	https://en.wikipedia.org/wiki/Radix_sort
 //	notice: this is type specified, only for IntSlice here
 //	time complexity O(n * k), k ~= logB N, B is radix, for int, B = 10
+//	digits are taken from num - min, so negative ints are sorted too, see Ints for a faster binary radix
 func Sort(data sort.IntSlice) {
-	_, max := data.MinMax()
-	radix := 10
-	// K digits number
-	K := int(math.Ceil(math.Log10(float64(max + 1))))
+	if data.Len() < 2 {
+		return
+	}
+	min, max := data.MinMax()
+	radix := uint(10)
+	// K digits number, uint avoids overflow of max - min
+	K := 0
+	for r := uint(max - min); r > 0; r /= radix {
+		K++
+	}
+	exp := uint(1)
 	for i := 1; i < K+1; i++ {
 		// radix buckets
 		buckets := make([]sort.IntSlice, radix)
@@ -22,7 +29,7 @@ func Sort(data sort.IntSlice) {
 		}
 		for _, num := range data {
 			// K-th digit, from low to high (right to left)
-			bucketIdx := num % int(math.Pow(float64(radix), float64(i))) / int(math.Pow(float64(radix), float64(i-1)))
+			bucketIdx := uint(num-min) / exp % radix
 			buckets[bucketIdx] = append(buckets[bucketIdx], num)
 		}
 
@@ -34,5 +41,6 @@ func Sort(data sort.IntSlice) {
 				idx++
 			}
 		}
+		exp *= radix
 	}
 }
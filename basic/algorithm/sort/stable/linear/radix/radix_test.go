@@ -3,7 +3,10 @@ package radix
 import (
 	sort2 "godev/basic/algorithm/sort"
 	"godev/utils"
+	"math"
+	"math/rand"
 	"sort"
+	"strings"
 	"testing"
 )
 
@@ -22,6 +25,294 @@ func TestSort(t *testing.T) {
 	}
 }
 
+func TestSort_Negative(t *testing.T) {
+	for _, r := range [][2]int{{-100, 100}, {-1000000, -10}, {math.MinInt64 / 2, math.MaxInt64 / 2}} {
+		a, b := make(sort2.IntSlice, 1000), make(sort2.IntSlice, 1000)
+		v := 0
+		for i := range a {
+			v = utils.GenerateRandomIntInRange(r[0], r[1])
+			a[i], b[i] = v, v
+		}
+		a[0], b[0] = r[0], r[0]
+		sort.Stable(a)
+		Sort(b)
+
+		if !sort2.Equal(a, b) {
+			t.Fatal(r)
+		}
+	}
+
+	// empty and extremes
+	Sort(sort2.IntSlice{})
+	is := sort2.IntSlice{math.MaxInt64, 0, math.MinInt64, -1}
+	Sort(is)
+	if !sort2.Equal(is, sort2.IntSlice{math.MinInt64, -1, 0, math.MaxInt64}) {
+		t.Fatal(is)
+	}
+}
+
+func TestInts(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 100, 10000} {
+		for _, gen := range []func() int{
+			rand.Int,
+			func() int { return -rand.Int() },
+			func() int { return rand.Intn(200) - 100 },
+			// shared high bytes skip passes
+			func() int { return 1<<40 + rand.Intn(1<<16) },
+			func() int { return 7 },
+		} {
+			a, b := make([]int, n), make([]int, n)
+			for i := range a {
+				a[i] = gen()
+				b[i] = a[i]
+			}
+			sort.Ints(a)
+			Ints(b)
+			if !sort2.Equal(a, b) {
+				t.Fatal(n)
+			}
+		}
+	}
+
+	is := []int{math.MaxInt64, 0, math.MinInt64, -1, 1}
+	Ints(is)
+	if !sort2.Equal(is, sort2.IntSlice{math.MinInt64, -1, 0, 1, math.MaxInt64}) {
+		t.Fatal(is)
+	}
+}
+
+func TestInt64s(t *testing.T) {
+	a, b := make([]int64, 1000), make([]int64, 1000)
+	for i := range a {
+		a[i] = rand.Int63() - rand.Int63()
+		b[i] = a[i]
+	}
+	sort.Slice(a, func(i, j int) bool { return a[i] < a[j] })
+	Int64s(b)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatal(i)
+		}
+	}
+}
+
+func TestUint64s(t *testing.T) {
+	a, b := make([]uint64, 1000), make([]uint64, 1000)
+	for i := range a {
+		a[i] = rand.Uint64()
+		b[i] = a[i]
+	}
+	a[0], b[0] = math.MaxUint64, math.MaxUint64
+	sort.Slice(a, func(i, j int) bool { return a[i] < a[j] })
+	Uint64s(b)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatal(i)
+		}
+	}
+}
+
+func TestFloat64s(t *testing.T) {
+	a := make([]float64, 1000)
+	for i := range a {
+		a[i] = rand.NormFloat64() * math.Pow(10, float64(rand.Intn(20)-10))
+	}
+	a = append(a, math.Inf(1), math.Inf(-1), math.NaN(), 0, math.Copysign(0, -1), math.SmallestNonzeroFloat64,
+		-math.SmallestNonzeroFloat64, math.MaxFloat64, -math.MaxFloat64, math.NaN())
+	b := append([]float64(nil), a...)
+	sort.Float64s(a)
+	Float64s(b)
+	for i := range a {
+		if !(a[i] == b[i] || math.IsNaN(a[i]) && math.IsNaN(b[i])) {
+			t.Fatal(i, a[i], b[i])
+		}
+	}
+	// -0 before +0
+	if !math.IsNaN(b[0]) || !math.IsNaN(b[1]) || b[2] != math.Inf(-1) || b[len(b)-1] != math.Inf(1) {
+		t.Fatal(b[:3])
+	}
+	zero := sort.SearchFloat64s(b[2:], 0) + 2
+	if !math.Signbit(b[zero]) || math.Signbit(b[zero+1]) {
+		t.Fatal(b[zero], b[zero+1])
+	}
+}
+
+func TestStrings(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 31, 32, 100, 10000} {
+		a := make([]string, n)
+		for i := range a {
+			switch i % 4 {
+			case 0:
+				a[i] = utils.GenerateRandomString(rand.Intn(20))
+			case 1:
+				// long shared prefixes
+				a[i] = "https://example.com/" + utils.GenerateRandomString(rand.Intn(3))
+			case 2:
+				a[i] = strings.Repeat("a", rand.Intn(50))
+			default:
+				a[i] = string([]byte{byte(rand.Intn(256)), 0, byte(rand.Intn(256))})
+			}
+		}
+		b := append([]string(nil), a...)
+		sort.Strings(a)
+		Strings(b)
+		for i := range a {
+			if a[i] != b[i] {
+				t.Fatal(n, i, a[i], b[i])
+			}
+		}
+	}
+
+	equal := make([]string, 100)
+	for i := range equal {
+		equal[i] = "same"
+	}
+	Strings(equal)
+}
+
+type record struct {
+	key int64
+	id  uint64
+	idx int
+}
+
+type records []record
+
+func (rs records) Len() int {
+	return len(rs)
+}
+
+func (rs records) Less(i, j int) bool {
+	return rs[i].key < rs[j].key
+}
+
+func (rs records) Swap(i, j int) {
+	rs[i], rs[j] = rs[j], rs[i]
+}
+
+func TestSortByKey(t *testing.T) {
+	rs := make(records, 10000)
+	now := int64(1600000000000000000)
+	for i := range rs {
+		rs[i] = record{key: now + rand.Int63n(1000) - 500, id: rand.Uint64() % 100, idx: i}
+	}
+	expected := append(records(nil), rs...)
+	sort.Stable(expected)
+	SortByKey(rs, func(i int) int64 {
+		return rs[i].key
+	})
+	for i := range rs {
+		if rs[i] != expected[i] {
+			t.Fatal(i, rs[i], expected[i])
+		}
+	}
+
+	sort.SliceStable(expected, func(i, j int) bool {
+		return expected[i].id < expected[j].id
+	})
+	SortByUint64Key(rs, func(i int) uint64 {
+		return rs[i].id
+	})
+	for i := range rs {
+		if rs[i] != expected[i] {
+			t.Fatal(i, rs[i], expected[i])
+		}
+	}
+
+	SortByKey(records{}, func(i int) int64 {
+		return 0
+	})
+}
+
+const benchSize = 1 << 16
+
+func BenchmarkInts(b *testing.B) {
+	data, a := make([]int, benchSize), make([]int, benchSize)
+	for i := range data {
+		data[i] = rand.Int() - rand.Int()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		copy(a, data)
+		b.StartTimer()
+		Ints(a)
+	}
+}
+
+func BenchmarkOfficialInts(b *testing.B) {
+	data, a := make([]int, benchSize), make([]int, benchSize)
+	for i := range data {
+		data[i] = rand.Int() - rand.Int()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		copy(a, data)
+		b.StartTimer()
+		sort.Ints(a)
+	}
+}
+
+func BenchmarkStrings(b *testing.B) {
+	data, a := make([]string, benchSize), make([]string, benchSize)
+	for i := range data {
+		data[i] = utils.GenerateRandomString(16)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		copy(a, data)
+		b.StartTimer()
+		Strings(a)
+	}
+}
+
+func BenchmarkOfficialStrings(b *testing.B) {
+	data, a := make([]string, benchSize), make([]string, benchSize)
+	for i := range data {
+		data[i] = utils.GenerateRandomString(16)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		copy(a, data)
+		b.StartTimer()
+		sort.Strings(a)
+	}
+}
+
+// BenchmarkSortByKey sorts records by timestamps of one hour
+func BenchmarkSortByKey(b *testing.B) {
+	data, rs := make(records, benchSize), make(records, benchSize)
+	for i := range data {
+		data[i] = record{key: 1600000000000000000 + rand.Int63n(3600e9), idx: i}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		copy(rs, data)
+		b.StartTimer()
+		SortByKey(rs, func(i int) int64 {
+			return rs[i].key
+		})
+	}
+}
+
+func BenchmarkOfficialStable(b *testing.B) {
+	data, rs := make(records, benchSize), make(records, benchSize)
+	for i := range data {
+		data[i] = record{key: 1600000000000000000 + rand.Int63n(3600e9), idx: i}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		copy(rs, data)
+		b.StartTimer()
+		sort.Stable(rs)
+	}
+}
+
 // BenchmarkSort-8   	 3000000	       481 ns/op
 func BenchmarkSort(b *testing.B) {
 	data := make(sort2.IntSlice, b.N)
@@ -0,0 +1,303 @@
+package pdq
+
+import (
+	"godev/basic/algorithm/sort"
+	"math/bits"
+)
+
+const (
+	// insertionSortLen is the length under which ranges are sorted by insertion sort
+	insertionSortLen = 12
+	// nintherLen is the length from which pivot is the median of three medians (Tukey's ninther)
+	nintherLen = 50
+	// maxSwaps is the number of swaps choosePivot does on strictly decreasing data
+	maxSwaps = 4 * 3
+	// partialInsertionSteps is the number of misplaced elements partialInsertionSort fixes before giving up
+	partialInsertionSteps = 5
+	// partialInsertionLen is the length under which partialInsertionSort only checks order
+	partialInsertionLen = 50
+)
+
+// sortedHint is what choosePivot learnt about order of data
+type sortedHint int
+
+const (
+	unknownHint sortedHint = iota
+	increasingHint
+	decreasingHint
+)
+
+// Sort implements pattern-defeating quick sort in place, not stable
+//	https://arxiv.org/pdf/2106.05123.pdf
+//	https://golang.org/src/sort/sort.go
+//	quick sort with insertion sort on small ranges and ninther pivots, it detects sorted and reversed input in O(n),
+//	partitions runs of equal elements once, shuffles data when partitions get unbalanced and falls back to heap sort
+//	after log(n) bad pivots, so the worst case is O(n*log(n))
+func Sort(data sort.Interface) {
+	n := data.Len()
+	pdqSort(data, 0, n, bits.Len(uint(n)))
+}
+
+// pdqSort sorts data[a, b), limit is the number of bad pivots allowed before falling back to heap sort
+func pdqSort(data sort.Interface, a, b, limit int) {
+	wasBalanced, wasPartitioned := true, true
+	for {
+		n := b - a
+		if n <= insertionSortLen {
+			insertionSort(data, a, b)
+			return
+		}
+		if limit == 0 {
+			heapSort(data, a, b)
+			return
+		}
+		// the last partition was unbalanced, shuffle some elements to break patterns
+		if !wasBalanced {
+			breakPatterns(data, a, b)
+			limit--
+		}
+
+		pivot, hint := choosePivot(data, a, b)
+		if hint == decreasingHint {
+			reverse(data, a, b)
+			// pivot moved with reversing
+			pivot = (b - 1) - (pivot - a)
+			hint = increasingHint
+		}
+		// data looks sorted, try fixing a few misplaced elements
+		if wasBalanced && wasPartitioned && hint == increasingHint {
+			if partialInsertionSort(data, a, b) {
+				return
+			}
+		}
+
+		// data[a-1] is the pivot of an earlier partition and <= every element of data[a, b),
+		// when it equals the new pivot, the elements equal to it are already in place
+		if a > 0 && !data.Less(a-1, pivot) {
+			a = partitionEqual(data, a, b, pivot)
+			continue
+		}
+
+		mid, alreadyPartitioned := partition(data, a, b, pivot)
+		wasPartitioned = alreadyPartitioned
+		left, right := mid-a, b-mid
+		// recurse into the smaller side to keep the stack depth at most log(n)
+		if left < right {
+			wasBalanced = left >= n/8
+			pdqSort(data, a, mid, limit)
+			a = mid + 1
+		} else {
+			wasBalanced = right >= n/8
+			pdqSort(data, mid+1, b, limit)
+			b = mid
+		}
+	}
+}
+
+// partition partitions data[a, b) around data[pivot]
+//	returns the final index of the pivot, data[a, mid) < pivot <= data(mid, b),
+//	alreadyPartitioned is true when no elements were swapped
+func partition(data sort.Interface, a, b, pivot int) (mid int, alreadyPartitioned bool) {
+	data.Swap(a, pivot)
+	i, j := a+1, b-1
+	for i <= j && data.Less(i, a) {
+		i++
+	}
+	for i <= j && !data.Less(j, a) {
+		j--
+	}
+	if i > j {
+		data.Swap(j, a)
+		return j, true
+	}
+	data.Swap(i, j)
+	i++
+	j--
+
+	for {
+		for i <= j && data.Less(i, a) {
+			i++
+		}
+		for i <= j && !data.Less(j, a) {
+			j--
+		}
+		if i > j {
+			break
+		}
+		data.Swap(i, j)
+		i++
+		j--
+	}
+	data.Swap(j, a)
+	return j, false
+}
+
+// partitionEqual partitions data[a, b) into elements equal to data[pivot] and greater elements
+//	data[pivot] is known to be the minimum, returns the index of the first greater element
+func partitionEqual(data sort.Interface, a, b, pivot int) int {
+	data.Swap(a, pivot)
+	i, j := a+1, b-1
+	for {
+		for i <= j && !data.Less(a, i) {
+			i++
+		}
+		for i <= j && data.Less(a, j) {
+			j--
+		}
+		if i > j {
+			break
+		}
+		data.Swap(i, j)
+		i++
+		j--
+	}
+	return i
+}
+
+// partialInsertionSort moves at most partialInsertionSteps misplaced elements into place
+//	returns true if data[a, b) ends up sorted
+func partialInsertionSort(data sort.Interface, a, b int) bool {
+	i := a + 1
+	for step := 0; step < partialInsertionSteps; step++ {
+		for i < b && !data.Less(i, i-1) {
+			i++
+		}
+		if i == b {
+			return true
+		}
+		if b-a < partialInsertionLen {
+			return false
+		}
+		data.Swap(i, i-1)
+
+		// shift the smaller element to the left
+		for j := i - 1; j > a && data.Less(j, j-1); j-- {
+			data.Swap(j, j-1)
+		}
+		// shift the greater element to the right
+		for j := i + 1; j < b && data.Less(j, j-1); j++ {
+			data.Swap(j, j-1)
+		}
+	}
+	return false
+}
+
+// breakPatterns swaps three elements around the middle of data[a, b) with pseudo random ones
+func breakPatterns(data sort.Interface, a, b int) {
+	n := b - a
+	if n < 8 {
+		return
+	}
+	random := xorShift(n)
+	mask := uint(1)<<uint(bits.Len(uint(n))) - 1
+	mid := a + n/4*2 - 1
+	for i := 0; i < 3; i++ {
+		other := int(uint(random.next()) & mask)
+		if other >= n {
+			other -= n
+		}
+		data.Swap(mid-1+i, a+other)
+	}
+}
+
+// xorShift is a tiny deterministic pseudo random generator
+//	https://en.wikipedia.org/wiki/Xorshift
+type xorShift uint64
+
+func (r *xorShift) next() uint64 {
+	*r ^= *r << 13
+	*r ^= *r >> 7
+	*r ^= *r << 17
+	return uint64(*r)
+}
+
+// choosePivot returns index of the pivot of data[a, b) and a hint of its order
+//	the pivot is the median of three elements at 1/4, 2/4 and 3/4, or the ninther of their neighbours on long ranges,
+//	no comparison swapping anything hints increasing data, all of them swapping hints decreasing data
+func choosePivot(data sort.Interface, a, b int) (pivot int, hint sortedHint) {
+	n := b - a
+	swaps := 0
+	i, j, k := a+n/4*1, a+n/4*2, a+n/4*3
+	if n >= 8 {
+		if n >= nintherLen {
+			i = medianAdjacent(data, i, &swaps)
+			j = medianAdjacent(data, j, &swaps)
+			k = medianAdjacent(data, k, &swaps)
+		}
+		j = median(data, i, j, k, &swaps)
+	}
+	switch swaps {
+	case 0:
+		return j, increasingHint
+	case maxSwaps:
+		return j, decreasingHint
+	default:
+		return j, unknownHint
+	}
+}
+
+// order2 returns indexes a, b ordered by their elements, data is untouched
+func order2(data sort.Interface, a, b int, swaps *int) (int, int) {
+	if data.Less(b, a) {
+		*swaps++
+		return b, a
+	}
+	return a, b
+}
+
+// median returns index of the median of data[a], data[b], data[c]
+func median(data sort.Interface, a, b, c int, swaps *int) int {
+	a, b = order2(data, a, b, swaps)
+	b, c = order2(data, b, c, swaps)
+	a, b = order2(data, a, b, swaps)
+	return b
+}
+
+// medianAdjacent returns index of the median of data[a-1], data[a], data[a+1]
+func medianAdjacent(data sort.Interface, a int, swaps *int) int {
+	return median(data, a-1, a, a+1, swaps)
+}
+
+func reverse(data sort.Interface, a, b int) {
+	for i, j := a, b-1; i < j; i, j = i+1, j-1 {
+		data.Swap(i, j)
+	}
+}
+
+func insertionSort(data sort.Interface, a, b int) {
+	for i := a + 1; i < b; i++ {
+		for j := i; j > a && data.Less(j, j-1); j-- {
+			data.Swap(j, j-1)
+		}
+	}
+}
+
+// heapSort sorts data[a, b) with a maximum heap
+func heapSort(data sort.Interface, a, b int) {
+	n := b - a
+	for i := (n - 1) >> 1; i >= 0; i-- {
+		siftDown(data, i, n, a)
+	}
+	for i := n - 1; i >= 0; i-- {
+		data.Swap(a, a+i)
+		siftDown(data, 0, i, a)
+	}
+}
+
+// siftDown implements maximum heap on data[offset, offset+n)
+func siftDown(data sort.Interface, root, n, offset int) {
+	for {
+		child := 2*root + 1
+		if child >= n {
+			return
+		}
+		if child+1 < n && data.Less(offset+child, offset+child+1) {
+			child++
+		}
+		if !data.Less(offset+root, offset+child) {
+			return
+		}
+		data.Swap(offset+root, offset+child)
+		root = child
+	}
+}
@@ -0,0 +1,198 @@
+package pdq
+
+import (
+	sort2 "godev/basic/algorithm/sort"
+	"godev/utils"
+	"math/bits"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// patterns returns inputs of length n known to hurt naive quick sort
+func patterns(n int) map[string]sort2.IntSlice {
+	res := map[string]sort2.IntSlice{}
+	add := func(name string, f func(i int) int) {
+		data := make(sort2.IntSlice, n)
+		for i := range data {
+			data[i] = f(i)
+		}
+		res[name] = data
+	}
+	add("random", func(i int) int { return utils.GenerateRandomInt() })
+	add("sorted", func(i int) int { return i })
+	add("reversed", func(i int) int { return n - i })
+	add("equal", func(i int) int { return 7 })
+	add("few", func(i int) int { return rand.Intn(4) })
+	add("sawtooth", func(i int) int { return i % 32 })
+	add("organPipe", func(i int) int {
+		if i < n/2 {
+			return i
+		}
+		return n - i
+	})
+	add("mod8", func(i int) int { return i % 8 * n / 8 })
+	add("pushFront", func(i int) int { return (i + 1) % n })
+	add("pushMiddle", func(i int) int {
+		if i == n/2 {
+			return n
+		}
+		return i
+	})
+	nearly := make(sort2.IntSlice, n)
+	for i := range nearly {
+		nearly[i] = i
+	}
+	for i := 0; i < n/100+1 && n > 1; i++ {
+		nearly.Swap(rand.Intn(n), rand.Intn(n))
+	}
+	res["nearlySorted"] = nearly
+	return res
+}
+
+// counter counts calls of Less
+type counter struct {
+	sort2.IntSlice
+	less int
+}
+
+func (c *counter) Less(i, j int) bool {
+	c.less++
+	return c.IntSlice.Less(i, j)
+}
+
+func TestSort(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 5, 12, 13, 49, 50, 100, 1000, 10000} {
+		for name, data := range patterns(n) {
+			expected := append(sort2.IntSlice(nil), data...)
+			sort.Ints(expected)
+			c := &counter{IntSlice: data}
+			Sort(c)
+			if !sort2.Equal(data, expected) {
+				t.Fatal(name, n)
+			}
+			// heap sort fallback bounds comparisons
+			if limit := 4 * (n + 1) * bits.Len(uint(n)); c.less > limit {
+				t.Fatal(name, n, c.less, limit)
+			}
+		}
+	}
+}
+
+// TestSort_Linear checks inputs detected in O(n)
+func TestSort_Linear(t *testing.T) {
+	n := 10000
+	for _, name := range []string{"sorted", "reversed", "equal"} {
+		c := &counter{IntSlice: patterns(n)[name]}
+		Sort(c)
+		if c.less > 2*n {
+			t.Fatal(name, c.less)
+		}
+	}
+}
+
+// adversary builds the worst input for a quick sort while it runs
+//	https://www.cs.dartmouth.edu/~doug/mdmspe.pdf
+//	all elements start as gas, every comparison of two gas elements freezes one of them to the next solid value,
+//	so the pivot candidate always stays gas and turns out to be the greatest element
+type adversary struct {
+	data      []int
+	gas       int
+	solid     int
+	candidate int
+	less      int
+}
+
+func newAdversary(n int) *adversary {
+	adv := &adversary{data: make([]int, n), gas: n}
+	for i := range adv.data {
+		adv.data[i] = adv.gas
+	}
+	return adv
+}
+
+func (adv *adversary) Len() int {
+	return len(adv.data)
+}
+
+func (adv *adversary) Less(i, j int) bool {
+	adv.less++
+	if adv.data[i] == adv.gas && adv.data[j] == adv.gas {
+		if i == adv.candidate {
+			adv.data[i] = adv.solid
+		} else {
+			adv.data[j] = adv.solid
+		}
+		adv.solid++
+	}
+	if adv.data[i] == adv.gas {
+		adv.candidate = i
+	} else if adv.data[j] == adv.gas {
+		adv.candidate = j
+	}
+	return adv.data[i] < adv.data[j]
+}
+
+func (adv *adversary) Swap(i, j int) {
+	adv.data[i], adv.data[j] = adv.data[j], adv.data[i]
+}
+
+func TestSort_Adversary(t *testing.T) {
+	for _, n := range []int{100, 1000, 10000, 50000} {
+		adv := newAdversary(n)
+		Sort(adv)
+		if limit := 4 * n * bits.Len(uint(n)); adv.less > limit {
+			t.Fatal(n, adv.less, limit)
+		}
+		if !sort.IsSorted(sort.IntSlice(adv.data)) {
+			t.Fatal(n)
+		}
+	}
+}
+
+func TestHeapSort(t *testing.T) {
+	data := patterns(1000)["random"]
+	expected := append(sort2.IntSlice(nil), data...)
+	// only the middle is sorted
+	heapSort(data, 100, 900)
+	if !sort.IsSorted(data[100:900]) || !sort2.Equal(data[:100], expected[:100]) || !sort2.Equal(data[900:], expected[900:]) {
+		t.Fail()
+	}
+	sort.Ints(expected)
+	heapSort(data, 0, 1000)
+	if !sort2.Equal(data, expected) {
+		t.Fail()
+	}
+}
+
+func benchmarkSort(b *testing.B, name string, f func(data sort2.Interface)) {
+	data := patterns(1 << 16)[name]
+	a := make(sort2.IntSlice, len(data))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		copy(a, data)
+		b.StartTimer()
+		f(a)
+	}
+}
+
+func BenchmarkSort_Random(b *testing.B) {
+	benchmarkSort(b, "random", Sort)
+}
+
+func BenchmarkSort_NearlySorted(b *testing.B) {
+	benchmarkSort(b, "nearlySorted", Sort)
+}
+
+func BenchmarkSort_Reversed(b *testing.B) {
+	benchmarkSort(b, "reversed", Sort)
+}
+
+func BenchmarkOfficialSort_Random(b *testing.B) {
+	benchmarkSort(b, "random", func(data sort2.Interface) { sort.Sort(data) })
+}
+
+func BenchmarkOfficialSort_NearlySorted(b *testing.B) {
+	benchmarkSort(b, "nearlySorted", func(data sort2.Interface) { sort.Sort(data) })
+}
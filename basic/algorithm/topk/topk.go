@@ -0,0 +1,144 @@
+// Package topk keeps the k greatest values of a stream in O(k) memory
+//	a minimum heap of k values is bounded by its root: a new value replaces the root only when it is greater,
+//	so every value costs O(1) when rejected and O(log(k)) when kept
+//	notice: pass a reversed comparator to keep the k smallest values
+package topk
+
+import (
+	"godev/basic"
+	"sort"
+)
+
+// TopK is a bounded minimum heap of the k greatest values pushed so far
+//	notice: not thread-safe
+type TopK struct {
+	k          int
+	comparator basic.Comparator
+	values     []interface{}
+}
+
+// New creates a TopK keeping k values, k < 1 keeps nothing
+func New(k int, comparator basic.Comparator) *TopK {
+	if k < 0 {
+		k = 0
+	}
+	return &TopK{
+		k:          k,
+		comparator: comparator,
+		values:     make([]interface{}, 0, k),
+	}
+}
+
+// Push offers value, returns true if value is kept among top k
+//	a value equal to the current minimum is rejected when heap is full, so earlier values win ties
+func (t *TopK) Push(value interface{}) bool {
+	if len(t.values) < t.k {
+		t.values = append(t.values, value)
+		t.up(len(t.values) - 1)
+		return true
+	}
+	if t.k == 0 || t.comparator(value, t.values[0]) <= 0 {
+		return false
+	}
+	t.values[0] = value
+	t.down(0)
+	return true
+}
+
+// Min returns the smallest value kept, every value pushed later must be greater to be kept once heap is full
+func (t *TopK) Min() (value interface{}, ok bool) {
+	if len(t.values) == 0 {
+		return nil, false
+	}
+	return t.values[0], true
+}
+
+// Full returns true if k values are kept
+func (t *TopK) Full() bool {
+	return len(t.values) == t.k
+}
+
+// Size returns number of values kept, at most k
+func (t *TopK) Size() int {
+	return len(t.values)
+}
+
+// Empty returns true if no values are kept
+func (t *TopK) Empty() bool {
+	return len(t.values) == 0
+}
+
+// Clear drops all values
+func (t *TopK) Clear() {
+	for i := range t.values {
+		t.values[i] = nil
+	}
+	t.values = t.values[:0]
+}
+
+// Values returns kept values from the greatest to the smallest
+func (t *TopK) Values() []interface{} {
+	values := make([]interface{}, len(t.values))
+	copy(values, t.values)
+	sort.SliceStable(values, func(i, j int) bool {
+		return t.comparator(values[i], values[j]) > 0
+	})
+	return values
+}
+
+// Each visits kept values from the greatest to the smallest as Values does, f returns false to stop
+func (t *TopK) Each(f func(value interface{}) bool) {
+	for _, v := range t.Values() {
+		if !f(v) {
+			return
+		}
+	}
+}
+
+func (t *TopK) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if t.comparator(t.values[i], t.values[parent]) >= 0 {
+			return
+		}
+		t.values[i], t.values[parent] = t.values[parent], t.values[i]
+		i = parent
+	}
+}
+
+func (t *TopK) down(i int) {
+	n := len(t.values)
+	for {
+		child := 2*i + 1
+		if child >= n {
+			return
+		}
+		if child+1 < n && t.comparator(t.values[child+1], t.values[child]) < 0 {
+			child++
+		}
+		if t.comparator(t.values[child], t.values[i]) >= 0 {
+			return
+		}
+		t.values[i], t.values[child] = t.values[child], t.values[i]
+		i = child
+	}
+}
+
+// FromChannel drains ch and returns its k greatest values from the greatest to the smallest
+//	it returns when ch is closed
+func FromChannel(ch <-chan interface{}, k int, comparator basic.Comparator) []interface{} {
+	t := New(k, comparator)
+	for v := range ch {
+		t.Push(v)
+	}
+	return t.Values()
+}
+
+// FromIterator drains iterator and returns its k greatest values from the greatest to the smallest
+func FromIterator(iterator basic.Iterator, k int, comparator basic.Comparator) []interface{} {
+	t := New(k, comparator)
+	for iterator.HasNext() {
+		t.Push(iterator.Next())
+	}
+	return t.Values()
+}
@@ -0,0 +1,138 @@
+package topk
+
+import (
+	"godev/basic"
+	"godev/basic/datastructure/queue/deque"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func checkTop(t *testing.T, values []interface{}, data []int, k int) {
+	sorted := append([]int(nil), data...)
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+	if k > len(sorted) {
+		k = len(sorted)
+	}
+	if len(values) != k {
+		t.Fatal(len(values), k)
+	}
+	for i := range values {
+		if values[i].(int) != sorted[i] {
+			t.Fatal(i, values[i], sorted[i])
+		}
+	}
+}
+
+func TestTopK(t *testing.T) {
+	for _, n := range []int{0, 1, 10, 1000} {
+		for _, k := range []int{0, 1, 5, 100, 2000} {
+			data := make([]int, n)
+			for i := range data {
+				data[i] = rand.Intn(500)
+			}
+			top := New(k, basic.IntComparator)
+			for _, v := range data {
+				top.Push(v)
+			}
+			checkTop(t, top.Values(), data, k)
+			if top.Size() != len(top.Values()) || top.Empty() != (top.Size() == 0) {
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestTopK_Push(t *testing.T) {
+	top := New(3, basic.IntComparator)
+	if _, ok := top.Min(); ok || top.Full() {
+		t.Fail()
+	}
+	for _, v := range []int{5, 1, 3} {
+		if !top.Push(v) {
+			t.Fatal(v)
+		}
+	}
+	if min, _ := top.Min(); min != 1 || !top.Full() {
+		t.Fatal(min)
+	}
+	// not greater than minimum
+	if top.Push(0) || top.Push(1) {
+		t.Fail()
+	}
+	if !top.Push(4) {
+		t.Fail()
+	}
+	if min, _ := top.Min(); min != 3 {
+		t.Fatal(min)
+	}
+	sum := 0
+	top.Each(func(value interface{}) bool {
+		sum += value.(int)
+		return true
+	})
+	if sum != 12 {
+		t.Fatal(sum)
+	}
+
+	top.Clear()
+	if !top.Empty() || top.Full() {
+		t.Fail()
+	}
+	if New(-1, basic.IntComparator).Push(1) {
+		t.Fail()
+	}
+}
+
+func TestTopK_Smallest(t *testing.T) {
+	reversed := func(a, b interface{}) int {
+		return basic.IntComparator(b, a)
+	}
+	top := New(3, reversed)
+	for _, v := range []int{5, 9, 1, 7, 3, 8} {
+		top.Push(v)
+	}
+	values := top.Values()
+	if len(values) != 3 || values[0] != 1 || values[1] != 3 || values[2] != 5 {
+		t.Fatal(values)
+	}
+}
+
+func TestFromChannel(t *testing.T) {
+	data := make([]int, 10000)
+	for i := range data {
+		data[i] = rand.Int()
+	}
+	ch := make(chan interface{}, 16)
+	go func() {
+		for _, v := range data {
+			ch <- v
+		}
+		close(ch)
+	}()
+	checkTop(t, FromChannel(ch, 10, basic.IntComparator), data, 10)
+}
+
+func TestFromIterator(t *testing.T) {
+	data := make([]int, 1000)
+	dq := deque.NewDeque(16)
+	for i := range data {
+		data[i] = rand.Int()
+		dq.PushBack(data[i])
+	}
+	checkTop(t, FromIterator(dq.Iterator(), 10, basic.IntComparator), data, 10)
+}
+
+func BenchmarkTopK(b *testing.B) {
+	data := make([]int, 1<<16)
+	for i := range data {
+		data[i] = rand.Int()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		top := New(100, basic.IntComparator)
+		for _, v := range data {
+			top.Push(v)
+		}
+	}
+}
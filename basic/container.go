@@ -47,6 +47,19 @@ func Float64Comparator(a, b interface{}) int {
 	}
 }
 
+// StringComparator compares two strings
+func StringComparator(a, b interface{}) int {
+	A := a.(string)
+	B := b.(string)
+	if A > B {
+		return 1
+	} else if A == B {
+		return 0
+	} else {
+		return -1
+	}
+}
+
 // sortable obeys sort.Interface, which requires Len() / Swap() / Less()
 type sortable struct {
 	values     []interface{}
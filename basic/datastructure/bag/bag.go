@@ -3,13 +3,17 @@ package bag
 import (
 	"bytes"
 	"fmt"
+	"godev/basic"
+	"sort"
 )
 
 // Bag struct / Multiset
 //	https://en.wikipedia.org/wiki/Set_(abstract_data_type)#Multiset
+//	bag with comparator is ordered, entries are returned sorted by comparator
 type Bag struct {
-	m    map[interface{}]int
-	size int
+	m          map[interface{}]int
+	size       int
+	comparator basic.Comparator
 }
 
 // Entry struct
@@ -30,12 +34,23 @@ func (e *Entry) GetCount() int {
 
 // NewBag creates a new bag
 func NewBag() *Bag {
+	return NewOrderedBag(nil)
+}
+
+// NewOrderedBag creates a new bag ordered by comparator, nil comparator means unordered
+func NewOrderedBag(comparator basic.Comparator) *Bag {
 	return &Bag{
-		m:    make(map[interface{}]int),
-		size: 0,
+		m:          make(map[interface{}]int),
+		size:       0,
+		comparator: comparator,
 	}
 }
 
+// Ordered returns true if the bag has a comparator
+func (bag *Bag) Ordered() bool {
+	return bag.comparator != nil
+}
+
 // Add adds entry into bag
 func (bag *Bag) Add(x interface{}) {
 	if _, found := bag.m[x]; found {
@@ -59,9 +74,15 @@ func (bag *Bag) Contains(x interface{}) bool {
 	return bag.Count(x) != 0
 }
 
-// SetCount sets entry with quantity
+// SetCount sets entry with quantity, cnt <= 0 deletes the entry
 func (bag *Bag) SetCount(x interface{}, cnt int) {
+	bag.size -= bag.m[x]
+	if cnt <= 0 {
+		delete(bag.m, x)
+		return
+	}
 	bag.m[x] = cnt
+	bag.size += cnt
 }
 
 // DeleteAll deletes all same entries
@@ -89,15 +110,38 @@ func (bag *Bag) DeleteOne(x interface{}) bool {
 }
 
 // Entries returns all distinct entries inside the bag
+//	sorted if the bag is ordered
 func (bag *Bag) Entries() []interface{} {
 	es := make([]interface{}, 0, len(bag.m))
 	for k := range bag.m {
 		es = append(es, k)
 	}
+	if bag.comparator != nil {
+		basic.Sort(es, bag.comparator)
+	}
 	return es
 }
 
+// Each visits distinct entries in the same order as Values until f returns false
+//	an unordered bag is visited without copying, an ordered one has to sort its entries first
+func (bag *Bag) Each(f func(value interface{}) bool) {
+	if bag.comparator != nil {
+		for _, e := range bag.Entries() {
+			if !f(e) {
+				return
+			}
+		}
+		return
+	}
+	for k := range bag.m {
+		if !f(k) {
+			return
+		}
+	}
+}
+
 // EntriesWithCount returns entries with count as Entry type
+//	sorted by entry if the bag is ordered
 func (bag *Bag) EntriesWithCount() []Entry {
 	entries := make([]Entry, 0, len(bag.m))
 	for k, v := range bag.m {
@@ -106,15 +150,37 @@ func (bag *Bag) EntriesWithCount() []Entry {
 			count: v,
 		})
 	}
+	if bag.comparator != nil {
+		sort.Slice(entries, func(i, j int) bool {
+			return bag.comparator(entries[i].entry, entries[j].entry) < 0
+		})
+	}
+	return entries
+}
+
+// TopN returns at most n entries with highest count in descending count order
+//	entries with same count are sorted by entry if the bag is ordered
+func (bag *Bag) TopN(n int) []Entry {
+	if n <= 0 {
+		return nil
+	}
+	entries := bag.EntriesWithCount()
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].count > entries[j].count
+	})
+	if n < len(entries) {
+		entries = entries[:n]
+	}
 	return entries
 }
 
 // ForEachEntry maps function on each entry
 //	notice: for entry has n quantity, func f will executes n times on this entry
+//	entries are visited in order if the bag is ordered
 func (bag *Bag) ForEachEntry(f func(interface{})) {
-	for k, count := range bag.m {
-		for i := 0; i < count; i++ {
-			f(k)
+	for _, e := range bag.EntriesWithCount() {
+		for i := 0; i < e.count; i++ {
+			f(e.entry)
 		}
 	}
 }
@@ -168,7 +234,7 @@ func (bag *Bag) Size() int {
 
 // Clear clears bag
 func (bag *Bag) Clear() {
-	*bag = *NewBag()
+	*bag = *NewOrderedBag(bag.comparator)
 }
 
 // Values returns all entries inside the bag
@@ -155,3 +155,66 @@ func TestBag_ContainsAll(t *testing.T) {
 	}
 	fmt.Println(bag)
 }
+
+func TestBag_SetCount(t *testing.T) {
+	bag := NewBag()
+
+	bag.Add(1)
+	bag.SetCount(1, 5)
+	bag.SetCount(2, 3)
+	if bag.Size() != 8 {
+		t.Fail()
+	}
+
+	bag.SetCount(1, 0)
+	if bag.Contains(1) || bag.Size() != 3 {
+		t.Fail()
+	}
+}
+
+func TestOrderedBag(t *testing.T) {
+	bag := NewOrderedBag(basic.IntComparator)
+	if !bag.Ordered() || NewBag().Ordered() {
+		t.Fail()
+	}
+
+	a := []int{5, 3, 3, 1, 4, 4, 4, 2, 3, 4}
+	for i := range a {
+		bag.Add(a[i])
+	}
+
+	for i, e := range bag.Entries() {
+		if e.(int) != i+1 {
+			t.Fail()
+		}
+	}
+	for i, e := range bag.EntriesWithCount() {
+		if e.GetEntry().(int) != i+1 {
+			t.Fail()
+		}
+	}
+
+	var visited []int
+	bag.ForEachEntry(func(x interface{}) {
+		visited = append(visited, x.(int))
+	})
+	for i := 1; i < len(visited); i++ {
+		if visited[i-1] > visited[i] {
+			t.Fail()
+		}
+	}
+
+	// count: 4 -> 4, 3 -> 3, 1 / 2 / 5 -> 1
+	top := bag.TopN(3)
+	if len(top) != 3 || top[0].GetEntry().(int) != 4 || top[1].GetEntry().(int) != 3 || top[2].GetEntry().(int) != 1 {
+		t.Fail()
+	}
+	if len(bag.TopN(10)) != 5 || bag.TopN(0) != nil {
+		t.Fail()
+	}
+
+	bag.Clear()
+	if !bag.Empty() || !bag.Ordered() {
+		t.Fail()
+	}
+}
@@ -0,0 +1,338 @@
+package bitset
+
+import (
+	"bytes"
+	"fmt"
+	"math/bits"
+)
+
+// references:
+// https://en.wikipedia.org/wiki/Bit_array
+// https://en.wikipedia.org/wiki/Succinct_data_structure (rank)
+
+const (
+	wordSize     = 64
+	log2WordSize = 6
+)
+
+// BitSet struct is a dynamic bit set, it grows automatically when setting a bit beyond its length
+type BitSet struct {
+	words  []uint64
+	length uint
+}
+
+func wordsNeeded(n uint) int {
+	return int((n + wordSize - 1) >> log2WordSize)
+}
+
+// NewBitSet creates a new bit set which can hold n bits without growing
+func NewBitSet(n uint) *BitSet {
+	return &BitSet{
+		words:  make([]uint64, wordsNeeded(n)),
+		length: n,
+	}
+}
+
+// NewBitSetFromIndexes creates a new bit set with input bits set
+func NewBitSetFromIndexes(indexes ...uint) *BitSet {
+	bs := NewBitSet(0)
+	for _, i := range indexes {
+		bs.Set(i)
+	}
+	return bs
+}
+
+// grow makes sure bit i is inside the set
+func (bs *BitSet) grow(i uint) {
+	if i < bs.length {
+		return
+	}
+	n := wordsNeeded(i + 1)
+	if n > len(bs.words) {
+		if n <= cap(bs.words) {
+			bs.words = bs.words[:n]
+		} else {
+			// double the capacity to amortize growing
+			words := make([]uint64, n, 2*n)
+			copy(words, bs.words)
+			bs.words = words
+		}
+	}
+	bs.length = i + 1
+}
+
+// Len returns number of bits the set can hold without growing
+func (bs *BitSet) Len() uint {
+	return bs.length
+}
+
+// Set sets bit i to 1
+func (bs *BitSet) Set(i uint) *BitSet {
+	bs.grow(i)
+	bs.words[i>>log2WordSize] |= 1 << (i & (wordSize - 1))
+	return bs
+}
+
+// Unset sets bit i to 0
+func (bs *BitSet) Unset(i uint) *BitSet {
+	if i >= bs.length {
+		return bs
+	}
+	bs.words[i>>log2WordSize] &^= 1 << (i & (wordSize - 1))
+	return bs
+}
+
+// SetTo sets bit i to value
+func (bs *BitSet) SetTo(i uint, value bool) *BitSet {
+	if value {
+		return bs.Set(i)
+	}
+	return bs.Unset(i)
+}
+
+// Flip flips bit i
+func (bs *BitSet) Flip(i uint) *BitSet {
+	bs.grow(i)
+	bs.words[i>>log2WordSize] ^= 1 << (i & (wordSize - 1))
+	return bs
+}
+
+// Test returns true if bit i is 1
+func (bs *BitSet) Test(i uint) bool {
+	if i >= bs.length {
+		return false
+	}
+	return bs.words[i>>log2WordSize]&(1<<(i&(wordSize-1))) != 0
+}
+
+// Count returns number of bits set to 1
+func (bs *BitSet) Count() uint {
+	cnt := 0
+	for _, w := range bs.words {
+		cnt += bits.OnesCount64(w)
+	}
+	return uint(cnt)
+}
+
+// Rank returns number of bits set to 1 in [0, i]
+func (bs *BitSet) Rank(i uint) uint {
+	if i >= bs.length {
+		return bs.Count()
+	}
+	idx := int(i >> log2WordSize)
+	cnt := 0
+	for _, w := range bs.words[:idx] {
+		cnt += bits.OnesCount64(w)
+	}
+	// keep bits [0, i & 63] of the last word
+	mask := ^uint64(0) >> (wordSize - 1 - (i & (wordSize - 1)))
+	cnt += bits.OnesCount64(bs.words[idx] & mask)
+	return uint(cnt)
+}
+
+// NextSet returns index of the first bit set to 1 starting from i (inclusive)
+func (bs *BitSet) NextSet(i uint) (uint, bool) {
+	if i >= bs.length {
+		return 0, false
+	}
+	idx := int(i >> log2WordSize)
+	w := bs.words[idx] >> (i & (wordSize - 1))
+	if w != 0 {
+		return i + uint(bits.TrailingZeros64(w)), true
+	}
+	for idx++; idx < len(bs.words); idx++ {
+		if bs.words[idx] != 0 {
+			return uint(idx)*wordSize + uint(bits.TrailingZeros64(bs.words[idx])), true
+		}
+	}
+	return 0, false
+}
+
+// EachBit calls f on every bit set to 1 in ascending order until f returns false
+func (bs *BitSet) EachBit(f func(i uint) bool) {
+	for idx, w := range bs.words {
+		for w != 0 {
+			t := w & -w // lowest bit
+			if !f(uint(idx)*wordSize + uint(bits.TrailingZeros64(w))) {
+				return
+			}
+			w ^= t
+		}
+	}
+}
+
+// Indexes returns indexes of all bits set to 1 in ascending order
+func (bs *BitSet) Indexes() []uint {
+	indexes := make([]uint, 0, bs.Count())
+	bs.EachBit(func(i uint) bool {
+		indexes = append(indexes, i)
+		return true
+	})
+	return indexes
+}
+
+// Clone returns a copy of the set
+func (bs *BitSet) Clone() *BitSet {
+	words := make([]uint64, len(bs.words))
+	copy(words, bs.words)
+	return &BitSet{
+		words:  words,
+		length: bs.length,
+	}
+}
+
+// Equal returns true if two sets have same bits set to 1, length is ignored
+func (bs *BitSet) Equal(other *BitSet) bool {
+	short, long := bs.words, other.words
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+	for i := range short {
+		if short[i] != long[i] {
+			return false
+		}
+	}
+	for _, w := range long[len(short):] {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// binary combines two sets word by word into a new set with length of the longer one
+func (bs *BitSet) binary(other *BitSet, op func(a, b uint64) uint64) *BitSet {
+	length := bs.length
+	if other.length > length {
+		length = other.length
+	}
+	res := NewBitSet(length)
+	for i := range res.words {
+		var a, b uint64
+		if i < len(bs.words) {
+			a = bs.words[i]
+		}
+		if i < len(other.words) {
+			b = other.words[i]
+		}
+		res.words[i] = op(a, b)
+	}
+	return res
+}
+
+// And returns a new set of bs & other
+func (bs *BitSet) And(other *BitSet) *BitSet {
+	return bs.binary(other, func(a, b uint64) uint64 { return a & b })
+}
+
+// Or returns a new set of bs | other
+func (bs *BitSet) Or(other *BitSet) *BitSet {
+	return bs.binary(other, func(a, b uint64) uint64 { return a | b })
+}
+
+// Xor returns a new set of bs ^ other
+func (bs *BitSet) Xor(other *BitSet) *BitSet {
+	return bs.binary(other, func(a, b uint64) uint64 { return a ^ b })
+}
+
+// AndNot returns a new set of bs &^ other
+func (bs *BitSet) AndNot(other *BitSet) *BitSet {
+	return bs.binary(other, func(a, b uint64) uint64 { return a &^ b })
+}
+
+// InPlaceAnd sets bs to bs & other
+func (bs *BitSet) InPlaceAnd(other *BitSet) {
+	for i := range bs.words {
+		if i < len(other.words) {
+			bs.words[i] &= other.words[i]
+		} else {
+			bs.words[i] = 0
+		}
+	}
+}
+
+// InPlaceOr sets bs to bs | other
+func (bs *BitSet) InPlaceOr(other *BitSet) {
+	if other.length > bs.length {
+		bs.grow(other.length - 1)
+	}
+	for i := range other.words {
+		bs.words[i] |= other.words[i]
+	}
+}
+
+// InPlaceXor sets bs to bs ^ other
+func (bs *BitSet) InPlaceXor(other *BitSet) {
+	if other.length > bs.length {
+		bs.grow(other.length - 1)
+	}
+	for i := range other.words {
+		bs.words[i] ^= other.words[i]
+	}
+}
+
+// InPlaceAndNot sets bs to bs &^ other
+func (bs *BitSet) InPlaceAndNot(other *BitSet) {
+	for i := range bs.words {
+		if i >= len(other.words) {
+			return
+		}
+		bs.words[i] &^= other.words[i]
+	}
+}
+
+// String for pretty print
+func (bs *BitSet) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	first := true
+	bs.EachBit(func(i uint) bool {
+		if !first {
+			buf.WriteString(" ")
+		}
+		first = false
+		_, _ = fmt.Fprintf(&buf, "%d", i)
+		return true
+	})
+	buf.WriteString("}")
+	return buf.String()
+}
+
+// Size returns number of bits set to 1
+func (bs *BitSet) Size() int {
+	return int(bs.Count())
+}
+
+// Empty returns true if no bit is set to 1
+func (bs *BitSet) Empty() bool {
+	for _, w := range bs.words {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Clear sets all bits to 0, length is kept
+func (bs *BitSet) Clear() {
+	for i := range bs.words {
+		bs.words[i] = 0
+	}
+}
+
+// Values returns indexes (uint) of all bits set to 1 in ascending order
+func (bs *BitSet) Values() []interface{} {
+	values := make([]interface{}, 0, bs.Count())
+	bs.EachBit(func(i uint) bool {
+		values = append(values, i)
+		return true
+	})
+	return values
+}
+
+// Each calls f on indexes of bits set to 1 as uint in ascending order until f returns false
+func (bs *BitSet) Each(f func(value interface{}) bool) {
+	bs.EachBit(func(i uint) bool {
+		return f(i)
+	})
+}
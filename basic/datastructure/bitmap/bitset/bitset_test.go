@@ -0,0 +1,117 @@
+package bitset
+
+import (
+	"godev/basic"
+	"math/rand"
+	"testing"
+)
+
+func TestNewBitSet(t *testing.T) {
+	var _ basic.Container = (*BitSet)(nil)
+
+	bs := NewBitSet(100)
+	if !bs.Empty() || bs.Size() != 0 || bs.Len() != 100 || len(bs.Values()) != 0 || bs.Test(10) || bs.Test(1000) {
+		t.Fail()
+	}
+	if _, found := bs.NextSet(0); found {
+		t.Fail()
+	}
+}
+
+func TestBitSet_Set(t *testing.T) {
+	bs := NewBitSet(0)
+	bs.Set(1).Set(64).Set(200).Flip(3).Flip(1)
+	if !bs.Test(64) || !bs.Test(200) || !bs.Test(3) || bs.Test(1) || bs.Len() != 201 || bs.Count() != 3 {
+		t.Fail()
+	}
+	bs.Unset(64).Unset(1000).SetTo(5, true).SetTo(200, false)
+	if bs.String() != "{3 5}" || bs.Size() != 2 {
+		t.Fail()
+	}
+
+	bs.Clear()
+	if !bs.Empty() || bs.Len() != 201 {
+		t.Fail()
+	}
+}
+
+func TestBitSet_RankAndIteration(t *testing.T) {
+	bs := NewBitSet(0)
+	expected := make([]uint, 0)
+	for i := uint(0); i < 1000; i++ {
+		if rand.Intn(3) == 0 {
+			bs.Set(i)
+			expected = append(expected, i)
+		}
+	}
+
+	indexes := bs.Indexes()
+	if len(indexes) != len(expected) || bs.Count() != uint(len(expected)) {
+		t.Fatal()
+	}
+	for i := range indexes {
+		if indexes[i] != expected[i] || bs.Rank(expected[i]) != uint(i+1) {
+			t.Fail()
+		}
+	}
+	if bs.Rank(10000) != uint(len(expected)) {
+		t.Fail()
+	}
+
+	i := 0
+	for idx, found := bs.NextSet(0); found; idx, found = bs.NextSet(idx + 1) {
+		if idx != expected[i] {
+			t.Fail()
+		}
+		i++
+	}
+	if i != len(expected) {
+		t.Fail()
+	}
+
+	cnt := 0
+	bs.EachBit(func(uint) bool {
+		cnt++
+		return cnt < 5
+	})
+	if cnt != 5 {
+		t.Fail()
+	}
+}
+
+func TestBitSet_Algebra(t *testing.T) {
+	a := NewBitSetFromIndexes(1, 2, 3, 100, 200)
+	b := NewBitSetFromIndexes(2, 3, 4, 300)
+
+	if a.And(b).String() != "{2 3}" || a.Or(b).String() != "{1 2 3 4 100 200 300}" {
+		t.Fail()
+	}
+	if a.Xor(b).String() != "{1 4 100 200 300}" || a.AndNot(b).String() != "{1 100 200}" {
+		t.Fail()
+	}
+
+	c := a.Clone()
+	c.InPlaceAnd(b)
+	if !c.Equal(a.And(b)) {
+		t.Fail()
+	}
+	c = a.Clone()
+	c.InPlaceOr(b)
+	if !c.Equal(a.Or(b)) {
+		t.Fail()
+	}
+	c = a.Clone()
+	c.InPlaceXor(b)
+	if !c.Equal(a.Xor(b)) {
+		t.Fail()
+	}
+	c = a.Clone()
+	c.InPlaceAndNot(b)
+	if !c.Equal(a.AndNot(b)) {
+		t.Fail()
+	}
+
+	if a.Equal(b) || !NewBitSet(1000).Equal(NewBitSet(0)) || !a.Equal(a.Clone().Unset(1).Set(1)) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,287 @@
+package roaring
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// arrayMaxSize is the max cardinality of array container, above it bitmap container uses less memory
+//
+//	4096 * 2 bytes == 1024 * 8 bytes
+const (
+	arrayMaxSize = 4096
+	bitmapWords  = 1024
+)
+
+// container stores low 16 bits of values sharing the same high 16 bits
+type container interface {
+	add(x uint16) container
+	remove(x uint16) container
+	contains(x uint16) bool
+	cardinality() int
+	// rank returns number of values <= x
+	rank(x uint16) int
+	each(f func(x uint16) bool) bool
+	clone() container
+	toBitmap() *bitmapContainer
+}
+
+// arrayContainer keeps sorted values for sparse chunks
+type arrayContainer struct {
+	values []uint16
+}
+
+func (ac *arrayContainer) search(x uint16) int {
+	return sort.Search(len(ac.values), func(i int) bool { return ac.values[i] >= x })
+}
+
+func (ac *arrayContainer) add(x uint16) container {
+	i := ac.search(x)
+	if i < len(ac.values) && ac.values[i] == x {
+		return ac
+	}
+	if len(ac.values) >= arrayMaxSize {
+		return ac.toBitmap().add(x)
+	}
+	ac.values = append(ac.values, 0)
+	copy(ac.values[i+1:], ac.values[i:])
+	ac.values[i] = x
+	return ac
+}
+
+func (ac *arrayContainer) remove(x uint16) container {
+	i := ac.search(x)
+	if i < len(ac.values) && ac.values[i] == x {
+		ac.values = append(ac.values[:i], ac.values[i+1:]...)
+	}
+	return ac
+}
+
+func (ac *arrayContainer) contains(x uint16) bool {
+	i := ac.search(x)
+	return i < len(ac.values) && ac.values[i] == x
+}
+
+func (ac *arrayContainer) cardinality() int {
+	return len(ac.values)
+}
+
+func (ac *arrayContainer) rank(x uint16) int {
+	return sort.Search(len(ac.values), func(i int) bool { return ac.values[i] > x })
+}
+
+func (ac *arrayContainer) each(f func(x uint16) bool) bool {
+	for _, v := range ac.values {
+		if !f(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (ac *arrayContainer) clone() container {
+	values := make([]uint16, len(ac.values))
+	copy(values, ac.values)
+	return &arrayContainer{values: values}
+}
+
+func (ac *arrayContainer) toBitmap() *bitmapContainer {
+	bc := &bitmapContainer{}
+	for _, v := range ac.values {
+		bc.words[v>>6] |= 1 << (v & 63)
+	}
+	bc.card = len(ac.values)
+	return bc
+}
+
+// bitmapContainer keeps a 2^16 bits bitmap for dense chunks
+type bitmapContainer struct {
+	words [bitmapWords]uint64
+	card  int
+}
+
+func (bc *bitmapContainer) add(x uint16) container {
+	mask := uint64(1) << (x & 63)
+	if bc.words[x>>6]&mask == 0 {
+		bc.words[x>>6] |= mask
+		bc.card++
+	}
+	return bc
+}
+
+func (bc *bitmapContainer) remove(x uint16) container {
+	mask := uint64(1) << (x & 63)
+	if bc.words[x>>6]&mask != 0 {
+		bc.words[x>>6] &^= mask
+		bc.card--
+		if bc.card <= arrayMaxSize {
+			return bc.toArray()
+		}
+	}
+	return bc
+}
+
+func (bc *bitmapContainer) contains(x uint16) bool {
+	return bc.words[x>>6]&(1<<(x&63)) != 0
+}
+
+func (bc *bitmapContainer) cardinality() int {
+	return bc.card
+}
+
+func (bc *bitmapContainer) rank(x uint16) int {
+	idx := int(x >> 6)
+	cnt := 0
+	for _, w := range bc.words[:idx] {
+		cnt += bits.OnesCount64(w)
+	}
+	return cnt + bits.OnesCount64(bc.words[idx]&(^uint64(0)>>(63-(x&63))))
+}
+
+func (bc *bitmapContainer) each(f func(x uint16) bool) bool {
+	for idx, w := range bc.words {
+		for w != 0 {
+			if !f(uint16(idx<<6 + bits.TrailingZeros64(w))) {
+				return false
+			}
+			w &= w - 1
+		}
+	}
+	return true
+}
+
+func (bc *bitmapContainer) clone() container {
+	cp := *bc
+	return &cp
+}
+
+func (bc *bitmapContainer) toBitmap() *bitmapContainer {
+	return bc
+}
+
+func (bc *bitmapContainer) toArray() *arrayContainer {
+	ac := &arrayContainer{values: make([]uint16, 0, bc.card)}
+	bc.each(func(x uint16) bool {
+		ac.values = append(ac.values, x)
+		return true
+	})
+	return ac
+}
+
+// normalize recounts cardinality and converts to array container if sparse enough
+func (bc *bitmapContainer) normalize() container {
+	bc.card = 0
+	for _, w := range bc.words {
+		bc.card += bits.OnesCount64(w)
+	}
+	if bc.card <= arrayMaxSize {
+		return bc.toArray()
+	}
+	return bc
+}
+
+// mergeArrays walks two sorted arrays, keeps values according to which sides they come from
+func mergeArrays(a, b []uint16, keepLeft, keepBoth, keepRight bool) []uint16 {
+	res := make([]uint16, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			if keepLeft {
+				res = append(res, a[i])
+			}
+			i++
+		case a[i] > b[j]:
+			if keepRight {
+				res = append(res, b[j])
+			}
+			j++
+		default:
+			if keepBoth {
+				res = append(res, a[i])
+			}
+			i++
+			j++
+		}
+	}
+	if keepLeft {
+		res = append(res, a[i:]...)
+	}
+	if keepRight {
+		res = append(res, b[j:]...)
+	}
+	return res
+}
+
+// fromArray returns array container, or bitmap container if too large
+func fromArray(values []uint16) container {
+	ac := &arrayContainer{values: values}
+	if len(values) > arrayMaxSize {
+		return ac.toBitmap()
+	}
+	return ac
+}
+
+func bitmapOp(a, b container, op func(x, y uint64) uint64) container {
+	x, y := a.toBitmap(), b.toBitmap()
+	res := &bitmapContainer{}
+	for i := range res.words {
+		res.words[i] = op(x.words[i], y.words[i])
+	}
+	return res.normalize()
+}
+
+func and(a, b container) container {
+	aa, aIsArray := a.(*arrayContainer)
+	ba, bIsArray := b.(*arrayContainer)
+	switch {
+	case aIsArray && bIsArray:
+		return &arrayContainer{values: mergeArrays(aa.values, ba.values, false, true, false)}
+	case aIsArray:
+		return filter(aa, b, true)
+	case bIsArray:
+		return filter(ba, a, true)
+	}
+	return bitmapOp(a, b, func(x, y uint64) uint64 { return x & y })
+}
+
+func or(a, b container) container {
+	aa, aIsArray := a.(*arrayContainer)
+	ba, bIsArray := b.(*arrayContainer)
+	if aIsArray && bIsArray {
+		return fromArray(mergeArrays(aa.values, ba.values, true, true, true))
+	}
+	return bitmapOp(a, b, func(x, y uint64) uint64 { return x | y })
+}
+
+func xor(a, b container) container {
+	aa, aIsArray := a.(*arrayContainer)
+	ba, bIsArray := b.(*arrayContainer)
+	if aIsArray && bIsArray {
+		return fromArray(mergeArrays(aa.values, ba.values, true, false, true))
+	}
+	return bitmapOp(a, b, func(x, y uint64) uint64 { return x ^ y })
+}
+
+func andNot(a, b container) container {
+	aa, aIsArray := a.(*arrayContainer)
+	ba, bIsArray := b.(*arrayContainer)
+	switch {
+	case aIsArray && bIsArray:
+		return &arrayContainer{values: mergeArrays(aa.values, ba.values, true, false, false)}
+	case aIsArray:
+		return filter(aa, b, false)
+	}
+	return bitmapOp(a, b, func(x, y uint64) uint64 { return x &^ y })
+}
+
+// filter keeps values of ac whether in c or not according to keep
+func filter(ac *arrayContainer, c container, keep bool) container {
+	values := make([]uint16, 0, len(ac.values))
+	for _, v := range ac.values {
+		if c.contains(v) == keep {
+			values = append(values, v)
+		}
+	}
+	return &arrayContainer{values: values}
+}
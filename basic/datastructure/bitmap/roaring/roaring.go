@@ -0,0 +1,445 @@
+package roaring
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// references:
+// https://roaringbitmap.org/
+// Chambi, S. et al. (2016). "Better bitmap performance with Roaring bitmaps"
+
+// Bitmap struct is a compressed bitmap of uint32
+//
+//	values are split by high 16 bits into chunks, each chunk stores low 16 bits
+//	in a sorted array (sparse) or a bitmap (dense)
+type Bitmap struct {
+	keys       []uint16
+	containers []container
+}
+
+// NewBitmap creates a new bitmap with input values
+func NewBitmap(values ...uint32) *Bitmap {
+	rb := &Bitmap{}
+	for _, v := range values {
+		rb.Add(v)
+	}
+	return rb
+}
+
+func highLow(x uint32) (uint16, uint16) {
+	return uint16(x >> 16), uint16(x)
+}
+
+// index returns position of key, or position to insert if not found
+func (rb *Bitmap) index(key uint16) (int, bool) {
+	i := sort.Search(len(rb.keys), func(i int) bool { return rb.keys[i] >= key })
+	return i, i < len(rb.keys) && rb.keys[i] == key
+}
+
+// Add adds x into the bitmap
+func (rb *Bitmap) Add(x uint32) {
+	hi, lo := highLow(x)
+	i, found := rb.index(hi)
+	if found {
+		rb.containers[i] = rb.containers[i].add(lo)
+		return
+	}
+	rb.keys = append(rb.keys, 0)
+	copy(rb.keys[i+1:], rb.keys[i:])
+	rb.keys[i] = hi
+	rb.containers = append(rb.containers, nil)
+	copy(rb.containers[i+1:], rb.containers[i:])
+	rb.containers[i] = &arrayContainer{values: []uint16{lo}}
+}
+
+// AddMany adds values into the bitmap
+func (rb *Bitmap) AddMany(values ...uint32) {
+	for _, v := range values {
+		rb.Add(v)
+	}
+}
+
+// Remove removes x from the bitmap, returns true if found
+func (rb *Bitmap) Remove(x uint32) bool {
+	hi, lo := highLow(x)
+	i, found := rb.index(hi)
+	if !found || !rb.containers[i].contains(lo) {
+		return false
+	}
+	rb.containers[i] = rb.containers[i].remove(lo)
+	if rb.containers[i].cardinality() == 0 {
+		rb.keys = append(rb.keys[:i], rb.keys[i+1:]...)
+		rb.containers = append(rb.containers[:i], rb.containers[i+1:]...)
+	}
+	return true
+}
+
+// Contains returns true if x inside the bitmap
+func (rb *Bitmap) Contains(x uint32) bool {
+	hi, lo := highLow(x)
+	i, found := rb.index(hi)
+	return found && rb.containers[i].contains(lo)
+}
+
+// Cardinality returns number of values inside the bitmap
+func (rb *Bitmap) Cardinality() uint64 {
+	cnt := uint64(0)
+	for _, c := range rb.containers {
+		cnt += uint64(c.cardinality())
+	}
+	return cnt
+}
+
+// Rank returns number of values <= x
+func (rb *Bitmap) Rank(x uint32) uint64 {
+	hi, lo := highLow(x)
+	cnt := uint64(0)
+	for i, key := range rb.keys {
+		if key > hi {
+			break
+		}
+		if key < hi {
+			cnt += uint64(rb.containers[i].cardinality())
+		} else {
+			cnt += uint64(rb.containers[i].rank(lo))
+		}
+	}
+	return cnt
+}
+
+// Min returns the minimum value
+func (rb *Bitmap) Min() (uint32, bool) {
+	if len(rb.keys) == 0 {
+		return 0, false
+	}
+	var min uint16
+	rb.containers[0].each(func(x uint16) bool {
+		min = x
+		return false
+	})
+	return uint32(rb.keys[0])<<16 | uint32(min), true
+}
+
+// Max returns the maximum value
+func (rb *Bitmap) Max() (uint32, bool) {
+	if len(rb.keys) == 0 {
+		return 0, false
+	}
+	last := len(rb.keys) - 1
+	var max uint16
+	rb.containers[last].each(func(x uint16) bool {
+		max = x
+		return true
+	})
+	return uint32(rb.keys[last])<<16 | uint32(max), true
+}
+
+// EachUint32 calls f on every value in ascending order until f returns false
+func (rb *Bitmap) EachUint32(f func(x uint32) bool) {
+	for i, c := range rb.containers {
+		hi := uint32(rb.keys[i]) << 16
+		if !c.each(func(lo uint16) bool {
+			return f(hi | uint32(lo))
+		}) {
+			return
+		}
+	}
+}
+
+// ToArray returns all values in ascending order
+func (rb *Bitmap) ToArray() []uint32 {
+	values := make([]uint32, 0, rb.Cardinality())
+	rb.EachUint32(func(x uint32) bool {
+		values = append(values, x)
+		return true
+	})
+	return values
+}
+
+// Clone returns a deep copy of the bitmap
+func (rb *Bitmap) Clone() *Bitmap {
+	cp := &Bitmap{
+		keys:       make([]uint16, len(rb.keys)),
+		containers: make([]container, len(rb.containers)),
+	}
+	copy(cp.keys, rb.keys)
+	for i, c := range rb.containers {
+		cp.containers[i] = c.clone()
+	}
+	return cp
+}
+
+// Equal returns true if two bitmaps have same values
+func (rb *Bitmap) Equal(other *Bitmap) bool {
+	if len(rb.keys) != len(other.keys) {
+		return false
+	}
+	for i := range rb.keys {
+		if rb.keys[i] != other.keys[i] || rb.containers[i].cardinality() != other.containers[i].cardinality() {
+			return false
+		}
+		if andNot(rb.containers[i], other.containers[i]).cardinality() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (rb *Bitmap) appendContainer(key uint16, c container) {
+	if c.cardinality() != 0 {
+		rb.keys = append(rb.keys, key)
+		rb.containers = append(rb.containers, c)
+	}
+}
+
+// combine merges chunks of two bitmaps by key
+func (rb *Bitmap) combine(other *Bitmap, op func(a, b container) container, keepLeft, keepRight bool) *Bitmap {
+	res := &Bitmap{}
+	i, j := 0, 0
+	for i < len(rb.keys) && j < len(other.keys) {
+		switch {
+		case rb.keys[i] < other.keys[j]:
+			if keepLeft {
+				res.appendContainer(rb.keys[i], rb.containers[i].clone())
+			}
+			i++
+		case rb.keys[i] > other.keys[j]:
+			if keepRight {
+				res.appendContainer(other.keys[j], other.containers[j].clone())
+			}
+			j++
+		default:
+			res.appendContainer(rb.keys[i], op(rb.containers[i], other.containers[j]))
+			i++
+			j++
+		}
+	}
+	for ; keepLeft && i < len(rb.keys); i++ {
+		res.appendContainer(rb.keys[i], rb.containers[i].clone())
+	}
+	for ; keepRight && j < len(other.keys); j++ {
+		res.appendContainer(other.keys[j], other.containers[j].clone())
+	}
+	return res
+}
+
+// And returns a new bitmap of values in both bitmaps
+func (rb *Bitmap) And(other *Bitmap) *Bitmap {
+	return rb.combine(other, and, false, false)
+}
+
+// Or returns a new bitmap of values in either bitmap
+func (rb *Bitmap) Or(other *Bitmap) *Bitmap {
+	return rb.combine(other, or, true, true)
+}
+
+// Xor returns a new bitmap of values in only one of the bitmaps
+func (rb *Bitmap) Xor(other *Bitmap) *Bitmap {
+	return rb.combine(other, xor, true, true)
+}
+
+// AndNot returns a new bitmap of values in rb but not in other
+func (rb *Bitmap) AndNot(other *Bitmap) *Bitmap {
+	return rb.combine(other, andNot, true, false)
+}
+
+// Size returns number of values inside the bitmap
+func (rb *Bitmap) Size() int {
+	return int(rb.Cardinality())
+}
+
+// Empty returns true if no value inside the bitmap
+func (rb *Bitmap) Empty() bool {
+	return len(rb.keys) == 0
+}
+
+// Clear clears the bitmap
+func (rb *Bitmap) Clear() {
+	*rb = Bitmap{}
+}
+
+// Values returns all values (uint32) in ascending order
+func (rb *Bitmap) Values() []interface{} {
+	values := make([]interface{}, 0, rb.Cardinality())
+	rb.EachUint32(func(x uint32) bool {
+		values = append(values, x)
+		return true
+	})
+	return values
+}
+
+// Each calls f on every value as uint32 in ascending order until f returns false
+func (rb *Bitmap) Each(f func(value interface{}) bool) {
+	rb.EachUint32(func(x uint32) bool {
+		return f(x)
+	})
+}
+
+// String for pretty print
+func (rb *Bitmap) String() string {
+	return fmt.Sprintf("%v", rb.ToArray())
+}
+
+/*
+serialization format, all numbers in little endian:
+
+	magic          uint32
+	containers     uint32
+	for every container:
+		key            uint16
+		type           uint8 (0: array, 1: bitmap)
+		cardinality    uint32
+		data           array: cardinality * uint16, bitmap: 1024 * uint64
+*/
+const (
+	serialMagic = uint32(0x524f4152) // "ROAR"
+	typeArray   = uint8(0)
+	typeBitmap  = uint8(1)
+)
+
+// ErrInvalidFormat is returned when deserializing malformed data
+var ErrInvalidFormat = errors.New("roaring: invalid serialized format")
+
+// WriteTo writes serialized bitmap into w
+func (rb *Bitmap) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	cw := &countWriter{w: bw}
+	write := func(data interface{}) {
+		if cw.err == nil {
+			cw.err = binary.Write(cw, binary.LittleEndian, data)
+		}
+	}
+
+	write(serialMagic)
+	write(uint32(len(rb.keys)))
+	for i, c := range rb.containers {
+		write(rb.keys[i])
+		switch c := c.(type) {
+		case *arrayContainer:
+			write(typeArray)
+			write(uint32(len(c.values)))
+			write(c.values)
+		case *bitmapContainer:
+			write(typeBitmap)
+			write(uint32(c.card))
+			write(c.words[:])
+		}
+	}
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	return cw.n, bw.Flush()
+}
+
+// ReadFrom reads serialized bitmap from r, replaces current content
+func (rb *Bitmap) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countReader{r: r}
+	read := func(data interface{}) {
+		if cr.err == nil {
+			cr.err = binary.Read(cr, binary.LittleEndian, data)
+		}
+	}
+
+	var magic, n uint32
+	read(&magic)
+	read(&n)
+	if cr.err != nil {
+		return cr.n, cr.err
+	}
+	if magic != serialMagic {
+		return cr.n, ErrInvalidFormat
+	}
+
+	res := Bitmap{}
+	for i := uint32(0); i < n; i++ {
+		var key uint16
+		var typ uint8
+		var card uint32
+		read(&key)
+		read(&typ)
+		read(&card)
+		if cr.err != nil {
+			return cr.n, cr.err
+		}
+		if (len(res.keys) > 0 && key <= res.keys[len(res.keys)-1]) || card == 0 || card > 1<<16 {
+			return cr.n, ErrInvalidFormat
+		}
+		switch typ {
+		case typeArray:
+			if card > arrayMaxSize {
+				return cr.n, ErrInvalidFormat
+			}
+			ac := &arrayContainer{values: make([]uint16, card)}
+			read(ac.values)
+			for j := 1; cr.err == nil && j < len(ac.values); j++ {
+				if ac.values[j-1] >= ac.values[j] {
+					return cr.n, ErrInvalidFormat
+				}
+			}
+			res.keys = append(res.keys, key)
+			res.containers = append(res.containers, ac)
+		case typeBitmap:
+			bc := &bitmapContainer{}
+			read(bc.words[:])
+			c := bc.normalize()
+			if cr.err == nil && c.cardinality() == 0 {
+				return cr.n, ErrInvalidFormat
+			}
+			res.keys = append(res.keys, key)
+			res.containers = append(res.containers, c)
+		default:
+			return cr.n, ErrInvalidFormat
+		}
+	}
+	if cr.err != nil {
+		if cr.err == io.EOF {
+			cr.err = io.ErrUnexpectedEOF
+		}
+		return cr.n, cr.err
+	}
+	*rb = res
+	return cr.n, nil
+}
+
+// MarshalBinary to meet encoding.BinaryMarshaler
+func (rb *Bitmap) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	_, err := rb.WriteTo(&buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary to meet encoding.BinaryUnmarshaler
+func (rb *Bitmap) UnmarshalBinary(data []byte) error {
+	_, err := rb.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
+type countWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+type countReader struct {
+	r   io.Reader
+	n   int64
+	err error
+}
+
+func (cr *countReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,214 @@
+package roaring
+
+import (
+	"bytes"
+	"godev/basic"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// randomValues returns sorted distinct values, dense in some chunks and sparse in others
+func randomValues(n int) []uint32 {
+	m := make(map[uint32]struct{}, n)
+	for len(m) < n {
+		switch rand.Intn(3) {
+		case 0:
+			m[rand.Uint32()] = struct{}{}
+		case 1:
+			m[uint32(rand.Intn(1<<16))] = struct{}{} // dense chunk 0
+		default:
+			m[3<<16|uint32(rand.Intn(1<<14))] = struct{}{} // dense chunk 3
+		}
+	}
+	values := make([]uint32, 0, n)
+	for v := range m {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	return values
+}
+
+func toSet(values []uint32) map[uint32]bool {
+	m := make(map[uint32]bool, len(values))
+	for _, v := range values {
+		m[v] = true
+	}
+	return m
+}
+
+func checkBitmap(t *testing.T, rb *Bitmap, expected map[uint32]bool) {
+	values := rb.ToArray()
+	if len(values) != len(expected) || rb.Cardinality() != uint64(len(expected)) {
+		t.Fatal(len(values), len(expected))
+	}
+	for i, v := range values {
+		if !expected[v] || (i > 0 && values[i-1] >= v) {
+			t.Fatal(v)
+		}
+	}
+}
+
+func TestNewBitmap(t *testing.T) {
+	var _ basic.Container = (*Bitmap)(nil)
+
+	rb := NewBitmap()
+	if !rb.Empty() || rb.Size() != 0 || len(rb.Values()) != 0 || rb.Contains(0) || rb.Remove(0) {
+		t.Fail()
+	}
+	if _, found := rb.Min(); found {
+		t.Fail()
+	}
+	if _, found := rb.Max(); found {
+		t.Fail()
+	}
+
+	rb = NewBitmap(5, 1, 1<<20, 3)
+	if rb.Size() != 4 || !rb.Contains(1<<20) || rb.Contains(2) || rb.String() != "[1 3 5 1048576]" {
+		t.Fail()
+	}
+	if min, _ := rb.Min(); min != 1 {
+		t.Fail()
+	}
+	if max, _ := rb.Max(); max != 1<<20 {
+		t.Fail()
+	}
+	if rb.Rank(0) != 0 || rb.Rank(3) != 2 || rb.Rank(1<<19) != 3 || rb.Rank(1<<30) != 4 {
+		t.Fail()
+	}
+	rb.Clear()
+	if !rb.Empty() {
+		t.Fail()
+	}
+}
+
+func TestBitmap_AddRemove(t *testing.T) {
+	values := randomValues(20000)
+	rb := NewBitmap()
+	rb.AddMany(values...)
+	rb.AddMany(values[:100]...)
+	expected := toSet(values)
+	checkBitmap(t, rb, expected)
+
+	if _, ok := rb.containers[0].(*bitmapContainer); !ok {
+		t.Error("dense chunk should be bitmap container")
+	}
+
+	for i, v := range values {
+		if rb.Rank(v) != uint64(i+1) {
+			t.Fatal(i)
+		}
+	}
+
+	rand.Shuffle(len(values), func(i, j int) { values[i], values[j] = values[j], values[i] })
+	for _, v := range values[:15000] {
+		if !rb.Remove(v) || rb.Remove(v) {
+			t.Fatal(v)
+		}
+		delete(expected, v)
+	}
+	checkBitmap(t, rb, expected)
+	if _, ok := rb.containers[0].(*arrayContainer); !ok {
+		t.Error("sparse chunk should be array container")
+	}
+}
+
+func TestBitmap_Algebra(t *testing.T) {
+	a, b := randomValues(20000), randomValues(20000)
+	ra, rb := NewBitmap(a...), NewBitmap(b...)
+	sa, sb := toSet(a), toSet(b)
+
+	and, or, xor, andNot := map[uint32]bool{}, map[uint32]bool{}, map[uint32]bool{}, map[uint32]bool{}
+	for v := range sa {
+		or[v] = true
+		if sb[v] {
+			and[v] = true
+		} else {
+			xor[v] = true
+			andNot[v] = true
+		}
+	}
+	for v := range sb {
+		or[v] = true
+		if !sa[v] {
+			xor[v] = true
+		}
+	}
+
+	checkBitmap(t, ra.And(rb), and)
+	checkBitmap(t, ra.Or(rb), or)
+	checkBitmap(t, ra.Xor(rb), xor)
+	checkBitmap(t, ra.AndNot(rb), andNot)
+
+	// sparse with dense
+	sparse := NewBitmap(1, 2, 3<<16|5, 7<<16)
+	checkBitmap(t, sparse.And(ra), toSet(intersect([]uint32{1, 2, 3<<16 | 5, 7 << 16}, sa)))
+	if !ra.And(ra).Equal(ra) || !ra.Xor(ra).Empty() || !ra.Or(NewBitmap()).Equal(ra) || ra.Equal(rb) {
+		t.Fail()
+	}
+
+	// inputs untouched
+	checkBitmap(t, ra, sa)
+	checkBitmap(t, rb, sb)
+}
+
+func intersect(values []uint32, set map[uint32]bool) []uint32 {
+	var res []uint32
+	for _, v := range values {
+		if set[v] {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+func TestBitmap_Serialization(t *testing.T) {
+	values := randomValues(20000)
+	rb := NewBitmap(values...)
+
+	data, err := rb.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := NewBitmap(1)
+	if err = other.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !other.Equal(rb) {
+		t.Fail()
+	}
+	checkBitmap(t, other, toSet(values))
+
+	var buf bytes.Buffer
+	n, err := NewBitmap().WriteTo(&buf)
+	if err != nil || n != int64(buf.Len()) {
+		t.Fail()
+	}
+	empty := NewBitmap(1)
+	if _, err = empty.ReadFrom(&buf); err != nil || !empty.Empty() {
+		t.Fail()
+	}
+
+	if err = other.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Fail()
+	}
+	data[0] = 0
+	if err = other.UnmarshalBinary(data); err != ErrInvalidFormat {
+		t.Fail()
+	}
+}
+
+func BenchmarkBitmap_Add(b *testing.B) {
+	rb := NewBitmap()
+	for i := 0; i < b.N; i++ {
+		rb.Add(uint32(i))
+	}
+}
+
+func BenchmarkBitmap_And(b *testing.B) {
+	x, y := NewBitmap(randomValues(100000)...), NewBitmap(randomValues(100000)...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.And(y)
+	}
+}
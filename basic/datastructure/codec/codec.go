@@ -0,0 +1,255 @@
+// Package codec encodes containers and maps to JSON or gob and decodes them back
+//	a maps.Map is written as its entries in iteration order, so a tree map keeps its key order and a linked hash map
+//	keeps its insertion (or access) order once the entries are set back in sequence,
+//	any other basic.Container is written as its Values()
+package codec
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"godev/basic"
+	"godev/basic/datastructure/maps"
+	"io"
+	"reflect"
+)
+
+// Format of encoded data
+type Format int
+
+const (
+	// JSON writes a json document with type names beside every key and value
+	JSON Format = iota
+	// Gob writes a gob stream, key and value types are transmitted as gob interface values
+	Gob
+)
+
+func (f Format) String() string {
+	switch f {
+	case JSON:
+		return "json"
+	case Gob:
+		return "gob"
+	default:
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+}
+
+// document kinds
+const (
+	kindMap    = "map"
+	kindValues = "values"
+)
+
+var (
+	// ErrFormat is returned for an unknown format
+	ErrFormat = errors.New("codec: unknown format")
+	// ErrKind is returned when decoding a map document into a container or vice versa
+	ErrKind = errors.New("codec: document kind mismatch")
+	// ErrCorrupted is returned when a document is malformed
+	ErrCorrupted = errors.New("codec: corrupted document")
+)
+
+// Codec encodes / decodes containers in one format with types from one registry
+type Codec struct {
+	format   Format
+	registry *Registry
+}
+
+// New returns a codec, DefaultRegistry is used if registry is nil
+func New(format Format, registry *Registry) *Codec {
+	if registry == nil {
+		registry = DefaultRegistry
+	}
+	return &Codec{
+		format:   format,
+		registry: registry,
+	}
+}
+
+// document is the format independent form of an encoded container
+//	Keys is only set for maps and always has the same length as Values
+type document struct {
+	Kind   string
+	Keys   []interface{}
+	Values []interface{}
+}
+
+// entries returns map entries in iteration order
+func entries(m maps.Map) (keys, values []interface{}) {
+	it := m.Iterator()
+	for it.HasNext() {
+		k, v := it.Next()
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	return keys, values
+}
+
+// Encode writes container to w
+//	every non-nil key / value type has to be registered
+func (c *Codec) Encode(w io.Writer, container basic.Container) error {
+	if c.format != JSON && c.format != Gob {
+		return ErrFormat
+	}
+	doc := document{Kind: kindValues}
+	if m, ok := container.(maps.Map); ok {
+		doc.Kind = kindMap
+		doc.Keys, doc.Values = entries(m)
+	} else {
+		doc.Values = container.Values()
+	}
+	for _, vs := range [][]interface{}{doc.Keys, doc.Values} {
+		for _, v := range vs {
+			if v == nil {
+				continue
+			}
+			if _, err := c.registry.nameOf(v); err != nil {
+				return err
+			}
+		}
+	}
+
+	if c.format == JSON {
+		return c.encodeJSON(w, &doc)
+	}
+	return gob.NewEncoder(w).Encode(&doc)
+}
+
+// DecodeMap reads a map document from r and sets its entries into m in encoded order
+func (c *Codec) DecodeMap(r io.Reader, m maps.Map) error {
+	doc, err := c.decode(r)
+	if err != nil {
+		return err
+	}
+	if doc.Kind != kindMap {
+		return ErrKind
+	}
+	for i := range doc.Keys {
+		m.Set(doc.Keys[i], doc.Values[i])
+	}
+	return nil
+}
+
+// Decode reads a container document from r and calls add for every value in encoded order
+//	e.g. codec.Decode(r, dq.PushBack) or codec.Decode(r, bag.Add)
+func (c *Codec) Decode(r io.Reader, add func(value interface{})) error {
+	doc, err := c.decode(r)
+	if err != nil {
+		return err
+	}
+	if doc.Kind != kindValues {
+		return ErrKind
+	}
+	for _, v := range doc.Values {
+		add(v)
+	}
+	return nil
+}
+
+func (c *Codec) decode(r io.Reader) (*document, error) {
+	var doc *document
+	var err error
+	switch c.format {
+	case JSON:
+		doc, err = c.decodeJSON(r)
+	case Gob:
+		doc = new(document)
+		err = gob.NewDecoder(r).Decode(doc)
+	default:
+		return nil, ErrFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	if doc.Kind == kindMap && len(doc.Keys) != len(doc.Values) ||
+		doc.Kind == kindValues && len(doc.Keys) != 0 {
+		return nil, ErrCorrupted
+	}
+	return doc, nil
+}
+
+// jsonValue is a json value tagged with its registered type name, nil is encoded as json null
+type jsonValue struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+type jsonDocument struct {
+	Kind   string       `json:"kind"`
+	Keys   []*jsonValue `json:"keys,omitempty"`
+	Values []*jsonValue `json:"values"`
+}
+
+func (c *Codec) encodeJSON(w io.Writer, doc *document) error {
+	jd := jsonDocument{Kind: doc.Kind}
+	var err error
+	if jd.Keys, err = c.marshalValues(doc.Keys); err != nil {
+		return err
+	}
+	if jd.Values, err = c.marshalValues(doc.Values); err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(&jd)
+}
+
+func (c *Codec) marshalValues(values []interface{}) ([]*jsonValue, error) {
+	if values == nil {
+		return nil, nil
+	}
+	jvs := make([]*jsonValue, len(values))
+	for i, v := range values {
+		if v == nil {
+			continue
+		}
+		name, err := c.registry.nameOf(v)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		jvs[i] = &jsonValue{Type: name, Value: raw}
+	}
+	return jvs, nil
+}
+
+func (c *Codec) decodeJSON(r io.Reader) (*document, error) {
+	var jd jsonDocument
+	if err := json.NewDecoder(r).Decode(&jd); err != nil {
+		return nil, err
+	}
+	doc := &document{Kind: jd.Kind}
+	var err error
+	if doc.Keys, err = c.unmarshalValues(jd.Keys); err != nil {
+		return nil, err
+	}
+	if doc.Values, err = c.unmarshalValues(jd.Values); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func (c *Codec) unmarshalValues(jvs []*jsonValue) ([]interface{}, error) {
+	if jvs == nil {
+		return nil, nil
+	}
+	values := make([]interface{}, len(jvs))
+	for i, jv := range jvs {
+		if jv == nil {
+			continue
+		}
+		t, err := c.registry.typeOf(jv.Type)
+		if err != nil {
+			return nil, err
+		}
+		p := reflect.New(t)
+		if err := json.Unmarshal(jv.Value, p.Interface()); err != nil {
+			return nil, err
+		}
+		values[i] = p.Elem().Interface()
+	}
+	return values, nil
+}
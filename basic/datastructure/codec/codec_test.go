@@ -0,0 +1,209 @@
+package codec
+
+import (
+	"bytes"
+	"godev/basic"
+	"godev/basic/datastructure/bag"
+	"godev/basic/datastructure/heap"
+	"godev/basic/datastructure/heap/pairing"
+	"godev/basic/datastructure/maps/linkedhashmap"
+	"godev/basic/datastructure/maps/treemap"
+	"godev/basic/datastructure/queue/deque"
+	"reflect"
+	"testing"
+)
+
+type point struct {
+	X, Y int
+}
+
+type item struct {
+	Priority int
+	Name     string
+}
+
+func (it *item) Compare(ait heap.Item) int {
+	return basic.IntComparator(it.Priority, ait.(*item).Priority)
+}
+
+var formats = []Format{JSON, Gob}
+
+func newRegistry(t *testing.T) *Registry {
+	r := NewRegistry()
+	if err := r.Register("codec.point", point{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register("codec.item", &item{}); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRegistry_Register(t *testing.T) {
+	r := newRegistry(t)
+	// same pair again is fine
+	if err := r.Register("codec.point", point{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register("codec.point", item{}); err == nil {
+		t.Fail()
+	}
+	if err := r.Register("codec.point2", point{}); err == nil {
+		t.Fail()
+	}
+	if err := r.Register("nil", nil); err == nil {
+		t.Fail()
+	}
+	if name, err := r.nameOf(int64(1)); err != nil || name != "int64" {
+		t.Fail()
+	}
+}
+
+func TestCodec_TreeMap(t *testing.T) {
+	for _, format := range formats {
+		c := New(format, newRegistry(t))
+		m := treemap.NewMap(basic.StringComparator)
+		keys := []string{"m", "c", "x", "a", "q", "z", "b"}
+		for i, k := range keys {
+			m.Set(k, point{i, -i})
+		}
+		m.Set("nil", nil)
+
+		var buf bytes.Buffer
+		if err := c.Encode(&buf, m); err != nil {
+			t.Fatal(format, err)
+		}
+		decoded := treemap.NewMap(basic.StringComparator)
+		if err := c.DecodeMap(&buf, decoded); err != nil {
+			t.Fatal(format, err)
+		}
+		if !reflect.DeepEqual(m.Keys(), decoded.Keys()) || !reflect.DeepEqual(m.Values(), decoded.Values()) {
+			t.Fatal(format, decoded.Keys(), decoded.Values())
+		}
+	}
+}
+
+func TestCodec_LinkedHashMap(t *testing.T) {
+	for _, format := range formats {
+		c := New(format, nil)
+		m := linkedhashmap.NewLinkedHashMapWithOrder(true, nil)
+		for i := 0; i < 10; i++ {
+			m.Set(i, float64(i)/2)
+		}
+		m.Get(3)
+		m.Get(0)
+
+		var buf bytes.Buffer
+		if err := c.Encode(&buf, m); err != nil {
+			t.Fatal(format, err)
+		}
+		decoded := linkedhashmap.NewLinkedHashMap()
+		if err := c.DecodeMap(bytes.NewReader(buf.Bytes()), decoded); err != nil {
+			t.Fatal(format, err)
+		}
+		// encoding must not touch access order
+		if !reflect.DeepEqual(m.Keys(), decoded.Keys()) || !reflect.DeepEqual(m.Values(), decoded.Values()) {
+			t.Fatal(format, m.Keys(), decoded.Keys())
+		}
+		if k, _, _ := decoded.Back(); k.(int) != 0 {
+			t.Fail()
+		}
+
+		// map document into a container
+		if err := c.Decode(bytes.NewReader(buf.Bytes()), func(interface{}) {}); err != ErrKind {
+			t.Fail()
+		}
+	}
+}
+
+func TestCodec_Containers(t *testing.T) {
+	for _, format := range formats {
+		c := New(format, newRegistry(t))
+
+		dq := deque.NewDeque(16)
+		for i := 0; i < 10; i++ {
+			dq.PushFront(i)
+		}
+		dq.PushBack("back")
+		var buf bytes.Buffer
+		if err := c.Encode(&buf, dq); err != nil {
+			t.Fatal(format, err)
+		}
+		decodedDq := deque.NewDeque(16)
+		if err := c.Decode(&buf, decodedDq.PushBack); err != nil {
+			t.Fatal(format, err)
+		}
+		if !reflect.DeepEqual(dq.Values(), decodedDq.Values()) {
+			t.Fatal(format, decodedDq.Values())
+		}
+
+		h := pairing.NewHeap()
+		for _, p := range []int{5, 3, 8, 1, 9} {
+			h.Insert(&item{p, string(rune('a' + p))})
+		}
+		buf.Reset()
+		if err := c.Encode(&buf, h); err != nil {
+			t.Fatal(format, err)
+		}
+		decodedH := pairing.NewHeap()
+		if err := c.Decode(&buf, func(v interface{}) { decodedH.Insert(v.(*item)) }); err != nil {
+			t.Fatal(format, err)
+		}
+		for _, p := range []int{1, 3, 5, 8, 9} {
+			it := decodedH.DeleteMin().(*item)
+			if it.Priority != p || it.Name != string(rune('a'+p)) {
+				t.Fatal(format, it)
+			}
+		}
+
+		b := bag.NewOrderedBag(basic.IntComparator)
+		for i := 0; i < 5; i++ {
+			b.Add(i % 3)
+		}
+		buf.Reset()
+		if err := c.Encode(&buf, b); err != nil {
+			t.Fatal(format, err)
+		}
+		decodedB := bag.NewOrderedBag(basic.IntComparator)
+		if err := c.Decode(&buf, decodedB.Add); err != nil {
+			t.Fatal(format, err)
+		}
+		if !reflect.DeepEqual(b.Values(), decodedB.Values()) {
+			t.Fatal(format, decodedB.Values())
+		}
+
+		// container document into a map
+		buf.Reset()
+		_ = c.Encode(&buf, b)
+		if err := c.DecodeMap(&buf, treemap.NewMap(basic.IntComparator)); err != ErrKind {
+			t.Fail()
+		}
+	}
+}
+
+func TestCodec_Unregistered(t *testing.T) {
+	for _, format := range formats {
+		m := treemap.NewMap(basic.IntComparator)
+		m.Set(1, point{1, 2})
+		var buf bytes.Buffer
+		err := New(format, nil).Encode(&buf, m)
+		if err == nil || err.Error() != (UnregisteredTypeError{"codec.point"}).Error() || buf.Len() != 0 {
+			t.Fatal(format, err)
+		}
+	}
+
+	// decoding with a registry that lacks the type
+	var buf bytes.Buffer
+	m := treemap.NewMap(basic.IntComparator)
+	m.Set(1, point{1, 2})
+	if err := New(JSON, newRegistry(t)).Encode(&buf, m); err != nil {
+		t.Fatal(err)
+	}
+	if err := New(JSON, NewRegistry()).DecodeMap(&buf, treemap.NewMap(basic.IntComparator)); err == nil {
+		t.Fail()
+	}
+
+	if err := New(Format(10), nil).Encode(&buf, m); err != ErrFormat {
+		t.Fail()
+	}
+}
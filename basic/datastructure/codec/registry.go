@@ -0,0 +1,115 @@
+package codec
+
+import (
+	"encoding/gob"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Registry maps type names to concrete types of keys and values
+//	both encoders write the registered name beside every key / value so that decoding can restore the concrete type,
+//	builtin scalar types (bool, string, ints, uints, floats and []byte) are registered under their go names
+type Registry struct {
+	sync.RWMutex
+	types map[string]reflect.Type
+	names map[reflect.Type]string
+}
+
+// DefaultRegistry is used by codecs created without a registry
+var DefaultRegistry = NewRegistry()
+
+var builtins = []interface{}{
+	false, "", []byte(nil),
+	int(0), int8(0), int16(0), int32(0), int64(0),
+	uint(0), uint8(0), uint16(0), uint32(0), uint64(0),
+	float32(0), float64(0),
+}
+
+// NewRegistry returns a registry with builtin types registered
+func NewRegistry() *Registry {
+	r := &Registry{
+		types: make(map[string]reflect.Type),
+		names: make(map[reflect.Type]string),
+	}
+	for _, v := range builtins {
+		t := reflect.TypeOf(v)
+		r.types[t.String()] = t
+		r.names[t] = t.String()
+	}
+	return r
+}
+
+// Register registers the concrete type of value under name
+//	the type is registered to gob with the same name as well, registering the same pair twice is a no-op
+func (r *Registry) Register(name string, value interface{}) error {
+	if value == nil {
+		return fmt.Errorf("codec: cannot register nil value as %q", name)
+	}
+	t := reflect.TypeOf(value)
+	r.Lock()
+	defer r.Unlock()
+	if old, ok := r.types[name]; ok {
+		if old == t {
+			return nil
+		}
+		return fmt.Errorf("codec: name %q already registered for type %v", name, old)
+	}
+	if old, ok := r.names[t]; ok {
+		return fmt.Errorf("codec: type %v already registered as %q", t, old)
+	}
+	if err := registerGob(name, value); err != nil {
+		return err
+	}
+	r.types[name] = t
+	r.names[t] = name
+	return nil
+}
+
+// Register registers the concrete type of value under name in DefaultRegistry
+func Register(name string, value interface{}) error {
+	return DefaultRegistry.Register(name, value)
+}
+
+// registerGob recovers gob's panic on conflicting registration
+func registerGob(name string, value interface{}) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("codec: %v", e)
+		}
+	}()
+	gob.RegisterName(name, value)
+	return nil
+}
+
+// nameOf returns registered name of the concrete type of v
+func (r *Registry) nameOf(v interface{}) (string, error) {
+	t := reflect.TypeOf(v)
+	r.RLock()
+	defer r.RUnlock()
+	name, ok := r.names[t]
+	if !ok {
+		return "", UnregisteredTypeError{t.String()}
+	}
+	return name, nil
+}
+
+// typeOf returns the type registered under name
+func (r *Registry) typeOf(name string) (reflect.Type, error) {
+	r.RLock()
+	defer r.RUnlock()
+	t, ok := r.types[name]
+	if !ok {
+		return nil, UnregisteredTypeError{name}
+	}
+	return t, nil
+}
+
+// UnregisteredTypeError is returned when a key or value type is unknown to the registry
+type UnregisteredTypeError struct {
+	typ string
+}
+
+func (e UnregisteredTypeError) Error() string {
+	return fmt.Sprintf("codec: type %s is not registered", e.typ)
+}
@@ -58,6 +58,15 @@ func (h *Heap) Values() []interface{} {
 	return h.values
 }
 
+// Each visits values inside the heap in array order until f returns false
+func (h *Heap) Each(f func(value interface{}) bool) {
+	for _, v := range h.values {
+		if !f(v) {
+			return
+		}
+	}
+}
+
 func (h *Heap) set(i int, v interface{}) {
 	if i > len(h.values)-1 || i < 0 {
 		panic("invalid index")
@@ -121,6 +130,11 @@ func (h *MinHeap) Values() []interface{} {
 	return h.heap.values
 }
 
+// Each visits values inside the heap in array order until f returns false
+func (h *MinHeap) Each(f func(value interface{}) bool) {
+	h.heap.Each(f)
+}
+
 // MaxHeap heap stored maximum value in its root
 type MaxHeap struct {
 	heap       *Heap
@@ -178,3 +192,8 @@ func (h *MaxHeap) Clear() {
 func (h *MaxHeap) Values() []interface{} {
 	return h.heap.values
 }
+
+// Each visits values inside the heap in array order until f returns false
+func (h *MaxHeap) Each(f func(value interface{}) bool) {
+	h.heap.Each(f)
+}
@@ -0,0 +1,138 @@
+package dary
+
+import (
+	"godev/basic/datastructure/heap"
+)
+
+// DefaultArity is used when arity passed to NewHeap is less than 2
+const DefaultArity = 4
+
+// Heap (d-ary Heap) struct
+//	https://en.wikipedia.org/wiki/D-ary_heap
+//	every node has d children, the tree is shallower than binary heap,
+//	so Insert / decrease is faster and children of a node are adjacent in memory (cache friendly),
+//	DeleteMin compares d children per level: O(d log_d n)
+type Heap struct {
+	items []heap.Item
+	d     int
+}
+
+// NewHeap creates a new empty d-ary heap
+func NewHeap(d int) *Heap {
+	if d < 2 {
+		d = DefaultArity
+	}
+	return &Heap{
+		items: nil,
+		d:     d,
+	}
+}
+
+// Arity returns number of children of every node
+func (h *Heap) Arity() int {
+	return h.d
+}
+
+// FindMin returns the minimum item inside the heap
+func (h *Heap) FindMin() heap.Item {
+	if len(h.items) == 0 {
+		return nil
+	}
+	return h.items[0]
+}
+
+// DeleteMin deletes and returns the minimum item inside the heap
+func (h *Heap) DeleteMin() heap.Item {
+	n := len(h.items)
+	if n == 0 {
+		return nil
+	}
+	min := h.items[0]
+	h.items[0] = h.items[n-1]
+	// avoid memory leak
+	h.items[n-1] = nil
+	h.items = h.items[:n-1]
+	if n > 1 {
+		h.down(0)
+	}
+	return min
+}
+
+// Insert inserts an item into the heap
+func (h *Heap) Insert(item heap.Item) {
+	h.items = append(h.items, item)
+	h.up(len(h.items) - 1)
+}
+
+func (h *Heap) up(i int) {
+	item := h.items[i]
+	for i > 0 {
+		parent := (i - 1) / h.d
+		if item.Compare(h.items[parent]) >= 0 {
+			break
+		}
+		h.items[i] = h.items[parent]
+		i = parent
+	}
+	h.items[i] = item
+}
+
+func (h *Heap) down(i int) {
+	n := len(h.items)
+	item := h.items[i]
+	for {
+		first := i*h.d + 1
+		if first >= n {
+			break
+		}
+		last := first + h.d
+		if last > n {
+			last = n
+		}
+		min := first
+		for c := first + 1; c < last; c++ {
+			if h.items[c].Compare(h.items[min]) < 0 {
+				min = c
+			}
+		}
+		if h.items[min].Compare(item) >= 0 {
+			break
+		}
+		h.items[i] = h.items[min]
+		i = min
+	}
+	h.items[i] = item
+}
+
+// Size returns the number of items inside the heap
+func (h *Heap) Size() int {
+	return len(h.items)
+}
+
+// Empty returns true if no item inside the heap
+func (h *Heap) Empty() bool {
+	return len(h.items) == 0
+}
+
+// Clear clears the heap
+func (h *Heap) Clear() {
+	h.items = nil
+}
+
+// Values returns items inside the heap (unordered)
+func (h *Heap) Values() []interface{} {
+	values := make([]interface{}, len(h.items))
+	for i := range h.items {
+		values[i] = h.items[i]
+	}
+	return values
+}
+
+// Each visits items inside the heap in array order until f returns false
+func (h *Heap) Each(f func(value interface{}) bool) {
+	for _, it := range h.items {
+		if !f(it) {
+			return
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package dary
+
+import (
+	"godev/basic/datastructure/heap"
+	"godev/utils"
+	"math"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+type item int
+
+func (it item) Compare(ait heap.Item) int {
+	if it > ait.(item) {
+		return 1
+	} else if it == ait.(item) {
+		return 0
+	} else {
+		return -1
+	}
+}
+
+func TestNewHeap(t *testing.T) {
+	var _ heap.Heap = (*Heap)(nil)
+	h := NewHeap(0)
+	if h.Arity() != DefaultArity || !h.Empty() || h.FindMin() != nil || h.DeleteMin() != nil {
+		t.Fail()
+	}
+}
+
+func TestHeap(t *testing.T) {
+	for d := 2; d <= 8; d++ {
+		h := NewHeap(d)
+		var a []int
+		for i := 0; i < 1000; i++ {
+			rn := utils.GenerateRandomInt() % 500
+			a = append(a, rn)
+			h.Insert(item(rn))
+		}
+		if h.Size() != len(a) || len(h.Values()) != len(a) {
+			t.Fail()
+		}
+		sort.Ints(a)
+		if h.FindMin().(item) != item(a[0]) {
+			t.Fail()
+		}
+		for i := range a {
+			if h.DeleteMin().(item) != item(a[i]) {
+				t.Fatal(d, i)
+			}
+		}
+		if !h.Empty() {
+			t.Fail()
+		}
+
+		h.Insert(item(1))
+		h.Clear()
+		if !h.Empty() || h.Size() != 0 || h.FindMin() != nil {
+			t.Fail()
+		}
+	}
+}
+
+func BenchmarkHeap(b *testing.B) {
+	for k := 1.0; k <= 5; k++ {
+		n := int(math.Pow(10, k))
+
+		h := NewHeap(DefaultArity)
+		for i := 0; i < n; i++ {
+			h.Insert(item(utils.GenerateRandomInt()))
+		}
+
+		b.ResetTimer()
+
+		b.Run("D-ary Heap DeleteMin + Insert (min): size-"+strconv.Itoa(n), func(b *testing.B) {
+			for i := 1; i < b.N; i++ {
+				min := h.DeleteMin()
+				h.Insert(min)
+			}
+		})
+	}
+}
@@ -509,6 +509,27 @@ func (h *Heap) traverse(startNode *node, nodes *[]*node) {
 	}
 }
 
+func (h *Heap) each(startNode *node, f func(value interface{}) bool) bool {
+	if startNode == nil {
+		return true
+	}
+	n := startNode
+	for {
+		if !f(n.item) || !h.each(n.child, f) {
+			return false
+		}
+		n = n.right
+		if n == startNode {
+			return true
+		}
+	}
+}
+
+// Each visits items in the same order as Values until f returns false
+func (h *Heap) Each(f func(value interface{}) bool) {
+	h.each(h.root, f)
+}
+
 // Print prints heap items
 func (h *Heap) Print() {
 	fmt.Println("***  Fibonacci Heap  ***")
@@ -17,6 +17,9 @@ type Heap interface {
 	Empty() bool
 	Clear()
 	Values() []interface{}
+
+	// Each visits items in the same order as Values until f returns false, to meet `basic.Iterable`
+	Each(f func(value interface{}) bool)
 }
 
 // Item interface stands for item stored inside heap
@@ -0,0 +1,85 @@
+package heap_test
+
+import (
+	"godev/basic/datastructure/heap"
+	"godev/basic/datastructure/heap/dary"
+	"godev/basic/datastructure/heap/fibonacci"
+	"godev/basic/datastructure/heap/minmax"
+	"godev/basic/datastructure/heap/pairing"
+	"godev/basic/datastructure/heap/radix"
+	"godev/utils"
+	"math"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+// item meets both heap.Item and radix.Item
+type item uint64
+
+func (it item) Compare(ait heap.Item) int {
+	if it > ait.(item) {
+		return 1
+	} else if it == ait.(item) {
+		return 0
+	} else {
+		return -1
+	}
+}
+
+func (it item) Key() uint64 {
+	return uint64(it)
+}
+
+var heaps = []struct {
+	name    string
+	newHeap func() heap.Heap
+}{
+	{"Pairing Heap", func() heap.Heap { return pairing.NewHeap() }},
+	{"Fibonacci Heap", func() heap.Heap { return fibonacci.NewHeap() }},
+	{"Binary Heap", func() heap.Heap { return dary.NewHeap(2) }},
+	{"4-ary Heap", func() heap.Heap { return dary.NewHeap(4) }},
+	{"8-ary Heap", func() heap.Heap { return dary.NewHeap(8) }},
+	{"Min-Max Heap", func() heap.Heap { return minmax.NewHeap() }},
+	{"Radix Heap", func() heap.Heap { return radix.NewHeap() }},
+}
+
+func TestHeaps(t *testing.T) {
+	for _, hp := range heaps {
+		h := hp.newHeap()
+		var a []int
+		for i := 0; i < 500; i++ {
+			rn := utils.GenerateRandomIntInRange(0, 1000)
+			a = append(a, rn)
+			h.Insert(item(rn))
+		}
+		sort.Ints(a)
+		for i := range a {
+			if h.FindMin().(item) != item(a[i]) || h.DeleteMin().(item) != item(a[i]) {
+				t.Fatal(hp.name, i)
+			}
+		}
+		if !h.Empty() || h.Size() != 0 {
+			t.Fatal(hp.name)
+		}
+	}
+}
+
+func BenchmarkHeaps(b *testing.B) {
+	for k := 1.0; k <= 5; k++ {
+		n := int(math.Pow(10, k))
+		for _, hp := range heaps {
+			h := hp.newHeap()
+			for i := 0; i < n; i++ {
+				h.Insert(item(utils.GenerateRandomIntInRange(0, math.MaxInt32)))
+			}
+
+			b.Run(hp.name+" DeleteMin + Insert (min): size-"+strconv.Itoa(n), func(b *testing.B) {
+				for i := 1; i < b.N; i++ {
+					min := h.DeleteMin()
+					h.Insert(min)
+				}
+			})
+		}
+	}
+}
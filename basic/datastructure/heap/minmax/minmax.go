@@ -0,0 +1,224 @@
+package minmax
+
+import (
+	"godev/basic/datastructure/heap"
+)
+
+// Heap (Min-Max Heap) struct
+//	https://en.wikipedia.org/wiki/Min-max_heap
+//	nodes on even levels are less than their descendants, nodes on odd levels are greater than their descendants,
+//	so both minimum (root) and maximum (one of root's children) can be found in O(1) and deleted in O(log n),
+//	which makes it a double-ended priority queue
+type Heap struct {
+	items []heap.Item
+}
+
+// NewHeap creates a new empty min-max heap
+func NewHeap() *Heap {
+	return &Heap{
+		items: nil,
+	}
+}
+
+// isMinLevel returns true if index i is on an even level
+func isMinLevel(i int) bool {
+	level := 0
+	for i++; i > 1; i >>= 1 {
+		level++
+	}
+	return level%2 == 0
+}
+
+func (h *Heap) less(i, j int) bool {
+	return h.items[i].Compare(h.items[j]) < 0
+}
+
+func (h *Heap) swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+}
+
+// FindMin returns the minimum item inside the heap
+func (h *Heap) FindMin() heap.Item {
+	if len(h.items) == 0 {
+		return nil
+	}
+	return h.items[0]
+}
+
+// FindMax returns the maximum item inside the heap
+func (h *Heap) FindMax() heap.Item {
+	i := h.maxIndex()
+	if i < 0 {
+		return nil
+	}
+	return h.items[i]
+}
+
+func (h *Heap) maxIndex() int {
+	switch len(h.items) {
+	case 0:
+		return -1
+	case 1:
+		return 0
+	case 2:
+		return 1
+	}
+	if h.less(1, 2) {
+		return 2
+	}
+	return 1
+}
+
+// DeleteMin deletes and returns the minimum item inside the heap
+func (h *Heap) DeleteMin() heap.Item {
+	if len(h.items) == 0 {
+		return nil
+	}
+	return h.delete(0)
+}
+
+// DeleteMax deletes and returns the maximum item inside the heap
+func (h *Heap) DeleteMax() heap.Item {
+	i := h.maxIndex()
+	if i < 0 {
+		return nil
+	}
+	return h.delete(i)
+}
+
+func (h *Heap) delete(i int) heap.Item {
+	item := h.items[i]
+	last := len(h.items) - 1
+	h.items[i] = h.items[last]
+	// avoid memory leak
+	h.items[last] = nil
+	h.items = h.items[:last]
+	if i < last {
+		h.down(i)
+	}
+	return item
+}
+
+// Insert inserts an item into the heap
+func (h *Heap) Insert(item heap.Item) {
+	h.items = append(h.items, item)
+	h.up(len(h.items) - 1)
+}
+
+func (h *Heap) up(i int) {
+	if i == 0 {
+		return
+	}
+	parent := (i - 1) / 2
+	if isMinLevel(i) {
+		if h.less(parent, i) {
+			h.swap(i, parent)
+			h.upMax(parent)
+		} else {
+			h.upMin(i)
+		}
+	} else {
+		if h.less(i, parent) {
+			h.swap(i, parent)
+			h.upMin(parent)
+		} else {
+			h.upMax(i)
+		}
+	}
+}
+
+func (h *Heap) upMin(i int) {
+	// compare with grandparent
+	for i > 2 {
+		g := ((i-1)/2 - 1) / 2
+		if !h.less(i, g) {
+			break
+		}
+		h.swap(i, g)
+		i = g
+	}
+}
+
+func (h *Heap) upMax(i int) {
+	for i > 2 {
+		g := ((i-1)/2 - 1) / 2
+		if !h.less(g, i) {
+			break
+		}
+		h.swap(i, g)
+		i = g
+	}
+}
+
+func (h *Heap) down(i int) {
+	if isMinLevel(i) {
+		h.downLevel(i, h.less)
+	} else {
+		h.downLevel(i, func(a, b int) bool { return h.less(b, a) })
+	}
+}
+
+// downLevel trickles down item i, before is `less` on min levels and `greater` on max levels
+func (h *Heap) downLevel(i int, before func(a, b int) bool) {
+	n := len(h.items)
+	for {
+		// find the first (by before) one among children and grandchildren
+		m := -1
+		for c := 2*i + 1; c <= 2*i+2 && c < n; c++ {
+			if m < 0 || before(c, m) {
+				m = c
+			}
+			for g := 2*c + 1; g <= 2*c+2 && g < n; g++ {
+				if before(g, m) {
+					m = g
+				}
+			}
+		}
+		if m < 0 || !before(m, i) {
+			return
+		}
+		h.swap(m, i)
+		if m <= 2*i+2 {
+			// child
+			return
+		}
+		// grandchild, keep its parent on the other kind of level in order
+		if p := (m - 1) / 2; before(p, m) {
+			h.swap(m, p)
+		}
+		i = m
+	}
+}
+
+// Size returns the number of items inside the heap
+func (h *Heap) Size() int {
+	return len(h.items)
+}
+
+// Empty returns true if no item inside the heap
+func (h *Heap) Empty() bool {
+	return len(h.items) == 0
+}
+
+// Clear clears the heap
+func (h *Heap) Clear() {
+	h.items = nil
+}
+
+// Values returns items inside the heap (unordered)
+func (h *Heap) Values() []interface{} {
+	values := make([]interface{}, len(h.items))
+	for i := range h.items {
+		values[i] = h.items[i]
+	}
+	return values
+}
+
+// Each visits items inside the heap in array order until f returns false
+func (h *Heap) Each(f func(value interface{}) bool) {
+	for _, it := range h.items {
+		if !f(it) {
+			return
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package minmax
+
+import (
+	"godev/basic/datastructure/heap"
+	"godev/utils"
+	"math"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+type item int
+
+func (it item) Compare(ait heap.Item) int {
+	if it > ait.(item) {
+		return 1
+	} else if it == ait.(item) {
+		return 0
+	} else {
+		return -1
+	}
+}
+
+func TestNewHeap(t *testing.T) {
+	var _ heap.Heap = (*Heap)(nil)
+	h := NewHeap()
+	if !h.Empty() || h.FindMin() != nil || h.FindMax() != nil || h.DeleteMin() != nil || h.DeleteMax() != nil {
+		t.Fail()
+	}
+}
+
+func TestHeap(t *testing.T) {
+	h := NewHeap()
+	a := []item{12, 7, 25, 15, 28, 33, 41, 1}
+	for i := range a {
+		h.Insert(a[i])
+	}
+	if h.FindMin().(item) != 1 || h.FindMax().(item) != 41 || h.Size() != 8 {
+		t.Fail()
+	}
+	if h.DeleteMax().(item) != 41 || h.DeleteMin().(item) != 1 || h.FindMax().(item) != 33 || h.FindMin().(item) != 7 {
+		t.Fail()
+	}
+	h.Clear()
+	if !h.Empty() || h.FindMin() != nil {
+		t.Fail()
+	}
+}
+
+func TestHeap_DoubleEnded(t *testing.T) {
+	h := NewHeap()
+	var a []int
+	for i := 0; i < 2000; i++ {
+		rn := utils.GenerateRandomInt() % 1000
+		a = append(a, rn)
+		h.Insert(item(rn))
+	}
+	if len(h.Values()) != len(a) {
+		t.Fail()
+	}
+	sort.Ints(a)
+	// pop from both ends alternately
+	lo, hi := 0, len(a)-1
+	for i := 0; !h.Empty(); i++ {
+		if i%3 == 0 {
+			if h.DeleteMax().(item) != item(a[hi]) {
+				t.Fatal(i)
+			}
+			hi--
+		} else {
+			if h.DeleteMin().(item) != item(a[lo]) {
+				t.Fatal(i)
+			}
+			lo++
+		}
+	}
+	if lo != hi+1 {
+		t.Fail()
+	}
+}
+
+func BenchmarkHeap(b *testing.B) {
+	for k := 1.0; k <= 5; k++ {
+		n := int(math.Pow(10, k))
+
+		h := NewHeap()
+		for i := 0; i < n; i++ {
+			h.Insert(item(utils.GenerateRandomInt()))
+		}
+
+		b.ResetTimer()
+
+		b.Run("Min-Max Heap DeleteMin + Insert (min): size-"+strconv.Itoa(n), func(b *testing.B) {
+			for i := 1; i < b.N; i++ {
+				min := h.DeleteMin()
+				h.Insert(min)
+			}
+		})
+
+		b.Run("Min-Max Heap DeleteMax + Insert (max): size-"+strconv.Itoa(n), func(b *testing.B) {
+			for i := 1; i < b.N; i++ {
+				max := h.DeleteMax()
+				h.Insert(max)
+			}
+		})
+	}
+}
@@ -266,3 +266,26 @@ func (n *node) traverseChildren(nodes *[]*node) {
 		child.traverseChildren(nodes)
 	}
 }
+
+// eachChildren visits sub-heaps in the same order as traverseChildren
+func (n *node) eachChildren(f func(value interface{}) bool) bool {
+	for _, child := range n.children {
+		if !f(child.item) {
+			return false
+		}
+	}
+	for _, child := range n.children {
+		if !child.eachChildren(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// Each visits items in the same order as Values until f returns false
+func (h *Heap) Each(f func(value interface{}) bool) {
+	if h.root == nil || !f(h.root.item) {
+		return
+	}
+	h.root.eachChildren(f)
+}
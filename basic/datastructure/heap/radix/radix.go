@@ -0,0 +1,150 @@
+package radix
+
+import (
+	"godev/basic/datastructure/heap"
+	"math/bits"
+)
+
+// Item interface stands for item stored inside radix heap, it has an unsigned integer key
+type Item interface {
+	heap.Item
+	Key() uint64
+}
+
+// Heap (Radix Heap) struct
+//	https://en.wikipedia.org/wiki/Radix_heap
+//	a monotone priority queue for integer keys: key of inserted item must not be less than
+//	key of the last deleted minimum, e.g. Dijkstra with non-negative weights, event schedulers.
+//	items are kept in buckets by highest bit that differs from last deleted key,
+//	every item moves to lower buckets at most 64 times, Insert: O(1), DeleteMin: O(log C) amortized
+type Heap struct {
+	buckets [65][]Item
+	// last deleted key
+	last    uint64
+	itemNum int
+}
+
+// NewHeap creates a new empty radix heap
+func NewHeap() *Heap {
+	return &Heap{}
+}
+
+func (h *Heap) bucket(key uint64) int {
+	return bits.Len64(key ^ h.last)
+}
+
+// Last returns key of the last deleted minimum, inserted key must not be less than it
+func (h *Heap) Last() uint64 {
+	return h.last
+}
+
+// Insert inserts an item into the heap
+//	it panics if item does not implement radix.Item or its key is less than Last()
+func (h *Heap) Insert(item heap.Item) {
+	it, ok := item.(Item)
+	if !ok {
+		panic("radix heap: item should implement radix.Item")
+	}
+	key := it.Key()
+	if key < h.last {
+		panic("radix heap: key is less than last deleted key")
+	}
+	b := h.bucket(key)
+	h.buckets[b] = append(h.buckets[b], it)
+	h.itemNum++
+}
+
+// firstBucket returns index of the first non-empty bucket, -1 if heap is empty
+func (h *Heap) firstBucket() int {
+	if h.itemNum == 0 {
+		return -1
+	}
+	for i := range h.buckets {
+		if len(h.buckets[i]) > 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// FindMin returns the minimum item inside the heap, O(size of the first non-empty bucket)
+func (h *Heap) FindMin() heap.Item {
+	b := h.firstBucket()
+	if b < 0 {
+		return nil
+	}
+	return h.buckets[b][minIndex(h.buckets[b])]
+}
+
+func minIndex(items []Item) int {
+	m := 0
+	for i := 1; i < len(items); i++ {
+		if items[i].Key() < items[m].Key() {
+			m = i
+		}
+	}
+	return m
+}
+
+// DeleteMin deletes and returns the minimum item inside the heap
+func (h *Heap) DeleteMin() heap.Item {
+	b := h.firstBucket()
+	if b < 0 {
+		return nil
+	}
+	if b > 0 {
+		// redistribute bucket b with the new last key, all of them go to lower buckets
+		items := h.buckets[b]
+		h.buckets[b] = nil
+		h.last = items[minIndex(items)].Key()
+		for _, it := range items {
+			nb := h.bucket(it.Key())
+			h.buckets[nb] = append(h.buckets[nb], it)
+		}
+	}
+	// all items in bucket 0 have key == last
+	items := h.buckets[0]
+	it := items[len(items)-1]
+	// avoid memory leak
+	items[len(items)-1] = nil
+	h.buckets[0] = items[:len(items)-1]
+	h.itemNum--
+	return it
+}
+
+// Size returns the number of items inside the heap
+func (h *Heap) Size() int {
+	return h.itemNum
+}
+
+// Empty returns true if no item inside the heap
+func (h *Heap) Empty() bool {
+	return h.itemNum == 0
+}
+
+// Clear clears the heap, last deleted key is reset to 0
+func (h *Heap) Clear() {
+	*h = Heap{}
+}
+
+// Values returns items inside the heap (unordered)
+func (h *Heap) Values() []interface{} {
+	values := make([]interface{}, 0, h.itemNum)
+	for i := range h.buckets {
+		for _, it := range h.buckets[i] {
+			values = append(values, it)
+		}
+	}
+	return values
+}
+
+// Each visits items bucket by bucket until f returns false
+func (h *Heap) Each(f func(value interface{}) bool) {
+	for i := range h.buckets {
+		for _, it := range h.buckets[i] {
+			if !f(it) {
+				return
+			}
+		}
+	}
+}
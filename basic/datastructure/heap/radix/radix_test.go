@@ -0,0 +1,135 @@
+package radix
+
+import (
+	"godev/basic/datastructure/heap"
+	"godev/utils"
+	"math"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+type item uint64
+
+func (it item) Compare(ait heap.Item) int {
+	if it > ait.(item) {
+		return 1
+	} else if it == ait.(item) {
+		return 0
+	} else {
+		return -1
+	}
+}
+
+func (it item) Key() uint64 {
+	return uint64(it)
+}
+
+type plain int
+
+func (p plain) Compare(ait heap.Item) int {
+	return int(p) - int(ait.(plain))
+}
+
+func TestNewHeap(t *testing.T) {
+	var _ heap.Heap = (*Heap)(nil)
+	h := NewHeap()
+	if !h.Empty() || h.FindMin() != nil || h.DeleteMin() != nil || h.Last() != 0 {
+		t.Fail()
+	}
+}
+
+func TestHeap(t *testing.T) {
+	h := NewHeap()
+	a := []item{12, 7, 25, 15, 28, 33, 41, 1, 7}
+	for i := range a {
+		h.Insert(a[i])
+	}
+	if h.FindMin().(item) != 1 || h.Size() != 9 || len(h.Values()) != 9 {
+		t.Fail()
+	}
+	if h.DeleteMin().(item) != 1 || h.DeleteMin().(item) != 7 || h.DeleteMin().(item) != 7 || h.Last() != 7 {
+		t.Fail()
+	}
+	// monotone: keys >= last are accepted
+	h.Insert(item(7))
+	h.Insert(item(8))
+	expected := []item{7, 8, 12, 15, 25, 28, 33, 41}
+	for i := range expected {
+		if h.FindMin().(item) != expected[i] || h.DeleteMin().(item) != expected[i] {
+			t.Fatal(i)
+		}
+	}
+	if !h.Empty() {
+		t.Fail()
+	}
+
+	h.Insert(item(math.MaxUint64))
+	if h.DeleteMin().(item) != math.MaxUint64 {
+		t.Fail()
+	}
+	h.Clear()
+	if !h.Empty() || h.Last() != 0 {
+		t.Fail()
+	}
+}
+
+func TestHeap_Panic(t *testing.T) {
+	expectPanic := func(f func()) {
+		defer func() {
+			if recover() == nil {
+				t.Fail()
+			}
+		}()
+		f()
+	}
+	h := NewHeap()
+	expectPanic(func() { h.Insert(plain(1)) })
+	h.Insert(item(10))
+	h.DeleteMin()
+	expectPanic(func() { h.Insert(item(9)) })
+}
+
+func TestHeap_Monotone(t *testing.T) {
+	// simulate an event scheduler: new events are always later than current time
+	h := NewHeap()
+	var popped []uint64
+	now := uint64(0)
+	for i := 0; i < 100; i++ {
+		h.Insert(item(now + uint64(utils.GenerateRandomIntInRange(0, 1000))))
+	}
+	for i := 0; i < 5000; i++ {
+		it := h.DeleteMin().(item)
+		now = uint64(it)
+		popped = append(popped, now)
+		if i < 4900 {
+			h.Insert(item(now + uint64(utils.GenerateRandomIntInRange(0, 1000))))
+		}
+		if h.Empty() {
+			break
+		}
+	}
+	if !sort.SliceIsSorted(popped, func(i, j int) bool { return popped[i] < popped[j] }) {
+		t.Fail()
+	}
+}
+
+func BenchmarkHeap(b *testing.B) {
+	for k := 1.0; k <= 5; k++ {
+		n := int(math.Pow(10, k))
+
+		h := NewHeap()
+		for i := 0; i < n; i++ {
+			h.Insert(item(utils.GenerateRandomInt()))
+		}
+
+		b.ResetTimer()
+
+		b.Run("Radix Heap DeleteMin + Insert (min): size-"+strconv.Itoa(n), func(b *testing.B) {
+			for i := 1; i < b.N; i++ {
+				min := h.DeleteMin()
+				h.Insert(min)
+			}
+		})
+	}
+}
@@ -175,7 +175,11 @@ func (sl *SkipList) Size() int {
 
 // Clear clears skip list
 func (sl *SkipList) Clear() {
-	sl.header = nil
+	sl.header = &node{
+		key:   nil,
+		value: nil,
+		next:  []*node{nil},
+	}
 	sl.itemNum = 1
 }
 
@@ -193,3 +197,37 @@ func (sl *SkipList) Values() []interface{} {
 	// skip nil header
 	return values[1:]
 }
+
+// Each visits values in key order until f returns false
+func (sl *SkipList) Each(f func(value interface{}) bool) {
+	for current := sl.header.next[0]; current != nil; current = current.next[0] {
+		if !f(current.value) {
+			return
+		}
+	}
+}
+
+// Iterator returns an iterator over values in key order
+//	notice: the skip list must not be modified during iteration
+func (sl *SkipList) Iterator() basic.Iterator {
+	return &iterator{current: sl.header.next[0]}
+}
+
+type iterator struct {
+	current *node
+}
+
+// HasNext to meet basic.Iterator interface
+func (iter *iterator) HasNext() bool {
+	return iter.current != nil
+}
+
+// Next to meet basic.Iterator interface
+func (iter *iterator) Next() interface{} {
+	if !iter.HasNext() {
+		return nil
+	}
+	v := iter.current.value
+	iter.current = iter.current.next[0]
+	return v
+}
@@ -0,0 +1,348 @@
+package concurrentmap
+
+import (
+	"fmt"
+	"godev/basic/datastructure/maps"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"sync"
+)
+
+// DefaultShardNum is used when shard number passed to NewMap is not positive
+const DefaultShardNum = 32
+
+// Hasher maps a key to uint64, equal keys must have equal hash
+type Hasher func(key interface{}) uint64
+
+// DefaultHasher hashes builtin types directly, other types are hashed on their `%#v` format,
+//	which is slow and not reliable for keys containing pointers or floats, provide a Hasher for them
+func DefaultHasher(key interface{}) uint64 {
+	switch k := key.(type) {
+	case string:
+		return hashString(k)
+	case int:
+		return mix(uint64(k))
+	case int8:
+		return mix(uint64(k))
+	case int16:
+		return mix(uint64(k))
+	case int32:
+		return mix(uint64(k))
+	case int64:
+		return mix(uint64(k))
+	case uint:
+		return mix(uint64(k))
+	case uint8:
+		return mix(uint64(k))
+	case uint16:
+		return mix(uint64(k))
+	case uint32:
+		return mix(uint64(k))
+	case uint64:
+		return mix(k)
+	case uintptr:
+		return mix(uint64(k))
+	case float32:
+		return hashFloat(float64(k))
+	case float64:
+		return hashFloat(k)
+	case bool:
+		if k {
+			return mix(1)
+		}
+		return mix(0)
+	default:
+		return hashString(fmt.Sprintf("%#v", key))
+	}
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum64()
+}
+
+func hashFloat(f float64) uint64 {
+	// +0 == -0
+	if f == 0 {
+		return mix(0)
+	}
+	return mix(math.Float64bits(f))
+}
+
+// mix is finalizer of splitmix64, spreads bits of integer keys over all shards
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+type shard struct {
+	sync.RWMutex
+	items map[interface{}]interface{}
+}
+
+// Map struct is a concurrency safe map split into shards, each shard has its own lock
+//	so goroutines working on different shards do not contend with each other
+type Map struct {
+	shards []*shard
+	mask   uint64
+	hasher Hasher
+}
+
+// NewMap creates a new concurrent map with DefaultHasher
+//	shardNum is rounded up to power of 2
+func NewMap(shardNum int) *Map {
+	return NewMapWithHasher(shardNum, DefaultHasher)
+}
+
+// NewMapWithHasher creates a new concurrent map with input hasher
+func NewMapWithHasher(shardNum int, hasher Hasher) *Map {
+	if shardNum <= 0 {
+		shardNum = DefaultShardNum
+	}
+	n := 1
+	for n < shardNum {
+		n <<= 1
+	}
+	m := &Map{
+		shards: make([]*shard, n),
+		mask:   uint64(n - 1),
+		hasher: hasher,
+	}
+	for i := range m.shards {
+		m.shards[i] = &shard{items: make(map[interface{}]interface{})}
+	}
+	return m
+}
+
+func (m *Map) getShard(key interface{}) *shard {
+	return m.shards[m.hasher(key)&m.mask]
+}
+
+// Set sets key value pairs
+func (m *Map) Set(key, value interface{}) {
+	s := m.getShard(key)
+	s.Lock()
+	s.items[key] = value
+	s.Unlock()
+}
+
+// Get gets value with input key if found inside map
+func (m *Map) Get(key interface{}) (value interface{}, found bool) {
+	s := m.getShard(key)
+	s.RLock()
+	value, found = s.items[key]
+	s.RUnlock()
+	return
+}
+
+// Delete deletes key value pairs
+func (m *Map) Delete(key interface{}) bool {
+	s := m.getShard(key)
+	s.Lock()
+	defer s.Unlock()
+	if _, found := s.items[key]; found {
+		delete(s.items, key)
+		return true
+	}
+	return false
+}
+
+// LoadOrStore returns existing value of key if present (loaded is true),
+//	otherwise stores and returns input value (loaded is false)
+func (m *Map) LoadOrStore(key, value interface{}) (actual interface{}, loaded bool) {
+	s := m.getShard(key)
+	s.Lock()
+	defer s.Unlock()
+	if actual, loaded = s.items[key]; loaded {
+		return
+	}
+	s.items[key] = value
+	return value, false
+}
+
+// LoadAndDelete deletes key and returns its previous value if present
+func (m *Map) LoadAndDelete(key interface{}) (value interface{}, loaded bool) {
+	s := m.getShard(key)
+	s.Lock()
+	defer s.Unlock()
+	if value, loaded = s.items[key]; loaded {
+		delete(s.items, key)
+	}
+	return
+}
+
+// CompareAndSwap sets key to newValue only if key exists and its value == oldValue
+//	values of uncomparable types such as slices are never equal, so it returns false for them
+func (m *Map) CompareAndSwap(key, oldValue, newValue interface{}) bool {
+	s := m.getShard(key)
+	s.Lock()
+	defer s.Unlock()
+	if value, found := s.items[key]; found && valueEqual(value, oldValue) {
+		s.items[key] = newValue
+		return true
+	}
+	return false
+}
+
+// CompareAndDelete deletes key only if key exists and its value == oldValue, uncomparable values are never equal
+func (m *Map) CompareAndDelete(key, oldValue interface{}) bool {
+	s := m.getShard(key)
+	s.Lock()
+	defer s.Unlock()
+	if value, found := s.items[key]; found && valueEqual(value, oldValue) {
+		delete(s.items, key)
+		return true
+	}
+	return false
+}
+
+// valueEqual returns a == b without panicking on uncomparable dynamic types, which are never equal
+func valueEqual(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	ta := reflect.TypeOf(a)
+	if ta != reflect.TypeOf(b) || !ta.Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// Compute atomically computes new value of key from its current value,
+//	f receives current value and whether key exists, returns new value and whether to keep it,
+//	if keep is false, the key is deleted
+//	f runs under shard lock, it must not access the map
+func (m *Map) Compute(key interface{}, f func(value interface{}, found bool) (newValue interface{}, keep bool)) (actual interface{}, ok bool) {
+	s := m.getShard(key)
+	s.Lock()
+	defer s.Unlock()
+	value, found := s.items[key]
+	newValue, keep := f(value, found)
+	if !keep {
+		delete(s.items, key)
+		return nil, false
+	}
+	s.items[key] = newValue
+	return newValue, true
+}
+
+// Range calls f on every key value pair until f returns false
+//	weakly consistent: each shard is copied under its lock, so f can modify the map safely,
+//	but modifications on shards not visited yet may or may not be seen
+func (m *Map) Range(f func(key, value interface{}) bool) {
+	for _, s := range m.shards {
+		keys, values := s.snapshot()
+		for i := range keys {
+			if !f(keys[i], values[i]) {
+				return
+			}
+		}
+	}
+}
+
+func (s *shard) snapshot() (keys, values []interface{}) {
+	s.RLock()
+	defer s.RUnlock()
+	keys = make([]interface{}, 0, len(s.items))
+	values = make([]interface{}, 0, len(s.items))
+	for k, v := range s.items {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	return
+}
+
+// Keys returns all keys, no order guaranteed
+func (m *Map) Keys() []interface{} {
+	var keys []interface{}
+	m.Range(func(key, _ interface{}) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Values returns all values, no order guaranteed
+func (m *Map) Values() []interface{} {
+	var values []interface{}
+	m.Range(func(_, value interface{}) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+// Size returns quantity of kv pairs inside map
+//	shards are counted one by one, so it is not a point-in-time result under concurrent writes
+func (m *Map) Size() int {
+	size := 0
+	for _, s := range m.shards {
+		s.RLock()
+		size += len(s.items)
+		s.RUnlock()
+	}
+	return size
+}
+
+// Empty returns true if no kv pairs inside map
+func (m *Map) Empty() bool {
+	for _, s := range m.shards {
+		s.RLock()
+		n := len(s.items)
+		s.RUnlock()
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Clear clears the map
+func (m *Map) Clear() {
+	for _, s := range m.shards {
+		s.Lock()
+		s.items = make(map[interface{}]interface{})
+		s.Unlock()
+	}
+}
+
+type iterator struct {
+	m            *Map
+	shard        int
+	keys, values []interface{}
+	cursor       int
+}
+
+// HasNext to meet Iterator interface
+func (iter *iterator) HasNext() bool {
+	for iter.cursor == len(iter.keys) {
+		if iter.shard == len(iter.m.shards) {
+			return false
+		}
+		iter.keys, iter.values = iter.m.shards[iter.shard].snapshot()
+		iter.shard++
+		iter.cursor = 0
+	}
+	return true
+}
+
+// Next to meet Iterator interface
+func (iter *iterator) Next() (key, value interface{}) {
+	if !iter.HasNext() {
+		return nil, nil
+	}
+	key, value = iter.keys[iter.cursor], iter.values[iter.cursor]
+	iter.cursor++
+	return
+}
+
+// Iterator returns a weakly consistent iterator, shards are copied lazily one by one
+func (m *Map) Iterator() maps.Iterator {
+	return &iterator{m: m}
+}
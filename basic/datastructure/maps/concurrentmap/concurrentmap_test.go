@@ -0,0 +1,231 @@
+package concurrentmap
+
+import (
+	"godev/basic"
+	"godev/basic/datastructure/maps"
+	"math"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+type point struct {
+	x, y int
+}
+
+func TestNewMap(t *testing.T) {
+	var _ basic.Container = (*Map)(nil)
+	var _ maps.Map = (*Map)(nil)
+
+	m := NewMap(0)
+	if len(m.shards) != DefaultShardNum {
+		t.Fail()
+	}
+	if m = NewMap(5); len(m.shards) != 8 {
+		t.Fail()
+	}
+	if !m.Empty() || m.Size() != 0 || len(m.Keys()) != 0 || len(m.Values()) != 0 || m.Iterator().HasNext() {
+		t.Fail()
+	}
+}
+
+func TestDefaultHasher(t *testing.T) {
+	if DefaultHasher(0.0) != DefaultHasher(math.Copysign(0, -1)) || DefaultHasher("a") == DefaultHasher("b") {
+		t.Fail()
+	}
+	if DefaultHasher(point{1, 2}) != DefaultHasher(point{1, 2}) || DefaultHasher(point{1, 2}) == DefaultHasher(point{2, 1}) {
+		t.Fail()
+	}
+}
+
+func TestMap_Set(t *testing.T) {
+	m := NewMap(4)
+
+	for i := 0; i < 100; i++ {
+		m.Set(i, i)
+		m.Set(strconv.Itoa(i), i)
+	}
+	m.Set(point{1, 2}, 3)
+
+	if m.Size() != 201 || len(m.Keys()) != 201 || len(m.Values()) != 201 {
+		t.Fail()
+	}
+	for i := 0; i < 100; i++ {
+		if v, found := m.Get(i); !found || v.(int) != i {
+			t.Fail()
+		}
+		if v, found := m.Get(strconv.Itoa(i)); !found || v.(int) != i {
+			t.Fail()
+		}
+	}
+	if v, found := m.Get(point{1, 2}); !found || v.(int) != 3 {
+		t.Fail()
+	}
+
+	cnt := 0
+	it := m.Iterator()
+	for it.HasNext() {
+		it.Next()
+		cnt++
+	}
+	if cnt != 201 {
+		t.Fail()
+	}
+	if k, v := it.Next(); k != nil || v != nil {
+		t.Fail()
+	}
+
+	cnt = 0
+	m.Range(func(key, value interface{}) bool {
+		// modifying inside Range is safe
+		m.Delete(key)
+		cnt++
+		return cnt < 10
+	})
+	if cnt != 10 || m.Size() != 191 {
+		t.Fail()
+	}
+
+	if m.Delete(1000) {
+		t.Fail()
+	}
+	m.Clear()
+	if !m.Empty() {
+		t.Fail()
+	}
+}
+
+func TestMap_Atomic(t *testing.T) {
+	m := NewMap(4)
+
+	if v, loaded := m.LoadOrStore(1, 1); loaded || v.(int) != 1 {
+		t.Fail()
+	}
+	if v, loaded := m.LoadOrStore(1, 2); !loaded || v.(int) != 1 {
+		t.Fail()
+	}
+
+	if m.CompareAndSwap(1, 2, 3) || !m.CompareAndSwap(1, 1, 3) || m.CompareAndSwap(2, nil, 3) {
+		t.Fail()
+	}
+	if v, _ := m.Get(1); v.(int) != 3 {
+		t.Fail()
+	}
+
+	if m.CompareAndDelete(1, 1) || !m.CompareAndDelete(1, 3) {
+		t.Fail()
+	}
+
+	// uncomparable values are never equal
+	m.Set(2, []int{1})
+	if m.CompareAndSwap(2, []int{1}, 3) || m.CompareAndSwap(2, func() {}, 3) || m.CompareAndDelete(2, map[int]int{}) {
+		t.Fail()
+	}
+	if v, _ := m.Get(2); len(v.([]int)) != 1 || !m.Delete(2) {
+		t.Fail()
+	}
+
+	m.Set(1, 1)
+	if v, loaded := m.LoadAndDelete(1); !loaded || v.(int) != 1 {
+		t.Fail()
+	}
+	if _, loaded := m.LoadAndDelete(1); loaded {
+		t.Fail()
+	}
+
+	if v, ok := m.Compute(1, func(value interface{}, found bool) (interface{}, bool) {
+		if found {
+			t.Fail()
+		}
+		return 10, true
+	}); !ok || v.(int) != 10 {
+		t.Fail()
+	}
+	if _, ok := m.Compute(1, func(value interface{}, found bool) (interface{}, bool) {
+		return nil, false
+	}); ok || !m.Empty() {
+		t.Fail()
+	}
+}
+
+func TestMap_Concurrent(t *testing.T) {
+	m := NewMap(16)
+	wg := sync.WaitGroup{}
+
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				m.Compute(i%10, func(value interface{}, found bool) (interface{}, bool) {
+					if !found {
+						return 1, true
+					}
+					return value.(int) + 1, true
+				})
+				m.Get(i % 10)
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			m.Range(func(key, value interface{}) bool { return true })
+		}
+	}()
+	wg.Wait()
+
+	sum := 0
+	for _, v := range m.Values() {
+		sum += v.(int)
+	}
+	if sum != 8000 {
+		t.Fail()
+	}
+}
+
+const benchKeys = 1 << 12
+
+func BenchmarkMap_GetSet(b *testing.B) {
+	m := NewMap(DefaultShardNum)
+	for i := 0; i < benchKeys; i++ {
+		m.Set(i, i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%4 == 0 {
+				m.Set(i%benchKeys, i)
+			} else {
+				m.Get(i % benchKeys)
+			}
+			i += 7
+		}
+	})
+}
+
+func BenchmarkMutexMap_GetSet(b *testing.B) {
+	m := make(map[interface{}]interface{})
+	lock := sync.RWMutex{}
+	for i := 0; i < benchKeys; i++ {
+		m[i] = i
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%4 == 0 {
+				lock.Lock()
+				m[i%benchKeys] = i
+				lock.Unlock()
+			} else {
+				lock.RLock()
+				_ = m[i%benchKeys]
+				lock.RUnlock()
+			}
+			i += 7
+		}
+	})
+}
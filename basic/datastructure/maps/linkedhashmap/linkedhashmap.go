@@ -10,38 +10,115 @@ type item struct {
 	value interface{}
 }
 
+// RemoveEldestFunc is called after Set inserts a new key with the eldest (front) key value pair,
+//	returns true to remove the eldest one, e.g. `return m.Size() > capacity` makes a bounded map
+type RemoveEldestFunc func(m *LinkedHashMap, eldestKey, eldestValue interface{}) bool
+
 // LinkedHashMap struct uses a linked list to record key insertion order
+//	or access order (Get / Set moves the key to the back), like Java's LinkedHashMap
 type LinkedHashMap struct {
 	_list *list.List
 	_map  map[interface{}]*item
+
+	accessOrder  bool
+	removeEldest RemoveEldestFunc
 }
 
-// NewLinkedHashMap returns a new linked hash map
+// NewLinkedHashMap returns a new linked hash map in insertion order
 func NewLinkedHashMap() *LinkedHashMap {
+	return NewLinkedHashMapWithOrder(false, nil)
+}
+
+// NewLinkedHashMapWithOrder returns a new linked hash map in access order if accessOrder is true,
+//	removeEldest can be nil which means never remove
+func NewLinkedHashMapWithOrder(accessOrder bool, removeEldest RemoveEldestFunc) *LinkedHashMap {
 	return &LinkedHashMap{
-		_list: list.New(),
-		_map:  make(map[interface{}]*item),
+		_list:        list.New(),
+		_map:         make(map[interface{}]*item),
+		accessOrder:  accessOrder,
+		removeEldest: removeEldest,
 	}
 }
 
 // Set sets key value pairs
+//	in access order, an existing key is moved to the back
 func (m *LinkedHashMap) Set(key, value interface{}) {
-	if _, found := m._map[key]; !found {
-		e := m._list.PushBack(key)
-		m._map[key] = &item{
-			key:   e,
-			value: value,
+	if it, found := m._map[key]; found {
+		it.value = value
+		if m.accessOrder {
+			m._list.MoveToBack(it.key)
 		}
 		return
 	}
-	m._map[key].value = value
+	e := m._list.PushBack(key)
+	m._map[key] = &item{
+		key:   e,
+		value: value,
+	}
+	if m.removeEldest != nil {
+		eldest := m._list.Front()
+		if m.removeEldest(m, eldest.Value, m._map[eldest.Value].value) {
+			m.Delete(eldest.Value)
+		}
+	}
 }
 
 // Get gets value with input key if found inside map
+//	in access order, the key is moved to the back
 func (m *LinkedHashMap) Get(key interface{}) (value interface{}, found bool) {
 	it, found := m._map[key]
-	value = it.value
-	return
+	if !found {
+		return
+	}
+	if m.accessOrder {
+		m._list.MoveToBack(it.key)
+	}
+	return it.value, true
+}
+
+// Peek gets value with input key without changing access order
+func (m *LinkedHashMap) Peek(key interface{}) (value interface{}, found bool) {
+	it, found := m._map[key]
+	if !found {
+		return
+	}
+	return it.value, true
+}
+
+// MoveToFront moves key to the front (eldest), returns false if key not found
+func (m *LinkedHashMap) MoveToFront(key interface{}) bool {
+	it, found := m._map[key]
+	if found {
+		m._list.MoveToFront(it.key)
+	}
+	return found
+}
+
+// MoveToBack moves key to the back (youngest), returns false if key not found
+func (m *LinkedHashMap) MoveToBack(key interface{}) bool {
+	it, found := m._map[key]
+	if found {
+		m._list.MoveToBack(it.key)
+	}
+	return found
+}
+
+// Front returns the eldest key value pair
+func (m *LinkedHashMap) Front() (key, value interface{}, found bool) {
+	e := m._list.Front()
+	if e == nil {
+		return nil, nil, false
+	}
+	return e.Value, m._map[e.Value].value, true
+}
+
+// Back returns the youngest key value pair
+func (m *LinkedHashMap) Back() (key, value interface{}, found bool) {
+	e := m._list.Back()
+	if e == nil {
+		return nil, nil, false
+	}
+	return e.Value, m._map[e.Value].value, true
 }
 
 // Delete deletes key value pairs
@@ -64,12 +141,12 @@ func (m *LinkedHashMap) Size() int {
 	return len(m._map)
 }
 
-// Clear clears the map
+// Clear clears the map, order mode and removeEldest are kept
 func (m *LinkedHashMap) Clear() {
-	*m = *NewLinkedHashMap()
+	*m = *NewLinkedHashMapWithOrder(m.accessOrder, m.removeEldest)
 }
 
-// Values returns all values in insertion order
+// Values returns all values in map order (insertion or access order)
 func (m *LinkedHashMap) Values() []interface{} {
 	values := make([]interface{}, 0, len(m._map))
 	for e := m._list.Front(); e != nil; e = e.Next() {
@@ -78,7 +155,7 @@ func (m *LinkedHashMap) Values() []interface{} {
 	return values
 }
 
-// Keys returns all keys in insertion order
+// Keys returns all keys in map order (insertion or access order)
 func (m *LinkedHashMap) Keys() []interface{} {
 	keys := make([]interface{}, 0, len(m._map))
 	for e := m._list.Front(); e != nil; e = e.Next() {
@@ -88,23 +165,31 @@ func (m *LinkedHashMap) Keys() []interface{} {
 }
 
 type iterator struct {
-	m      *LinkedHashMap
-	cursor *list.Element
+	m       *LinkedHashMap
+	cursor  *list.Element
+	reverse bool
 }
 
 // HasNext to meet Iterator interface
 func (iter *iterator) HasNext() bool {
-	return iter.cursor.Next() != nil
+	return iter.cursor != nil
 }
 
 // Next to meet Iterator interface
 func (iter *iterator) Next() (key, value interface{}) {
+	if iter.cursor == nil {
+		return nil, nil
+	}
 	it := iter.m._map[iter.cursor.Value]
-	iter.cursor = iter.cursor.Next()
+	if iter.reverse {
+		iter.cursor = iter.cursor.Prev()
+	} else {
+		iter.cursor = iter.cursor.Next()
+	}
 	return it.key.Value, it.value
 }
 
-// Iterator returns iterator
+// Iterator returns iterator from front to back, it does not change access order
 func (m *LinkedHashMap) Iterator() maps.Iterator {
 	iter := iterator{
 		m:      m,
@@ -112,3 +197,13 @@ func (m *LinkedHashMap) Iterator() maps.Iterator {
 	}
 	return &iter
 }
+
+// ReverseIterator returns iterator from back to front, it does not change access order
+func (m *LinkedHashMap) ReverseIterator() maps.Iterator {
+	iter := iterator{
+		m:       m,
+		cursor:  m._list.Back(),
+		reverse: true,
+	}
+	return &iter
+}
@@ -101,6 +101,116 @@ func TestLinkedHashMap_Iterator(t *testing.T) {
 		}
 		i++
 	}
+	if i != len(a) {
+		t.Fail()
+	}
+
+	it = m.ReverseIterator()
+	for it.HasNext() {
+		i--
+		k, v := it.Next()
+		if k.(int) != i || v.(int) != a[i] {
+			t.Fail()
+		}
+	}
+	if i != 0 {
+		t.Fail()
+	}
+	if k, v := it.Next(); k != nil || v != nil {
+		t.Fail()
+	}
+
+	if NewLinkedHashMap().Iterator().HasNext() {
+		t.Fail()
+	}
+}
+
+func TestLinkedHashMap_AccessOrder(t *testing.T) {
+	m := NewLinkedHashMapWithOrder(true, nil)
+	for i := 0; i < 5; i++ {
+		m.Set(i, i)
+	}
+
+	// 0 1 2 3 4 -> 0 2 3 4 1 -> 0 3 4 1 2 -> 3 4 1 2 0
+	m.Get(1)
+	m.Set(2, 20)
+	m.Peek(3)
+	m.Get(0)
+	if _, found := m.Get(100); found {
+		t.Fail()
+	}
+
+	expected := []int{3, 4, 1, 2, 0}
+	for i, k := range m.Keys() {
+		if k.(int) != expected[i] {
+			t.Fail()
+		}
+	}
+
+	if !m.MoveToFront(0) || !m.MoveToBack(3) || m.MoveToFront(100) || m.MoveToBack(100) {
+		t.Fail()
+	}
+	if k, _, _ := m.Front(); k.(int) != 0 {
+		t.Fail()
+	}
+	if k, v, _ := m.Back(); k.(int) != 3 || v.(int) != 3 {
+		t.Fail()
+	}
+
+	m.Clear()
+	if _, _, found := m.Front(); found {
+		t.Fail()
+	}
+	if _, _, found := m.Back(); found {
+		t.Fail()
+	}
+	m.Set(1, 1)
+	m.Set(2, 2)
+	m.Get(1)
+	if k, _, _ := m.Back(); k.(int) != 1 {
+		t.Fail()
+	}
+}
+
+func TestLinkedHashMap_RemoveEldest(t *testing.T) {
+	var evicted []interface{}
+	m := NewLinkedHashMapWithOrder(true, func(m *LinkedHashMap, eldestKey, eldestValue interface{}) bool {
+		if m.Size() > 3 {
+			evicted = append(evicted, eldestKey)
+			return true
+		}
+		return false
+	})
+
+	m.Set(1, 1)
+	m.Set(2, 2)
+	m.Set(3, 3)
+	m.Get(1)
+	m.Set(4, 4) // evicts 2
+	m.Set(3, 30)
+	m.Set(5, 5) // evicts 1
+
+	if m.Size() != 3 || len(evicted) != 2 || evicted[0].(int) != 2 || evicted[1].(int) != 1 {
+		t.Fail()
+	}
+	expected := []int{4, 3, 5}
+	for i, k := range m.Keys() {
+		if k.(int) != expected[i] {
+			t.Fail()
+		}
+	}
+
+	// insertion order with eviction acts like FIFO
+	fifo := NewLinkedHashMapWithOrder(false, func(m *LinkedHashMap, _, _ interface{}) bool {
+		return m.Size() > 2
+	})
+	fifo.Set(1, 1)
+	fifo.Set(2, 2)
+	fifo.Get(1)
+	fifo.Set(3, 3)
+	if _, found := fifo.Get(1); found || fifo.Size() != 2 {
+		t.Fail()
+	}
 }
 
 // BenchmarkLinkedHashMap_Set-8   	 2000000	       833 ns/op
@@ -0,0 +1,129 @@
+package multimap
+
+// HashMultiMap struct is based on builtin map, keys are not ordered
+type HashMultiMap struct {
+	m    map[interface{}][]interface{}
+	size int
+}
+
+// NewHashMultiMap creates a new hash multimap
+func NewHashMultiMap() *HashMultiMap {
+	return &HashMultiMap{
+		m:    make(map[interface{}][]interface{}),
+		size: 0,
+	}
+}
+
+// Put appends value to key's values
+func (mm *HashMultiMap) Put(key, value interface{}) {
+	mm.m[key] = append(mm.m[key], value)
+	mm.size++
+}
+
+// PutAll appends values to key's values
+func (mm *HashMultiMap) PutAll(key interface{}, values ...interface{}) {
+	if len(values) == 0 {
+		return
+	}
+	mm.m[key] = append(mm.m[key], values...)
+	mm.size += len(values)
+}
+
+// Get returns a copy of key's values
+func (mm *HashMultiMap) Get(key interface{}) (values []interface{}, found bool) {
+	vs, found := mm.m[key]
+	if !found {
+		return nil, false
+	}
+	return copyValues(vs), true
+}
+
+// Remove removes one value equals to input value from key's values, key is removed when it has no value
+func (mm *HashMultiMap) Remove(key, value interface{}) bool {
+	vs, found := mm.m[key]
+	if !found {
+		return false
+	}
+	vs, found = removeValue(vs, value)
+	if !found {
+		return false
+	}
+	if len(vs) == 0 {
+		delete(mm.m, key)
+	} else {
+		mm.m[key] = vs
+	}
+	mm.size--
+	return true
+}
+
+// RemoveAll removes key with all its values
+func (mm *HashMultiMap) RemoveAll(key interface{}) bool {
+	vs, found := mm.m[key]
+	if !found {
+		return false
+	}
+	delete(mm.m, key)
+	mm.size -= len(vs)
+	return true
+}
+
+// ContainsKey returns true if key has at least one value
+func (mm *HashMultiMap) ContainsKey(key interface{}) bool {
+	_, found := mm.m[key]
+	return found
+}
+
+// ContainsEntry returns true if key has the value
+func (mm *HashMultiMap) ContainsEntry(key, value interface{}) bool {
+	return containsValue(mm.m[key], value)
+}
+
+// Keys returns distinct keys
+func (mm *HashMultiMap) Keys() []interface{} {
+	keys := make([]interface{}, 0, len(mm.m))
+	for k := range mm.m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// KeySize returns number of distinct keys
+func (mm *HashMultiMap) KeySize() int {
+	return len(mm.m)
+}
+
+// Entries returns all key value pairs
+func (mm *HashMultiMap) Entries() []Entry {
+	entries := make([]Entry, 0, mm.size)
+	for k, vs := range mm.m {
+		for _, v := range vs {
+			entries = append(entries, Entry{Key: k, Value: v})
+		}
+	}
+	return entries
+}
+
+// Empty returns true if no kv pairs inside
+func (mm *HashMultiMap) Empty() bool {
+	return mm.size == 0
+}
+
+// Size returns number of kv pairs inside
+func (mm *HashMultiMap) Size() int {
+	return mm.size
+}
+
+// Clear clears the multimap
+func (mm *HashMultiMap) Clear() {
+	*mm = *NewHashMultiMap()
+}
+
+// Values returns all values
+func (mm *HashMultiMap) Values() []interface{} {
+	values := make([]interface{}, 0, mm.size)
+	for _, vs := range mm.m {
+		values = append(values, vs...)
+	}
+	return values
+}
@@ -0,0 +1,72 @@
+package multimap
+
+import (
+	"godev/basic"
+	"reflect"
+)
+
+// MultiMap interface maps one key to many values
+//	values of a key keep insertion order and can be duplicated
+//	Remove and ContainsEntry compare values with ==, or reflect.DeepEqual for uncomparable ones such as slices
+type MultiMap interface {
+	Put(key, value interface{})
+	PutAll(key interface{}, values ...interface{})
+	Get(key interface{}) (values []interface{}, found bool)
+	Remove(key, value interface{}) bool
+	RemoveAll(key interface{}) bool
+	ContainsKey(key interface{}) bool
+	ContainsEntry(key, value interface{}) bool
+	Keys() []interface{}
+	KeySize() int
+	Entries() []Entry
+
+	// Size returns number of key value pairs
+	basic.Container
+}
+
+// Entry struct stands for one key value pair
+type Entry struct {
+	Key, Value interface{}
+}
+
+// valueEqual returns a == b, values of uncomparable dynamic types such as slices are compared deeply
+func valueEqual(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	ta := reflect.TypeOf(a)
+	if ta != reflect.TypeOf(b) {
+		return false
+	}
+	if !ta.Comparable() {
+		return reflect.DeepEqual(a, b)
+	}
+	return a == b
+}
+
+// removeValue removes first value equals to input value, returns new slice and true if found
+func removeValue(values []interface{}, value interface{}) ([]interface{}, bool) {
+	for i := range values {
+		if valueEqual(values[i], value) {
+			copy(values[i:], values[i+1:])
+			values[len(values)-1] = nil
+			return values[:len(values)-1], true
+		}
+	}
+	return values, false
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for i := range values {
+		if valueEqual(values[i], value) {
+			return true
+		}
+	}
+	return false
+}
+
+func copyValues(values []interface{}) []interface{} {
+	cp := make([]interface{}, len(values))
+	copy(cp, values)
+	return cp
+}
@@ -0,0 +1,118 @@
+package multimap
+
+import (
+	"godev/basic"
+	"testing"
+)
+
+func TestMultiMap(t *testing.T) {
+	var _ MultiMap = (*HashMultiMap)(nil)
+	var _ MultiMap = (*TreeMultiMap)(nil)
+
+	for _, mm := range []MultiMap{NewHashMultiMap(), NewTreeMultiMap(basic.IntComparator)} {
+		if !mm.Empty() || mm.Size() != 0 || mm.KeySize() != 0 || len(mm.Keys()) != 0 || len(mm.Values()) != 0 || len(mm.Entries()) != 0 {
+			t.Fail()
+		}
+
+		mm.Put(3, "c")
+		mm.Put(1, "a")
+		mm.PutAll(2, "b", "bb", "b")
+		mm.PutAll(4)
+		mm.Put(1, "aa")
+
+		if mm.Size() != 6 || mm.KeySize() != 3 || len(mm.Keys()) != 3 || len(mm.Values()) != 6 || len(mm.Entries()) != 6 {
+			t.Fail()
+		}
+		if vs, found := mm.Get(2); !found || len(vs) != 3 || vs[0] != "b" || vs[1] != "bb" || vs[2] != "b" {
+			t.Fail()
+		}
+		if vs, found := mm.Get(4); found || vs != nil || mm.ContainsKey(4) {
+			t.Fail()
+		}
+		if !mm.ContainsKey(1) || !mm.ContainsEntry(1, "aa") || mm.ContainsEntry(1, "b") {
+			t.Fail()
+		}
+
+		// returned values are copies
+		vs, _ := mm.Get(1)
+		vs[0] = "x"
+		if !mm.ContainsEntry(1, "a") {
+			t.Fail()
+		}
+
+		if !mm.Remove(2, "b") || mm.Remove(2, "c") || mm.Remove(5, "b") || mm.Size() != 5 {
+			t.Fail()
+		}
+		if vs, _ := mm.Get(2); len(vs) != 2 || vs[0] != "bb" || vs[1] != "b" {
+			t.Fail()
+		}
+		if !mm.Remove(3, "c") || mm.ContainsKey(3) || mm.KeySize() != 2 {
+			t.Fail()
+		}
+		if !mm.RemoveAll(2) || mm.RemoveAll(2) || mm.Size() != 2 {
+			t.Fail()
+		}
+
+		mm.Clear()
+		if !mm.Empty() || mm.KeySize() != 0 {
+			t.Fail()
+		}
+	}
+}
+
+func TestTreeMultiMap_Order(t *testing.T) {
+	mm := NewTreeMultiMap(basic.IntComparator)
+	a := []int{12, 7, 25, 15, 28, 33, 41, 1}
+	for i := range a {
+		mm.PutAll(a[i], a[i], -a[i])
+	}
+
+	keys := mm.Keys()
+	for i := 1; i < len(keys); i++ {
+		if keys[i-1].(int) >= keys[i].(int) {
+			t.Fail()
+		}
+	}
+
+	entries := mm.Entries()
+	values := mm.Values()
+	if len(entries) != 16 || len(values) != 16 {
+		t.Fail()
+	}
+	for i := 0; i < len(entries); i += 2 {
+		if entries[i].Key != keys[i/2] || entries[i].Value.(int) != keys[i/2].(int) || entries[i+1].Value.(int) != -keys[i/2].(int) {
+			t.Fail()
+		}
+		if values[i] != entries[i].Value {
+			t.Fail()
+		}
+	}
+
+	for i := range a {
+		if !mm.RemoveAll(a[i]) {
+			t.Fail()
+		}
+	}
+	if !mm.Empty() {
+		t.Fail()
+	}
+}
+
+func TestMultiMap_UncomparableValues(t *testing.T) {
+	for _, mm := range []MultiMap{NewHashMultiMap(), NewTreeMultiMap(basic.IntComparator)} {
+		mm.PutAll(1, []int{1, 2}, []int{3}, map[string]int{"a": 1}, "s")
+
+		if !mm.ContainsEntry(1, []int{3}) || mm.ContainsEntry(1, []int{2, 1}) || !mm.ContainsEntry(1, map[string]int{"a": 1}) {
+			t.Fail()
+		}
+		if mm.ContainsEntry(1, func() {}) || mm.Remove(1, []string{"s"}) {
+			t.Fail()
+		}
+		if !mm.Remove(1, []int{1, 2}) || mm.ContainsEntry(1, []int{1, 2}) || mm.Size() != 3 {
+			t.Fail()
+		}
+		if !mm.Remove(1, "s") || mm.Size() != 2 {
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package multimap
+
+import (
+	"godev/basic"
+	"godev/basic/datastructure/maps/treemap"
+)
+
+// TreeMultiMap struct is based on tree map, keys are ordered by comparator
+type TreeMultiMap struct {
+	m    *treemap.Map
+	size int
+}
+
+// NewTreeMultiMap creates a new tree multimap with input comparator
+func NewTreeMultiMap(comparator basic.Comparator) *TreeMultiMap {
+	return &TreeMultiMap{
+		m:    treemap.NewMap(comparator),
+		size: 0,
+	}
+}
+
+func (mm *TreeMultiMap) get(key interface{}) []interface{} {
+	if vs, found := mm.m.Get(key); found {
+		return vs.([]interface{})
+	}
+	return nil
+}
+
+// Put appends value to key's values
+func (mm *TreeMultiMap) Put(key, value interface{}) {
+	mm.m.Set(key, append(mm.get(key), value))
+	mm.size++
+}
+
+// PutAll appends values to key's values
+func (mm *TreeMultiMap) PutAll(key interface{}, values ...interface{}) {
+	if len(values) == 0 {
+		return
+	}
+	mm.m.Set(key, append(mm.get(key), values...))
+	mm.size += len(values)
+}
+
+// Get returns a copy of key's values
+func (mm *TreeMultiMap) Get(key interface{}) (values []interface{}, found bool) {
+	vs := mm.get(key)
+	if vs == nil {
+		return nil, false
+	}
+	return copyValues(vs), true
+}
+
+// Remove removes one value equals to input value from key's values, key is removed when it has no value
+func (mm *TreeMultiMap) Remove(key, value interface{}) bool {
+	vs := mm.get(key)
+	if vs == nil {
+		return false
+	}
+	vs, found := removeValue(vs, value)
+	if !found {
+		return false
+	}
+	if len(vs) == 0 {
+		mm.m.Delete(key)
+	} else {
+		mm.m.Set(key, vs)
+	}
+	mm.size--
+	return true
+}
+
+// RemoveAll removes key with all its values
+func (mm *TreeMultiMap) RemoveAll(key interface{}) bool {
+	vs := mm.get(key)
+	if vs == nil {
+		return false
+	}
+	mm.m.Delete(key)
+	mm.size -= len(vs)
+	return true
+}
+
+// ContainsKey returns true if key has at least one value
+func (mm *TreeMultiMap) ContainsKey(key interface{}) bool {
+	return mm.get(key) != nil
+}
+
+// ContainsEntry returns true if key has the value
+func (mm *TreeMultiMap) ContainsEntry(key, value interface{}) bool {
+	return containsValue(mm.get(key), value)
+}
+
+// Keys returns distinct keys in order
+func (mm *TreeMultiMap) Keys() []interface{} {
+	return mm.m.Keys()
+}
+
+// KeySize returns number of distinct keys
+func (mm *TreeMultiMap) KeySize() int {
+	return mm.m.Size()
+}
+
+// Entries returns all key value pairs ordered by key
+func (mm *TreeMultiMap) Entries() []Entry {
+	entries := make([]Entry, 0, mm.size)
+	keys, values := mm.m.Keys(), mm.m.Values()
+	for i := range keys {
+		for _, v := range values[i].([]interface{}) {
+			entries = append(entries, Entry{Key: keys[i], Value: v})
+		}
+	}
+	return entries
+}
+
+// Empty returns true if no kv pairs inside
+func (mm *TreeMultiMap) Empty() bool {
+	return mm.size == 0
+}
+
+// Size returns number of kv pairs inside
+func (mm *TreeMultiMap) Size() int {
+	return mm.size
+}
+
+// Clear clears the multimap
+func (mm *TreeMultiMap) Clear() {
+	mm.m.Clear()
+	mm.size = 0
+}
+
+// Values returns all values ordered by key
+func (mm *TreeMultiMap) Values() []interface{} {
+	values := make([]interface{}, 0, mm.size)
+	for _, vs := range mm.m.Values() {
+		values = append(values, vs.([]interface{})...)
+	}
+	return values
+}
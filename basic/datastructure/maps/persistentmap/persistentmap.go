@@ -0,0 +1,91 @@
+package persistentmap
+
+import (
+	"godev/basic"
+	"godev/basic/datastructure/maps"
+	"godev/basic/datastructure/tree/persistenttree"
+	"sync"
+	"sync/atomic"
+)
+
+// Map struct holds the current version of a persistent tree
+//	writers are serialized by a mutex and publish new versions atomically,
+//	readers never block and can take a consistent Snapshot for long-running reads
+type Map struct {
+	current atomic.Value // *persistenttree.Tree
+	lock    sync.Mutex
+}
+
+// NewMap creates a new map with input comparator
+func NewMap(comparator basic.Comparator) *Map {
+	m := &Map{}
+	m.current.Store(persistenttree.NewTree(comparator))
+	return m
+}
+
+// Snapshot returns current version, it never changes even if the map is modified later
+func (m *Map) Snapshot() *persistenttree.Tree {
+	return m.current.Load().(*persistenttree.Tree)
+}
+
+// Update applies f on current version and publishes the returned version atomically
+//	f must not be nil and should not return nil
+func (m *Map) Update(f func(t *persistenttree.Tree) *persistenttree.Tree) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.current.Store(f(m.Snapshot()))
+}
+
+// Set sets key value pairs
+func (m *Map) Set(key, value interface{}) {
+	m.Update(func(t *persistenttree.Tree) *persistenttree.Tree {
+		return t.Set(key, value)
+	})
+}
+
+// Get gets value with input key if found inside map
+func (m *Map) Get(key interface{}) (value interface{}, found bool) {
+	return m.Snapshot().Get(key)
+}
+
+// Delete deletes key value pairs
+func (m *Map) Delete(key interface{}) bool {
+	var found bool
+	m.Update(func(t *persistenttree.Tree) *persistenttree.Tree {
+		t, found = t.Delete(key)
+		return t
+	})
+	return found
+}
+
+// Keys returns all keys of current version
+func (m *Map) Keys() []interface{} {
+	return m.Snapshot().Keys()
+}
+
+// Values returns all values of current version
+func (m *Map) Values() []interface{} {
+	return m.Snapshot().Values()
+}
+
+// Empty returns true if no kv pairs inside map
+func (m *Map) Empty() bool {
+	return m.Snapshot().Empty()
+}
+
+// Size returns quantity of kv pairs inside map
+func (m *Map) Size() int {
+	return m.Snapshot().Size()
+}
+
+// Clear publishes an empty version, snapshots taken before are not affected
+func (m *Map) Clear() {
+	m.Update(func(t *persistenttree.Tree) *persistenttree.Tree {
+		return persistenttree.NewTree(t.Comparator())
+	})
+}
+
+// Iterator returns iterator over current version
+func (m *Map) Iterator() maps.Iterator {
+	return m.Snapshot().Iterator()
+}
@@ -0,0 +1,132 @@
+package persistentmap
+
+import (
+	"godev/basic"
+	"godev/basic/datastructure/maps"
+	"godev/basic/datastructure/tree/persistenttree"
+	"sync"
+	"testing"
+)
+
+func TestNewMap(t *testing.T) {
+	var _ basic.Container = (*Map)(nil)
+	var _ maps.Map = (*Map)(nil)
+
+	m := NewMap(basic.IntComparator)
+
+	if !m.Empty() || m.Size() != 0 || len(m.Keys()) != 0 || len(m.Values()) != 0 {
+		t.Fail()
+	}
+}
+
+func TestMap_Set(t *testing.T) {
+	m := NewMap(basic.IntComparator)
+
+	a := []int{12, 7, 25, 15, 28, 33, 41, 1}
+	for i := range a {
+		m.Set(i, a[i])
+	}
+
+	if m.Size() != 8 {
+		t.Fail()
+	}
+	for i, v := range m.Keys() {
+		if v.(int) != i {
+			t.Fail()
+		}
+	}
+	for i, v := range m.Values() {
+		if v.(int) != a[i] {
+			t.Fail()
+		}
+	}
+
+	it := m.Iterator()
+	cnt := 0
+	for it.HasNext() {
+		k, v := it.Next()
+		if k.(int) != cnt || v.(int) != a[cnt] {
+			t.Fail()
+		}
+		cnt++
+	}
+	if cnt != len(a) {
+		t.Fail()
+	}
+
+	if !m.Delete(1) || m.Delete(1) || m.Size() != 7 {
+		t.Fail()
+	}
+	if _, found := m.Get(1); found {
+		t.Fail()
+	}
+
+	m.Clear()
+	if !m.Empty() {
+		t.Fail()
+	}
+}
+
+func TestMap_Snapshot(t *testing.T) {
+	m := NewMap(basic.IntComparator)
+	for i := 0; i < 10; i++ {
+		m.Set(i, i)
+	}
+
+	snapshot := m.Snapshot()
+	m.Delete(0)
+	m.Set(100, 100)
+	m.Clear()
+
+	if snapshot.Size() != 10 {
+		t.Fail()
+	}
+	for i := 0; i < 10; i++ {
+		if v, found := snapshot.Get(i); !found || v.(int) != i {
+			t.Fail()
+		}
+	}
+
+	// batch update publishes once
+	m.Update(func(tree *persistenttree.Tree) *persistenttree.Tree {
+		for i := 0; i < 5; i++ {
+			tree = tree.Set(i, -i)
+		}
+		return tree
+	})
+	if m.Size() != 5 {
+		t.Fail()
+	}
+}
+
+func TestMap_Concurrent(t *testing.T) {
+	m := NewMap(basic.IntComparator)
+	wg := sync.WaitGroup{}
+
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				m.Set(w*100+i, i)
+			}
+		}(w)
+	}
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				snapshot := m.Snapshot()
+				if len(snapshot.Keys()) != snapshot.Size() {
+					t.Error("inconsistent snapshot")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if m.Size() != 400 {
+		t.Fail()
+	}
+}
@@ -92,6 +92,12 @@ func TestMap_Iterator(t *testing.T) {
 		}
 		i++
 	}
+	if i != len(a) {
+		t.Fatal(i)
+	}
+	if NewMap(basic.IntComparator).Iterator().HasNext() {
+		t.Fail()
+	}
 }
 
 // BenchmarkMap_Set-8   	 1000000	      1717 ns/op
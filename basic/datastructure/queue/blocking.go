@@ -0,0 +1,189 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// NonBlocking interface stands for queues whose operations return immediately
+//	TryEnqueue returns false only if queue is full, TryDequeue returns false only if queue is empty
+type NonBlocking interface {
+	TryEnqueue(val interface{}) bool
+	TryDequeue() (val interface{}, success bool)
+}
+
+// TryEnqueue to meet NonBlocking interface, it never fails
+func (q *MSQueue) TryEnqueue(val interface{}) bool {
+	q.Enqueue(val)
+	return true
+}
+
+// TryDequeue to meet NonBlocking interface
+func (q *MSQueue) TryDequeue() (val interface{}, success bool) {
+	return q.Dequeue()
+}
+
+// TryEnqueue to meet NonBlocking interface
+//	unlike Enqueue, it retries on CAS contention and only fails when queue is full
+func (lf *LockFree) TryEnqueue(val interface{}) bool {
+	for {
+		success, size := lf.Enqueue(val)
+		if success {
+			return true
+		}
+		if size >= lf.cap-1 {
+			return false
+		}
+	}
+}
+
+// TryDequeue to meet NonBlocking interface
+//	unlike Dequeue, it retries on CAS contention and only fails when queue is empty
+func (lf *LockFree) TryDequeue() (val interface{}, success bool) {
+	for {
+		success, val, size := lf.Dequeue()
+		if success {
+			return val, true
+		}
+		if size < 1 {
+			return nil, false
+		}
+	}
+}
+
+// signal wakes up all goroutines waiting on it, waiters are counted so that
+//	fast path (nobody waiting) does not need lock
+type signal struct {
+	waiters int32
+	lock    sync.Mutex
+	ch      chan struct{}
+}
+
+func newSignal() *signal {
+	return &signal{ch: make(chan struct{})}
+}
+
+// wait registers a waiter and returns channel to wait on, must be followed by done
+func (s *signal) wait() <-chan struct{} {
+	atomic.AddInt32(&s.waiters, 1)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.ch
+}
+
+func (s *signal) done() {
+	atomic.AddInt32(&s.waiters, -1)
+}
+
+func (s *signal) broadcast() {
+	if atomic.LoadInt32(&s.waiters) == 0 {
+		return
+	}
+	s.lock.Lock()
+	close(s.ch)
+	s.ch = make(chan struct{})
+	s.lock.Unlock()
+}
+
+// Blocking wraps a NonBlocking queue, Put blocks when queue is full and Take blocks when queue is empty
+//	instead of busy-looping, waiters sleep until the other side makes progress
+type Blocking struct {
+	q                 NonBlocking
+	notEmpty, notFull *signal
+}
+
+// NewBlocking creates a new blocking queue on top of q
+func NewBlocking(q NonBlocking) *Blocking {
+	return &Blocking{
+		q:        q,
+		notEmpty: newSignal(),
+		notFull:  newSignal(),
+	}
+}
+
+// NewBlockingMSQueue creates a new unbounded blocking queue, Put never blocks
+func NewBlockingMSQueue() *Blocking {
+	return NewBlocking(NewMSQueue())
+}
+
+// NewBlockingLockFree creates a new bounded blocking queue with capacity cap (see NewLockFree)
+func NewBlockingLockFree(cap uint32) *Blocking {
+	return NewBlocking(NewLockFree(cap))
+}
+
+// TryPut puts val without blocking, returns false if queue is full
+func (bq *Blocking) TryPut(val interface{}) bool {
+	if bq.q.TryEnqueue(val) {
+		bq.notEmpty.broadcast()
+		return true
+	}
+	return false
+}
+
+// TryTake takes val without blocking, returns false if queue is empty
+func (bq *Blocking) TryTake() (val interface{}, success bool) {
+	if val, success = bq.q.TryDequeue(); success {
+		bq.notFull.broadcast()
+	}
+	return
+}
+
+// Put puts val, blocks until there is space or ctx is done
+func (bq *Blocking) Put(ctx context.Context, val interface{}) error {
+	for {
+		if bq.TryPut(val) {
+			return nil
+		}
+		ch := bq.notFull.wait()
+		// check again after registered, in case space is freed before that
+		if bq.TryPut(val) {
+			bq.notFull.done()
+			return nil
+		}
+		select {
+		case <-ch:
+			bq.notFull.done()
+		case <-ctx.Done():
+			bq.notFull.done()
+			return ctx.Err()
+		}
+	}
+}
+
+// Take takes val, blocks until there is one or ctx is done
+func (bq *Blocking) Take(ctx context.Context) (interface{}, error) {
+	for {
+		if val, success := bq.TryTake(); success {
+			return val, nil
+		}
+		ch := bq.notEmpty.wait()
+		// check again after registered, in case val is put before that
+		if val, success := bq.TryTake(); success {
+			bq.notEmpty.done()
+			return val, nil
+		}
+		select {
+		case <-ch:
+			bq.notEmpty.done()
+		case <-ctx.Done():
+			bq.notEmpty.done()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// PutTimeout puts val, blocks at most timeout, returns context.DeadlineExceeded on timeout
+func (bq *Blocking) PutTimeout(val interface{}, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return bq.Put(ctx, val)
+}
+
+// TakeTimeout takes val, blocks at most timeout, returns context.DeadlineExceeded on timeout
+func (bq *Blocking) TakeTimeout(timeout time.Duration) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return bq.Take(ctx)
+}
@@ -0,0 +1,102 @@
+package queue
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBlocking(t *testing.T) {
+	var _ NonBlocking = (*MSQueue)(nil)
+	var _ NonBlocking = (*LockFree)(nil)
+
+	for _, bq := range []*Blocking{NewBlockingMSQueue(), NewBlockingLockFree(8)} {
+		val, ok := bq.TryTake()
+		assert.False(t, ok)
+		assert.Nil(t, val)
+
+		val, err := bq.TakeTimeout(10 * time.Millisecond)
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.Nil(t, val)
+
+		assert.NoError(t, bq.Put(context.Background(), 1))
+		assert.True(t, bq.TryPut(2))
+		val, err = bq.Take(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, val)
+		val, ok = bq.TryTake()
+		assert.True(t, ok)
+		assert.Equal(t, 2, val)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+		}()
+		_, err = bq.Take(ctx)
+		assert.Equal(t, context.Canceled, err)
+	}
+}
+
+func TestBlocking_Full(t *testing.T) {
+	bq := NewBlockingLockFree(8)
+	// max size = cap - 1
+	for i := 0; i < 7; i++ {
+		assert.True(t, bq.TryPut(i))
+	}
+	assert.False(t, bq.TryPut(7))
+	assert.Equal(t, context.DeadlineExceeded, bq.PutTimeout(7, 10*time.Millisecond))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_, _ = bq.Take(context.Background())
+	}()
+	assert.NoError(t, bq.PutTimeout(7, time.Second))
+
+	for i := 1; i < 8; i++ {
+		val, err := bq.TakeTimeout(time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, i, val)
+	}
+}
+
+func TestBlocking_Concurrent(t *testing.T) {
+	for _, bq := range []*Blocking{NewBlockingMSQueue(), NewBlockingLockFree(16)} {
+		producers, consumers, cnt := 4, 4, 2000
+		sum := make(chan int, consumers)
+
+		for c := 0; c < consumers; c++ {
+			go func() {
+				s := 0
+				for i := 0; i < cnt; i++ {
+					val, err := bq.TakeTimeout(5 * time.Second)
+					if err != nil {
+						break
+					}
+					s += val.(int)
+				}
+				sum <- s
+			}()
+		}
+
+		wg := sync.WaitGroup{}
+		for p := 0; p < producers; p++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < cnt; i++ {
+					assert.NoError(t, bq.PutTimeout(i, 5*time.Second))
+				}
+			}()
+		}
+		wg.Wait()
+
+		total := 0
+		for c := 0; c < consumers; c++ {
+			total += <-sum
+		}
+		assert.Equal(t, producers*cnt*(cnt-1)/2, total)
+	}
+}
@@ -56,6 +56,14 @@ func (dq *DequeRW) Values() []interface{} {
 	return dq.dq.Values()
 }
 
+// Each visits elements from front to back until f returns false
+//	the read lock is held during the whole visit, so f must not modify the deque
+func (dq *DequeRW) Each(f func(value interface{}) bool) {
+	dq.lock.RLock()
+	defer dq.lock.RUnlock()
+	dq.dq.Each(f)
+}
+
 // String for print
 func (dq *DequeRW) String() string {
 	dq.lock.RLock()
@@ -2,6 +2,7 @@ package deque
 
 import (
 	"fmt"
+	"godev/basic"
 )
 
 // minCap represents the minimum capacity of deque
@@ -62,11 +63,6 @@ func (dq *Deque) Values() []interface{} {
 	if dq.Empty() {
 		return nil
 	}
-	if dq.tail == dq.head {
-		buf := make([]interface{}, dq.cap)
-		copy(buf, dq.buf)
-		return buf
-	}
 	if dq.tail > dq.head {
 		buf := make([]interface{}, dq.tail-dq.head)
 		copy(buf, dq.buf[dq.head:dq.tail])
@@ -78,6 +74,41 @@ func (dq *Deque) Values() []interface{} {
 	return buf
 }
 
+// Each visits elements from front to back until f returns false
+func (dq *Deque) Each(f func(value interface{}) bool) {
+	for i := 0; i < dq.cnt; i++ {
+		if !f(dq.buf[(dq.head+i)&(dq.cap-1)]) {
+			return
+		}
+	}
+}
+
+// Iterator returns an iterator from front to back
+//	notice: the deque must not be modified during iteration
+func (dq *Deque) Iterator() basic.Iterator {
+	return &iterator{dq: dq}
+}
+
+type iterator struct {
+	dq     *Deque
+	cursor int
+}
+
+// HasNext to meet basic.Iterator interface
+func (iter *iterator) HasNext() bool {
+	return iter.cursor < iter.dq.cnt
+}
+
+// Next to meet basic.Iterator interface
+func (iter *iterator) Next() interface{} {
+	if !iter.HasNext() {
+		return nil
+	}
+	v := iter.dq.buf[(iter.dq.head+iter.cursor)&(iter.dq.cap-1)]
+	iter.cursor++
+	return v
+}
+
 // String for print
 func (dq *Deque) String() string {
 	return fmt.Sprintf("Deque: \n\tCap: %d\n\tPositionsCanPopFront: %d\n\tPositionsCanPushBack: %d\n\tBuffer: %+v\n",
@@ -81,3 +81,10 @@ func (pq *PQBin) Values() []interface{} {
 	}
 	return res
 }
+
+// Each visits values stored in the queue (unordered) until f returns false
+func (pq *PQBin) Each(f func(value interface{}) bool) {
+	pq.items.Each(func(v interface{}) bool {
+		return f(v.(*item).content)
+	})
+}
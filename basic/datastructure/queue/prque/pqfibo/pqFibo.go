@@ -75,3 +75,10 @@ func (pq *PQFib) Values() []interface{} {
 	}
 	return res
 }
+
+// Each visits values stored in the queue (unordered) until f returns false
+func (pq *PQFib) Each(f func(value interface{}) bool) {
+	pq.items.Each(func(v interface{}) bool {
+		return f(v.(*item).content)
+	})
+}
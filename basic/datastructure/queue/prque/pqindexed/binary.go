@@ -0,0 +1,168 @@
+package pqindexed
+
+import (
+	"godev/basic"
+)
+
+// Binary indexed priority queue based on binary heap
+//	Push / Pop / Update / Remove: O(log n)
+type Binary struct {
+	items      []*Handle
+	comparator basic.Comparator
+}
+
+// NewBinary creates a new priority queue based on binary heap
+func NewBinary(comparator basic.Comparator) *Binary {
+	return &Binary{
+		items:      nil,
+		comparator: comparator,
+	}
+}
+
+func (pq *Binary) less(i, j int) bool {
+	return pq.comparator(pq.items[i].priority, pq.items[j].priority) < 0
+}
+
+func (pq *Binary) swap(i, j int) {
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+	pq.items[i].index = i
+	pq.items[j].index = j
+}
+
+func (pq *Binary) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !pq.less(i, parent) {
+			break
+		}
+		pq.swap(i, parent)
+		i = parent
+	}
+}
+
+// down returns true if item i moved
+func (pq *Binary) down(i int) bool {
+	i0 := i
+	n := len(pq.items)
+	for {
+		child := 2*i + 1
+		if child >= n {
+			break
+		}
+		if right := child + 1; right < n && pq.less(right, child) {
+			child = right
+		}
+		if !pq.less(child, i) {
+			break
+		}
+		pq.swap(i, child)
+		i = child
+	}
+	return i > i0
+}
+
+// Push pushes value with priority and returns its handle
+func (pq *Binary) Push(value, priority interface{}) *Handle {
+	h := newHandle(value, priority, pq)
+	h.index = len(pq.items)
+	pq.items = append(pq.items, h)
+	pq.up(h.index)
+	return h
+}
+
+// Peek returns handle with minimum priority without removing it
+func (pq *Binary) Peek() *Handle {
+	if len(pq.items) == 0 {
+		return nil
+	}
+	return pq.items[0]
+}
+
+// Pop removes and returns handle with minimum priority
+func (pq *Binary) Pop() *Handle {
+	if len(pq.items) == 0 {
+		return nil
+	}
+	h := pq.items[0]
+	pq.remove(0)
+	return h
+}
+
+func (pq *Binary) remove(i int) {
+	last := len(pq.items) - 1
+	h := pq.items[i]
+	if i != last {
+		pq.swap(i, last)
+	}
+	// avoid memory leak
+	pq.items[last] = nil
+	pq.items = pq.items[:last]
+	if i != last && !pq.down(i) {
+		pq.up(i)
+	}
+	h.reset()
+	h.queue = nil
+}
+
+// Update changes priority of handle, returns false if handle is not inside the queue
+func (pq *Binary) Update(h *Handle, priority interface{}) bool {
+	if !pq.Contains(h) {
+		return false
+	}
+	h.priority = priority
+	if !pq.down(h.index) {
+		pq.up(h.index)
+	}
+	return true
+}
+
+// Remove removes handle from the queue, returns false if handle is not inside the queue
+func (pq *Binary) Remove(h *Handle) bool {
+	if !pq.Contains(h) {
+		return false
+	}
+	pq.remove(h.index)
+	return true
+}
+
+// Contains returns true if handle is inside the queue
+func (pq *Binary) Contains(h *Handle) bool {
+	return h != nil && h.queue == pq
+}
+
+// Size returns queue length
+func (pq *Binary) Size() int {
+	return len(pq.items)
+}
+
+// Empty returns true if queue is empty
+func (pq *Binary) Empty() bool {
+	return len(pq.items) == 0
+}
+
+// Clear clears the queue
+func (pq *Binary) Clear() {
+	for _, h := range pq.items {
+		h.reset()
+		h.queue = nil
+	}
+	pq.items = nil
+}
+
+// Values returns values stored in the queue (unordered)
+func (pq *Binary) Values() []interface{} {
+	values := make([]interface{}, len(pq.items))
+	for i, h := range pq.items {
+		values[i] = h.value
+	}
+	return values
+}
+
+// Each visits values in the same order as Values until f returns false
+func (pq *Binary) Each(f func(value interface{}) bool) {
+	for _, h := range pq.items {
+		if !f(h.value) {
+			return
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package pqindexed
+
+import (
+	"godev/basic"
+)
+
+// Fibonacci indexed priority queue based on fibonacci heap
+//	Push / decrease priority: O(1) amortized, Pop / Remove: O(log n) amortized
+//	https://en.wikipedia.org/wiki/Fibonacci_heap
+type Fibonacci struct {
+	min        *Handle
+	comparator basic.Comparator
+	itemNum    int
+}
+
+// NewFibonacci creates a new priority queue based on fibonacci heap
+func NewFibonacci(comparator basic.Comparator) *Fibonacci {
+	return &Fibonacci{
+		min:        nil,
+		comparator: comparator,
+		itemNum:    0,
+	}
+}
+
+func (pq *Fibonacci) less(a, b *Handle) bool {
+	return pq.comparator(a.priority, b.priority) < 0
+}
+
+// insert h into circular list before node
+func insertBefore(h, node *Handle) {
+	h.left = node.left
+	h.right = node
+	node.left.right = h
+	node.left = h
+}
+
+// remove h from its circular list
+func removeFromList(h *Handle) {
+	h.left.right = h.right
+	h.right.left = h.left
+	h.left, h.right = h, h
+}
+
+func (pq *Fibonacci) addRoot(h *Handle) {
+	h.parent = nil
+	h.marked = false
+	if pq.min == nil {
+		h.left, h.right = h, h
+		pq.min = h
+		return
+	}
+	insertBefore(h, pq.min)
+	if pq.less(h, pq.min) {
+		pq.min = h
+	}
+}
+
+// Push pushes value with priority and returns its handle
+func (pq *Fibonacci) Push(value, priority interface{}) *Handle {
+	h := newHandle(value, priority, pq)
+	pq.addRoot(h)
+	pq.itemNum++
+	return h
+}
+
+// Peek returns handle with minimum priority without removing it
+func (pq *Fibonacci) Peek() *Handle {
+	return pq.min
+}
+
+// Pop removes and returns handle with minimum priority
+func (pq *Fibonacci) Pop() *Handle {
+	z := pq.min
+	if z == nil {
+		return nil
+	}
+	// move children to roots list
+	for z.child != nil {
+		c := z.child
+		if c.right == c {
+			z.child = nil
+		} else {
+			z.child = c.right
+		}
+		removeFromList(c)
+		insertBefore(c, z)
+		c.parent = nil
+		c.marked = false
+	}
+	if z.right == z {
+		pq.min = nil
+	} else {
+		pq.min = z.right
+		removeFromList(z)
+		pq.consolidate()
+	}
+	pq.itemNum--
+	z.reset()
+	z.queue = nil
+	return z
+}
+
+// consolidate links roots with same degree until every root has distinct degree
+func (pq *Fibonacci) consolidate() {
+	var roots []*Handle
+	for h := pq.min; ; {
+		roots = append(roots, h)
+		h = h.right
+		if h == pq.min {
+			break
+		}
+	}
+
+	var degrees []*Handle
+	for _, x := range roots {
+		removeFromList(x)
+		d := x.degree
+		for d < len(degrees) && degrees[d] != nil {
+			y := degrees[d]
+			if pq.less(y, x) {
+				x, y = y, x
+			}
+			pq.link(y, x)
+			degrees[d] = nil
+			d++
+		}
+		for d >= len(degrees) {
+			degrees = append(degrees, nil)
+		}
+		degrees[d] = x
+	}
+
+	pq.min = nil
+	for _, x := range degrees {
+		if x != nil {
+			pq.addRoot(x)
+		}
+	}
+}
+
+// link makes y a child of x
+func (pq *Fibonacci) link(y, x *Handle) {
+	y.parent = x
+	y.marked = false
+	if x.child == nil {
+		y.left, y.right = y, y
+		x.child = y
+	} else {
+		insertBefore(y, x.child)
+	}
+	x.degree++
+}
+
+// cut moves x from children of y to roots list
+func (pq *Fibonacci) cut(x, y *Handle) {
+	if x.right == x {
+		y.child = nil
+	} else if y.child == x {
+		y.child = x.right
+	}
+	removeFromList(x)
+	y.degree--
+	pq.addRoot(x)
+}
+
+func (pq *Fibonacci) cascadingCut(y *Handle) {
+	for z := y.parent; z != nil; y, z = z, z.parent {
+		if !y.marked {
+			y.marked = true
+			return
+		}
+		pq.cut(y, z)
+	}
+}
+
+// Update changes priority of handle, returns false if handle is not inside the queue
+func (pq *Fibonacci) Update(h *Handle, priority interface{}) bool {
+	if !pq.Contains(h) {
+		return false
+	}
+	if pq.comparator(priority, h.priority) < 0 {
+		// decrease key
+		h.priority = priority
+		if p := h.parent; p != nil && pq.less(h, p) {
+			pq.cut(h, p)
+			pq.cascadingCut(p)
+		}
+		if pq.less(h, pq.min) {
+			pq.min = h
+		}
+		return true
+	}
+	// increase: remove then insert again
+	pq.Remove(h)
+	h.priority = priority
+	h.queue = pq
+	pq.addRoot(h)
+	pq.itemNum++
+	return true
+}
+
+// Remove removes handle from the queue, returns false if handle is not inside the queue
+func (pq *Fibonacci) Remove(h *Handle) bool {
+	if !pq.Contains(h) {
+		return false
+	}
+	// same as decreasing to minus infinity then Pop
+	if p := h.parent; p != nil {
+		pq.cut(h, p)
+		pq.cascadingCut(p)
+	}
+	pq.min = h
+	pq.Pop()
+	return true
+}
+
+// Contains returns true if handle is inside the queue
+func (pq *Fibonacci) Contains(h *Handle) bool {
+	return h != nil && h.queue == pq
+}
+
+// Size returns queue length
+func (pq *Fibonacci) Size() int {
+	return pq.itemNum
+}
+
+// Empty returns true if queue is empty
+func (pq *Fibonacci) Empty() bool {
+	return pq.itemNum == 0
+}
+
+// Clear clears the queue
+func (pq *Fibonacci) Clear() {
+	pq.each(pq.min, func(h *Handle) bool {
+		h.queue = nil
+		return true
+	})
+	pq.min = nil
+	pq.itemNum = 0
+}
+
+func (pq *Fibonacci) each(start *Handle, f func(h *Handle) bool) bool {
+	if start == nil {
+		return true
+	}
+	h := start
+	for {
+		next := h.right
+		if !pq.each(h.child, f) || !f(h) {
+			return false
+		}
+		h = next
+		if h == start {
+			return true
+		}
+	}
+}
+
+// Values returns values stored in the queue (unordered)
+func (pq *Fibonacci) Values() []interface{} {
+	values := make([]interface{}, 0, pq.itemNum)
+	pq.each(pq.min, func(h *Handle) bool {
+		values = append(values, h.value)
+		return true
+	})
+	return values
+}
+
+// Each visits values in the same order as Values until f returns false
+func (pq *Fibonacci) Each(f func(value interface{}) bool) {
+	pq.each(pq.min, func(h *Handle) bool {
+		return f(h.value)
+	})
+}
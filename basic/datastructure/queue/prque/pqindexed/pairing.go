@@ -0,0 +1,195 @@
+package pqindexed
+
+import (
+	"godev/basic"
+)
+
+// Pairing indexed priority queue based on pairing heap
+//	Push: O(1), Pop / Remove: O(log n) amortized, decrease priority: o(log n) amortized
+//	https://en.wikipedia.org/wiki/Pairing_heap
+type Pairing struct {
+	root       *Handle
+	comparator basic.Comparator
+	itemNum    int
+}
+
+// NewPairing creates a new priority queue based on pairing heap
+func NewPairing(comparator basic.Comparator) *Pairing {
+	return &Pairing{
+		root:       nil,
+		comparator: comparator,
+		itemNum:    0,
+	}
+}
+
+// meld links two heap roots, the larger one becomes the first child of the other
+func (pq *Pairing) meld(a, b *Handle) *Handle {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if pq.comparator(b.priority, a.priority) < 0 {
+		a, b = b, a
+	}
+	b.right = a.child
+	if a.child != nil {
+		a.child.left = b
+	}
+	b.left = a
+	a.child = b
+	return a
+}
+
+// mergePairs melds sub-heaps in pairs from left to right, then melds the results from right to left
+func (pq *Pairing) mergePairs(first *Handle) *Handle {
+	var pairs []*Handle
+	for first != nil {
+		a := first
+		b := a.right
+		if b == nil {
+			first = nil
+		} else {
+			first = b.right
+			b.left, b.right = nil, nil
+		}
+		a.left, a.right = nil, nil
+		pairs = append(pairs, pq.meld(a, b))
+	}
+	var root *Handle
+	for i := len(pairs) - 1; i >= 0; i-- {
+		root = pq.meld(pairs[i], root)
+	}
+	return root
+}
+
+// detach cuts sub-heap of h from its parent / siblings
+func (pq *Pairing) detach(h *Handle) {
+	if h.left.child == h {
+		h.left.child = h.right
+	} else {
+		h.left.right = h.right
+	}
+	if h.right != nil {
+		h.right.left = h.left
+	}
+	h.left, h.right = nil, nil
+}
+
+// Push pushes value with priority and returns its handle
+func (pq *Pairing) Push(value, priority interface{}) *Handle {
+	h := newHandle(value, priority, pq)
+	pq.root = pq.meld(pq.root, h)
+	pq.itemNum++
+	return h
+}
+
+// Peek returns handle with minimum priority without removing it
+func (pq *Pairing) Peek() *Handle {
+	return pq.root
+}
+
+// Pop removes and returns handle with minimum priority
+func (pq *Pairing) Pop() *Handle {
+	h := pq.root
+	if h == nil {
+		return nil
+	}
+	pq.root = pq.mergePairs(h.child)
+	pq.itemNum--
+	h.reset()
+	h.queue = nil
+	return h
+}
+
+// Update changes priority of handle, returns false if handle is not inside the queue
+func (pq *Pairing) Update(h *Handle, priority interface{}) bool {
+	if !pq.Contains(h) {
+		return false
+	}
+	if pq.comparator(priority, h.priority) < 0 {
+		// decrease: cut the sub-heap and meld it with root
+		h.priority = priority
+		if h != pq.root {
+			pq.detach(h)
+			pq.root = pq.meld(pq.root, h)
+		}
+		return true
+	}
+	// increase: remove then insert again
+	pq.Remove(h)
+	h.priority = priority
+	h.queue = pq
+	pq.root = pq.meld(pq.root, h)
+	pq.itemNum++
+	return true
+}
+
+// Remove removes handle from the queue, returns false if handle is not inside the queue
+func (pq *Pairing) Remove(h *Handle) bool {
+	if !pq.Contains(h) {
+		return false
+	}
+	if h == pq.root {
+		pq.Pop()
+		return true
+	}
+	pq.detach(h)
+	pq.root = pq.meld(pq.root, pq.mergePairs(h.child))
+	pq.itemNum--
+	h.reset()
+	h.queue = nil
+	return true
+}
+
+// Contains returns true if handle is inside the queue
+func (pq *Pairing) Contains(h *Handle) bool {
+	return h != nil && h.queue == pq
+}
+
+// Size returns queue length
+func (pq *Pairing) Size() int {
+	return pq.itemNum
+}
+
+// Empty returns true if queue is empty
+func (pq *Pairing) Empty() bool {
+	return pq.itemNum == 0
+}
+
+// Clear clears the queue
+func (pq *Pairing) Clear() {
+	pq.each(pq.root, func(h *Handle) bool {
+		h.queue = nil
+		return true
+	})
+	pq.root = nil
+	pq.itemNum = 0
+}
+
+func (pq *Pairing) each(h *Handle, f func(h *Handle) bool) bool {
+	for ; h != nil; h = h.right {
+		if !pq.each(h.child, f) || !f(h) {
+			return false
+		}
+	}
+	return true
+}
+
+// Values returns values stored in the queue (unordered)
+func (pq *Pairing) Values() []interface{} {
+	values := make([]interface{}, 0, pq.itemNum)
+	pq.each(pq.root, func(h *Handle) bool {
+		values = append(values, h.value)
+		return true
+	})
+	return values
+}
+
+// Each visits values in the same order as Values until f returns false
+func (pq *Pairing) Each(f func(value interface{}) bool) {
+	pq.each(pq.root, func(h *Handle) bool {
+		return f(h.value)
+	})
+}
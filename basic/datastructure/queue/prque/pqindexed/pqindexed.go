@@ -0,0 +1,70 @@
+package pqindexed
+
+import (
+	"godev/basic"
+)
+
+// PriorityQueue interface of indexed priority queue
+//	the element with minimum priority (by comparator) is popped first,
+//	use Reverse(comparator) to pop maximum first
+//	Push returns a Handle, which can be used to Update / Remove the element later (decrease-key)
+type PriorityQueue interface {
+	Push(value, priority interface{}) *Handle
+	Peek() *Handle
+	Pop() *Handle
+	Update(h *Handle, priority interface{}) bool
+	Remove(h *Handle) bool
+	Contains(h *Handle) bool
+
+	basic.Iterable
+}
+
+// Handle of an element inside the queue
+//	it is also the node of pointer based heaps
+type Handle struct {
+	value, priority interface{}
+	// queue which the handle belongs to, nil when popped or removed
+	queue PriorityQueue
+
+	// binary heap
+	index int
+
+	// pairing heap: child (first child), left (previous sibling or parent), right (next sibling)
+	// fibonacci heap: parent, child (any child), left / right (circular sibling list)
+	parent, child, left, right *Handle
+	degree                     int
+	marked                     bool
+}
+
+func newHandle(value, priority interface{}, queue PriorityQueue) *Handle {
+	return &Handle{
+		value:    value,
+		priority: priority,
+		queue:    queue,
+	}
+}
+
+// Value returns value of the element
+func (h *Handle) Value() interface{} {
+	return h.value
+}
+
+// Priority returns priority of the element
+func (h *Handle) Priority() interface{} {
+	return h.priority
+}
+
+// reset clears links of the handle
+func (h *Handle) reset() {
+	h.index = -1
+	h.parent, h.child, h.left, h.right = nil, nil, nil, nil
+	h.degree = 0
+	h.marked = false
+}
+
+// Reverse returns comparator in reversed order, e.g. to pop maximum first
+func Reverse(comparator basic.Comparator) basic.Comparator {
+	return func(a, b interface{}) int {
+		return comparator(b, a)
+	}
+}
@@ -0,0 +1,192 @@
+package pqindexed
+
+import (
+	"godev/basic"
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+var constructors = map[string]func(comparator basic.Comparator) PriorityQueue{
+	"Binary":    func(c basic.Comparator) PriorityQueue { return NewBinary(c) },
+	"Pairing":   func(c basic.Comparator) PriorityQueue { return NewPairing(c) },
+	"Fibonacci": func(c basic.Comparator) PriorityQueue { return NewFibonacci(c) },
+}
+
+func TestPriorityQueue(t *testing.T) {
+	var _ PriorityQueue = (*Binary)(nil)
+	var _ PriorityQueue = (*Pairing)(nil)
+	var _ PriorityQueue = (*Fibonacci)(nil)
+
+	for name, newPQ := range constructors {
+		pq := newPQ(basic.IntComparator)
+		if !pq.Empty() || pq.Peek() != nil || pq.Pop() != nil {
+			t.Error(name)
+		}
+
+		a := pq.Push("a", 6)
+		b := pq.Push("b", 5)
+		c := pq.Push("c", 10)
+		pq.Push("d", 3)
+		if pq.Size() != 4 || pq.Peek().Value() != "d" || len(pq.Values()) != 4 {
+			t.Error(name)
+		}
+
+		// decrease key
+		if !pq.Update(c, 1) || pq.Peek() != c || c.Priority() != 1 {
+			t.Error(name)
+		}
+		// increase key
+		if !pq.Update(c, 100) || pq.Peek().Value() != "d" {
+			t.Error(name)
+		}
+		if !pq.Remove(b) || pq.Remove(b) || pq.Update(b, 1) || pq.Contains(b) {
+			t.Error(name)
+		}
+
+		var got []string
+		for !pq.Empty() {
+			got = append(got, pq.Pop().Value().(string))
+		}
+		if len(got) != 3 || got[0] != "d" || got[1] != "a" || got[2] != "c" {
+			t.Error(name, got)
+		}
+		if pq.Contains(a) || pq.Update(a, 1) {
+			t.Error(name)
+		}
+
+		// handles of other queues are rejected
+		other := newPQ(basic.IntComparator)
+		h := other.Push("x", 1)
+		if pq.Contains(h) || pq.Remove(h) || other.Size() != 1 {
+			t.Error(name)
+		}
+		other.Clear()
+		if !other.Empty() || other.Contains(h) {
+			t.Error(name)
+		}
+
+		// max queue
+		pq = newPQ(Reverse(basic.IntComparator))
+		for i := 0; i < 10; i++ {
+			pq.Push(i, i)
+		}
+		if pq.Pop().Value() != 9 {
+			t.Error(name)
+		}
+	}
+}
+
+func TestPriorityQueue_Random(t *testing.T) {
+	for name, newPQ := range constructors {
+		r := rand.New(rand.NewSource(1))
+		pq := newPQ(basic.IntComparator)
+		handles := map[*Handle]int{}
+
+		for i := 0; i < 20000; i++ {
+			switch op := r.Intn(10); {
+			case op < 4 || len(handles) == 0:
+				p := r.Intn(1000)
+				handles[pq.Push(i, p)] = p
+			case op < 6:
+				h := pq.Pop()
+				for _, p := range handles {
+					if p < h.Priority().(int) {
+						t.Fatal(name, "popped priority is not minimum")
+					}
+				}
+				delete(handles, h)
+			default:
+				for h := range handles {
+					if op < 8 {
+						p := r.Intn(1000)
+						pq.Update(h, p)
+						handles[h] = p
+					} else {
+						pq.Remove(h)
+						delete(handles, h)
+					}
+					break
+				}
+			}
+			if pq.Size() != len(handles) {
+				t.Fatal(name, "size mismatch")
+			}
+		}
+
+		var priorities []int
+		for !pq.Empty() {
+			priorities = append(priorities, pq.Pop().Priority().(int))
+		}
+		if len(priorities) != len(handles) || !sort.IntsAreSorted(priorities) {
+			t.Error(name, "not sorted")
+		}
+	}
+}
+
+// dijkstra on a random graph, the typical usage of decrease-key
+func dijkstra(pq PriorityQueue, graph [][]edge, source int) []int {
+	dist := make([]int, len(graph))
+	handles := make([]*Handle, len(graph))
+	for i := range dist {
+		dist[i] = -1
+	}
+	dist[source] = 0
+	handles[source] = pq.Push(source, 0)
+	for !pq.Empty() {
+		u := pq.Pop().Value().(int)
+		for _, e := range graph[u] {
+			d := dist[u] + e.weight
+			if dist[e.to] == -1 {
+				dist[e.to] = d
+				handles[e.to] = pq.Push(e.to, d)
+			} else if d < dist[e.to] && pq.Contains(handles[e.to]) {
+				dist[e.to] = d
+				pq.Update(handles[e.to], d)
+			}
+		}
+	}
+	return dist
+}
+
+type edge struct {
+	to, weight int
+}
+
+func randomGraph(n, m int) [][]edge {
+	r := rand.New(rand.NewSource(2))
+	graph := make([][]edge, n)
+	for i := 0; i < m; i++ {
+		u := r.Intn(n)
+		graph[u] = append(graph[u], edge{to: r.Intn(n), weight: r.Intn(100) + 1})
+	}
+	return graph
+}
+
+func TestDijkstra(t *testing.T) {
+	graph := randomGraph(500, 5000)
+	want := dijkstra(NewBinary(basic.IntComparator), graph, 0)
+	for name, newPQ := range constructors {
+		got := dijkstra(newPQ(basic.IntComparator), graph, 0)
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatal(name, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func BenchmarkDijkstra(b *testing.B) {
+	for _, n := range []int{1000, 10000} {
+		graph := randomGraph(n, n*10)
+		for _, name := range []string{"Binary", "Pairing", "Fibonacci"} {
+			newPQ := constructors[name]
+			b.Run(name+": size-"+strconv.Itoa(n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					dijkstra(newPQ(basic.IntComparator), graph, 0)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package queue
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// references:
+// Michael, M. M. & Scott, M. L. (1996). "Simple, Fast, and Practical Non-Blocking and Blocking Concurrent Queue Algorithms"
+
+type msNode struct {
+	val  interface{}
+	next unsafe.Pointer // *msNode
+}
+
+// MSQueue is an unbounded lock-free queue (Michael-Scott queue)
+//	notice: FIFO, multiple producers and multiple consumers
+//	ABA problem does not exist here since nodes are never reused (GC)
+type MSQueue struct {
+	size       int64          // first field for 64-bit alignment of atomic ops on 32-bit arch
+	head, tail unsafe.Pointer // *msNode, head is a dummy node
+}
+
+// NewMSQueue creates a new unbounded lock-free queue
+func NewMSQueue() *MSQueue {
+	dummy := unsafe.Pointer(&msNode{})
+	return &MSQueue{
+		size: 0,
+		head: dummy,
+		tail: dummy,
+	}
+}
+
+func load(p *unsafe.Pointer) *msNode {
+	return (*msNode)(atomic.LoadPointer(p))
+}
+
+func cas(p *unsafe.Pointer, old, new *msNode) bool {
+	return atomic.CompareAndSwapPointer(p, unsafe.Pointer(old), unsafe.Pointer(new))
+}
+
+// Enqueue appends val to the tail, it never fails
+func (q *MSQueue) Enqueue(val interface{}) {
+	n := &msNode{val: val}
+	for {
+		tail := load(&q.tail)
+		next := load(&tail.next)
+		if tail != load(&q.tail) {
+			continue
+		}
+		if next != nil {
+			// tail is falling behind, help to advance it
+			cas(&q.tail, tail, next)
+			continue
+		}
+		if cas(&tail.next, nil, n) {
+			// failure is fine, someone else has advanced it
+			cas(&q.tail, tail, n)
+			atomic.AddInt64(&q.size, 1)
+			return
+		}
+	}
+}
+
+// Dequeue removes val from the head, returns false if queue is empty
+//	notice: the dequeued node becomes the new dummy node, so val is kept alive until next Dequeue
+func (q *MSQueue) Dequeue() (val interface{}, success bool) {
+	for {
+		head := load(&q.head)
+		tail := load(&q.tail)
+		next := load(&head.next)
+		if head != load(&q.head) {
+			continue
+		}
+		if next == nil {
+			return nil, false
+		}
+		if head == tail {
+			// tail is falling behind, help to advance it
+			cas(&q.tail, tail, next)
+			continue
+		}
+		val = next.val
+		if cas(&q.head, head, next) {
+			atomic.AddInt64(&q.size, -1)
+			return val, true
+		}
+	}
+}
+
+// Size returns current queue elements size, it may be stale under concurrent operations
+func (q *MSQueue) Size() int {
+	return int(atomic.LoadInt64(&q.size))
+}
+
+// Empty returns whether queue is empty
+func (q *MSQueue) Empty() bool {
+	return load(&load(&q.head).next) == nil
+}
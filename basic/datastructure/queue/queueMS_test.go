@@ -0,0 +1,99 @@
+package queue
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestMSQueue(t *testing.T) {
+	q := NewMSQueue()
+	assert.True(t, q.Empty())
+	assert.Equal(t, 0, q.Size())
+
+	val, ok := q.Dequeue()
+	assert.False(t, ok)
+	assert.Nil(t, val)
+
+	for i := 0; i < 100; i++ {
+		q.Enqueue(i)
+	}
+	assert.False(t, q.Empty())
+	assert.Equal(t, 100, q.Size())
+
+	for i := 0; i < 100; i++ {
+		val, ok := q.Dequeue()
+		assert.True(t, ok)
+		assert.Equal(t, i, val)
+	}
+	assert.True(t, q.Empty())
+	assert.Equal(t, 0, q.Size())
+}
+
+func TestMSQueueConcurrent(t *testing.T) {
+	q := NewMSQueue()
+	producers, consumers, cnt := 4, 4, 10000
+
+	wg := sync.WaitGroup{}
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < cnt; i++ {
+				q.Enqueue(p*cnt + i)
+			}
+		}(p)
+	}
+
+	results := make(chan []int, consumers)
+	var taken int64
+	lock := sync.Mutex{}
+	for c := 0; c < consumers; c++ {
+		go func() {
+			var got []int
+			for {
+				lock.Lock()
+				if taken == int64(producers*cnt) {
+					lock.Unlock()
+					break
+				}
+				lock.Unlock()
+				if val, ok := q.Dequeue(); ok {
+					got = append(got, val.(int))
+					lock.Lock()
+					taken++
+					lock.Unlock()
+				}
+			}
+			results <- got
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[int]bool, producers*cnt)
+	for c := 0; c < consumers; c++ {
+		got := <-results
+		// FIFO per producer
+		last := make(map[int]int)
+		for _, v := range got {
+			assert.False(t, seen[v])
+			seen[v] = true
+			if l, ok := last[v/cnt]; ok {
+				assert.True(t, l < v)
+			}
+			last[v/cnt] = v
+		}
+	}
+	assert.Equal(t, producers*cnt, len(seen))
+	assert.True(t, q.Empty())
+}
+
+func BenchmarkMSQueue(b *testing.B) {
+	q := NewMSQueue()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			q.Enqueue(1)
+			q.Dequeue()
+		}
+	})
+}
@@ -0,0 +1,466 @@
+package rope
+
+import (
+	"io"
+	"math/rand"
+	"strings"
+	"unicode/utf8"
+)
+
+// references:
+// https://en.wikipedia.org/wiki/Rope_(data_structure)
+// https://en.wikipedia.org/wiki/Treap
+
+// maxChunk is the maximum bytes of a chunk, chunks are split on rune boundaries
+const maxChunk = 512
+
+// node is an immutable treap node ordered by position, every node holds a chunk of text
+//	nodes are never modified after creation (path copying), so old roots remain valid snapshots
+type node struct {
+	chunk       string
+	chunkRunes  int
+	chunkLines  int // number of '\n' inside chunk
+	priority    uint32
+	left, right *node
+	// totals of the sub-tree
+	bytes, runes, lines int
+}
+
+func newNode(chunk string, chunkRunes, chunkLines int, priority uint32, left, right *node) *node {
+	n := &node{
+		chunk:      chunk,
+		chunkRunes: chunkRunes,
+		chunkLines: chunkLines,
+		priority:   priority,
+		left:       left,
+		right:      right,
+	}
+	n.bytes = len(chunk) + left.totalBytes() + right.totalBytes()
+	n.runes = chunkRunes + left.totalRunes() + right.totalRunes()
+	n.lines = chunkLines + left.totalLines() + right.totalLines()
+	return n
+}
+
+func leaf(chunk string) *node {
+	return newNode(chunk, utf8.RuneCountInString(chunk), strings.Count(chunk, "\n"), rand.Uint32(), nil, nil)
+}
+
+// with returns a copy of n with new children
+func (n *node) with(left, right *node) *node {
+	return newNode(n.chunk, n.chunkRunes, n.chunkLines, n.priority, left, right)
+}
+
+func (n *node) totalBytes() int {
+	if n == nil {
+		return 0
+	}
+	return n.bytes
+}
+
+func (n *node) totalRunes() int {
+	if n == nil {
+		return 0
+	}
+	return n.runes
+}
+
+func (n *node) totalLines() int {
+	if n == nil {
+		return 0
+	}
+	return n.lines
+}
+
+func merge(a, b *node) *node {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.priority > b.priority {
+		return a.with(a.left, merge(a.right, b))
+	}
+	return b.with(merge(a, b.left), b.right)
+}
+
+// split splits n into the first i runes and the rest
+func split(n *node, i int) (left, right *node) {
+	if n == nil {
+		return nil, nil
+	}
+	lr := n.left.totalRunes()
+	if i <= lr {
+		l, r := split(n.left, i)
+		return l, n.with(r, n.right)
+	}
+	if i >= lr+n.chunkRunes {
+		l, r := split(n.right, i-lr-n.chunkRunes)
+		return n.with(n.left, l), r
+	}
+	b := byteOffset(n.chunk, i-lr)
+	return merge(n.left, leaf(n.chunk[:b])), merge(leaf(n.chunk[b:]), n.right)
+}
+
+// build builds a treap from s, s is cut into chunks on rune boundaries
+func build(s string) *node {
+	var root *node
+	for len(s) > 0 {
+		end := len(s)
+		if end > maxChunk {
+			end = maxChunk
+			for end > 0 && !utf8.RuneStart(s[end]) {
+				end--
+			}
+			if end == 0 {
+				// invalid utf-8
+				end = maxChunk
+			}
+		}
+		root = merge(root, leaf(s[:end]))
+		s = s[end:]
+	}
+	return root
+}
+
+// byteOffset returns byte offset of the i-th rune inside s
+func byteOffset(s string, i int) int {
+	for b := range s {
+		if i == 0 {
+			return b
+		}
+		i--
+	}
+	return len(s)
+}
+
+// Rope struct stands for a text, edits are O(log n) and copying is O(1)
+//	indexes are counted in runes, lines and columns are 0 based and counted in runes
+type Rope struct {
+	root *node
+}
+
+// New creates a rope from s
+func New(s string) *Rope {
+	return &Rope{root: build(s)}
+}
+
+// Len returns number of runes
+func (r *Rope) Len() int {
+	return r.root.totalRunes()
+}
+
+// ByteLen returns number of bytes
+func (r *Rope) ByteLen() int {
+	return r.root.totalBytes()
+}
+
+// LineCount returns number of lines, which is number of '\n' + 1
+func (r *Rope) LineCount() int {
+	return r.root.totalLines() + 1
+}
+
+// Clone returns a copy of the rope in O(1), changes on either one do not affect the other
+func (r *Rope) Clone() *Rope {
+	return &Rope{root: r.root}
+}
+
+// String returns the whole text
+func (r *Rope) String() string {
+	var sb strings.Builder
+	sb.Grow(r.ByteLen())
+	_, _ = r.WriteTo(&sb)
+	return sb.String()
+}
+
+func (r *Rope) checkIndex(i, limit int) {
+	if i < 0 || i > limit {
+		panic("rope: index out of range")
+	}
+}
+
+// Insert inserts s before the i-th rune, 0 <= i <= Len()
+func (r *Rope) Insert(i int, s string) {
+	r.checkIndex(i, r.Len())
+	if s == "" {
+		return
+	}
+	if n, ok := insertInto(r.root, i, s); ok {
+		r.root = n
+		return
+	}
+	left, right := split(r.root, i)
+	r.root = merge(merge(left, build(s)), right)
+}
+
+// insertInto inserts small s into an existing chunk if it still fits, avoiding tiny chunks on typing
+func insertInto(n *node, i int, s string) (*node, bool) {
+	if n == nil {
+		return nil, false
+	}
+	lr := n.left.totalRunes()
+	switch {
+	case i < lr:
+		l, ok := insertInto(n.left, i, s)
+		if !ok {
+			return nil, false
+		}
+		return n.with(l, n.right), true
+	case i > lr+n.chunkRunes:
+		r, ok := insertInto(n.right, i-lr-n.chunkRunes, s)
+		if !ok {
+			return nil, false
+		}
+		return n.with(n.left, r), true
+	}
+	if len(n.chunk)+len(s) > maxChunk {
+		return nil, false
+	}
+	b := byteOffset(n.chunk, i-lr)
+	chunk := n.chunk[:b] + s + n.chunk[b:]
+	return newNode(chunk, n.chunkRunes+utf8.RuneCountInString(s), n.chunkLines+strings.Count(s, "\n"), n.priority, n.left, n.right), true
+}
+
+// Append appends s at the end
+func (r *Rope) Append(s string) {
+	r.Insert(r.Len(), s)
+}
+
+// Concat appends text of other at the end in O(log n), other is not changed
+func (r *Rope) Concat(other *Rope) {
+	r.root = merge(r.root, other.root)
+}
+
+// Delete deletes n runes starting from the i-th rune
+func (r *Rope) Delete(i, n int) {
+	r.checkIndex(i, r.Len())
+	r.checkIndex(i+n, r.Len())
+	left, rest := split(r.root, i)
+	_, right := split(rest, n)
+	r.root = merge(left, right)
+}
+
+// Split cuts the rope at the i-th rune, r keeps the first i runes and the rest is returned
+func (r *Rope) Split(i int) *Rope {
+	r.checkIndex(i, r.Len())
+	left, right := split(r.root, i)
+	r.root = left
+	return &Rope{root: right}
+}
+
+// Substring returns n runes starting from the i-th rune
+func (r *Rope) Substring(i, n int) string {
+	r.checkIndex(i, r.Len())
+	r.checkIndex(i+n, r.Len())
+	var sb strings.Builder
+	substring(r.root, i, i+n, &sb)
+	return sb.String()
+}
+
+// substring writes runes in [from, to) of n into sb
+func substring(n *node, from, to int, sb *strings.Builder) {
+	if n == nil || from >= to {
+		return
+	}
+	lr := n.left.totalRunes()
+	if from < lr {
+		substring(n.left, from, to, sb)
+	}
+	start, end := from-lr, to-lr
+	if start < 0 {
+		start = 0
+	}
+	if end > n.chunkRunes {
+		end = n.chunkRunes
+	}
+	if start < end {
+		sb.WriteString(n.chunk[byteOffset(n.chunk, start):byteOffset(n.chunk, end)])
+	}
+	if offset := lr + n.chunkRunes; to > offset {
+		substring(n.right, from-offset, to-offset, sb)
+	}
+}
+
+// RuneAt returns the i-th rune, 0 <= i < Len()
+func (r *Rope) RuneAt(i int) rune {
+	r.checkIndex(i, r.Len()-1)
+	n := r.root
+	for {
+		lr := n.left.totalRunes()
+		switch {
+		case i < lr:
+			n = n.left
+		case i >= lr+n.chunkRunes:
+			i -= lr + n.chunkRunes
+			n = n.right
+		default:
+			c, _ := utf8.DecodeRuneInString(n.chunk[byteOffset(n.chunk, i-lr):])
+			return c
+		}
+	}
+}
+
+// LineCol returns line and column of the i-th rune, 0 <= i <= Len()
+func (r *Rope) LineCol(i int) (line, col int) {
+	r.checkIndex(i, r.Len())
+	line = countLines(r.root, i)
+	return line, i - r.lineStart(line)
+}
+
+// countLines returns number of '\n' in the first i runes of n
+func countLines(n *node, i int) int {
+	lines := 0
+	for n != nil && i > 0 {
+		lr := n.left.totalRunes()
+		if i <= lr {
+			n = n.left
+			continue
+		}
+		lines += n.left.totalLines()
+		if i >= lr+n.chunkRunes {
+			lines += n.chunkLines
+			i -= lr + n.chunkRunes
+			n = n.right
+			continue
+		}
+		return lines + strings.Count(n.chunk[:byteOffset(n.chunk, i-lr)], "\n")
+	}
+	return lines
+}
+
+// lineStart returns rune index of the beginning of line
+func (r *Rope) lineStart(line int) int {
+	if line == 0 {
+		return 0
+	}
+	// index of the line-th '\n' + 1
+	index := 0
+	n := r.root
+	for {
+		if line <= n.left.totalLines() {
+			n = n.left
+			continue
+		}
+		line -= n.left.totalLines()
+		index += n.left.totalRunes()
+		if line > n.chunkLines {
+			line -= n.chunkLines
+			index += n.chunkRunes
+			n = n.right
+			continue
+		}
+		for _, c := range n.chunk {
+			index++
+			if c == '\n' {
+				if line--; line == 0 {
+					return index
+				}
+			}
+		}
+	}
+}
+
+// lineEnd returns rune index of the end of line (the '\n' or Len())
+func (r *Rope) lineEnd(line int) int {
+	if line == r.LineCount()-1 {
+		return r.Len()
+	}
+	return r.lineStart(line+1) - 1
+}
+
+// Offset returns rune index of line and column, 0 <= col <= length of the line
+func (r *Rope) Offset(line, col int) int {
+	r.checkIndex(line, r.LineCount()-1)
+	start := r.lineStart(line)
+	r.checkIndex(col, r.lineEnd(line)-start)
+	return start + col
+}
+
+// Line returns text of line without '\n'
+func (r *Rope) Line(line int) string {
+	r.checkIndex(line, r.LineCount()-1)
+	start := r.lineStart(line)
+	return r.Substring(start, r.lineEnd(line)-start)
+}
+
+// WriteTo writes the whole text into w, to meet io.WriterTo interface
+func (r *Rope) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+	var err error
+	each(r.root, func(chunk string) bool {
+		var n int
+		n, err = io.WriteString(w, chunk)
+		written += int64(n)
+		return err == nil
+	})
+	return written, err
+}
+
+// each calls f on chunks in order until f returns false
+func each(n *node, f func(chunk string) bool) bool {
+	if n == nil {
+		return true
+	}
+	return each(n.left, f) && f(n.chunk) && each(n.right, f)
+}
+
+// Reader reads text of a rope snapshot, later changes on the rope are not seen
+type Reader struct {
+	stack []*node
+	cur   string
+}
+
+// Reader returns a reader of current text
+func (r *Rope) Reader() *Reader {
+	reader := &Reader{}
+	reader.push(r.root)
+	return reader
+}
+
+func (reader *Reader) push(n *node) {
+	for ; n != nil; n = n.left {
+		reader.stack = append(reader.stack, n)
+	}
+}
+
+// fill moves to the next non-empty chunk, returns false at the end
+func (reader *Reader) fill() bool {
+	for reader.cur == "" {
+		if len(reader.stack) == 0 {
+			return false
+		}
+		n := reader.stack[len(reader.stack)-1]
+		reader.stack = reader.stack[:len(reader.stack)-1]
+		reader.push(n.right)
+		reader.cur = n.chunk
+	}
+	return true
+}
+
+// Read to meet io.Reader interface
+func (reader *Reader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	read := 0
+	for read < len(p) && reader.fill() {
+		n := copy(p[read:], reader.cur)
+		reader.cur = reader.cur[n:]
+		read += n
+	}
+	if read == 0 {
+		return 0, io.EOF
+	}
+	return read, nil
+}
+
+// ReadRune to meet io.RuneReader interface, e.g. used by regexp.MatchReader
+func (reader *Reader) ReadRune() (c rune, size int, err error) {
+	if !reader.fill() {
+		return 0, 0, io.EOF
+	}
+	// chunks never split a rune
+	c, size = utf8.DecodeRuneInString(reader.cur)
+	reader.cur = reader.cur[size:]
+	return c, size, nil
+}
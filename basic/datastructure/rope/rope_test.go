@@ -0,0 +1,228 @@
+package rope
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var alphabet = []rune("ab\n世界🙂 ")
+
+func randomText(r *rand.Rand, n int) string {
+	rs := make([]rune, n)
+	for i := range rs {
+		rs[i] = alphabet[r.Intn(len(alphabet))]
+	}
+	return string(rs)
+}
+
+// lineCol of model
+func lineCol(rs []rune, i int) (line, col int) {
+	for _, c := range rs[:i] {
+		if c == '\n' {
+			line++
+			col = 0
+		} else {
+			col++
+		}
+	}
+	return
+}
+
+func checkRope(t *testing.T, r *Rope, model []rune) {
+	s := string(model)
+	if r.String() != s || r.Len() != len(model) || r.ByteLen() != len(s) || r.LineCount() != strings.Count(s, "\n")+1 {
+		t.Fatal("content mismatch")
+	}
+	lines := strings.Split(s, "\n")
+	for l := range lines {
+		if r.Line(l) != lines[l] {
+			t.Fatal("line", l)
+		}
+	}
+}
+
+func TestRope(t *testing.T) {
+	r := New("")
+	if r.Len() != 0 || r.String() != "" || r.LineCount() != 1 || r.Line(0) != "" {
+		t.Fail()
+	}
+	if line, col := r.LineCol(0); line != 0 || col != 0 {
+		t.Fail()
+	}
+
+	r = New("hello\nworld")
+	r.Insert(5, ",世界")
+	r.Append("!")
+	r.Delete(0, 1)
+	r.Insert(0, "H")
+	if r.String() != "Hello,世界\nworld!" || r.Len() != 15 || r.RuneAt(6) != '世' {
+		t.Fatal(r.String())
+	}
+	if line, col := r.LineCol(10); line != 1 || col != 1 {
+		t.Fail()
+	}
+	if r.Offset(1, 1) != 10 || r.Offset(0, 8) != 8 || r.Substring(6, 2) != "世界" {
+		t.Fail()
+	}
+
+	// snapshot
+	c := r.Clone()
+	reader := r.Reader()
+	r.Delete(0, r.Len())
+	if r.String() != "" || c.String() != "Hello,世界\nworld!" {
+		t.Fail()
+	}
+	if b, err := ioutil.ReadAll(reader); err != nil || string(b) != "Hello,世界\nworld!" {
+		t.Fail()
+	}
+
+	tail := c.Split(6)
+	if c.String() != "Hello," || tail.String() != "世界\nworld!" {
+		t.Fail()
+	}
+	tail.Concat(c)
+	if tail.String() != "世界\nworld!Hello," || c.String() != "Hello," {
+		t.Fail()
+	}
+}
+
+func TestRope_Panic(t *testing.T) {
+	r := New("abc\nd")
+	for _, f := range []func(){
+		func() { r.Insert(6, "x") },
+		func() { r.Insert(-1, "x") },
+		func() { r.Delete(2, 4) },
+		func() { r.Substring(5, 1) },
+		func() { r.RuneAt(5) },
+		func() { r.Offset(2, 0) },
+		func() { r.Offset(0, 4) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fail()
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestRope_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	model := []rune(randomText(rnd, 3000))
+	r := New(string(model))
+	checkRope(t, r, model)
+
+	for step := 0; step < 2000; step++ {
+		switch rnd.Intn(3) {
+		case 0:
+			// typing
+			i := rnd.Intn(len(model) + 1)
+			s := randomText(rnd, 1+rnd.Intn(3))
+			r.Insert(i, s)
+			model = append(model[:i:i], append([]rune(s), model[i:]...)...)
+		case 1:
+			// paste
+			i := rnd.Intn(len(model) + 1)
+			s := randomText(rnd, rnd.Intn(2000))
+			r.Insert(i, s)
+			model = append(model[:i:i], append([]rune(s), model[i:]...)...)
+		default:
+			i := rnd.Intn(len(model) + 1)
+			n := rnd.Intn(len(model) - i + 1)
+			if n > 1500 {
+				n = 1500
+			}
+			r.Delete(i, n)
+			model = append(model[:i:i], model[i+n:]...)
+		}
+
+		i := rnd.Intn(len(model) + 1)
+		n := rnd.Intn(len(model) - i + 1)
+		if r.Substring(i, n) != string(model[i:i+n]) {
+			t.Fatal("substring", step)
+		}
+		if i < len(model) && r.RuneAt(i) != model[i] {
+			t.Fatal("rune at", step)
+		}
+		line, col := r.LineCol(i)
+		if l, c := lineCol(model, i); l != line || c != col {
+			t.Fatal("line col", step, line, col, l, c)
+		}
+		if r.Offset(line, col) != i {
+			t.Fatal("offset", step)
+		}
+		if step%200 == 0 {
+			checkRope(t, r, model)
+		}
+	}
+	checkRope(t, r, model)
+}
+
+func TestReader(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	s := randomText(rnd, 5000)
+	r := New(s)
+
+	b, err := ioutil.ReadAll(iotest.OneByteReader(r.Reader()))
+	if err != nil || string(b) != s {
+		t.Fail()
+	}
+	if err := iotest.TestReader(r.Reader(), []byte(s)); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if n, err := r.WriteTo(&buf); err != nil || n != int64(len(s)) || buf.String() != s {
+		t.Fail()
+	}
+
+	var _ io.RuneReader = r.Reader()
+	if !regexp.MustCompile("🙂").MatchReader(r.Reader()) {
+		t.Fail()
+	}
+	reader := New("a世").Reader()
+	if c, size, err := reader.ReadRune(); c != 'a' || size != 1 || err != nil {
+		t.Fail()
+	}
+	if c, size, err := reader.ReadRune(); c != '世' || size != 3 || err != nil {
+		t.Fail()
+	}
+	if _, _, err := reader.ReadRune(); err != io.EOF {
+		t.Fail()
+	}
+}
+
+const benchSize = 1 << 20
+
+func BenchmarkRope_Insert(b *testing.B) {
+	r := New(strings.Repeat("hello world\n", benchSize/12))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Insert(i*7919%r.Len(), "x")
+	}
+}
+
+func BenchmarkString_Insert(b *testing.B) {
+	s := strings.Repeat("hello world\n", benchSize/12)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		j := i * 7919 % len(s)
+		s = s[:j] + "x" + s[j:]
+	}
+}
+
+func BenchmarkRope_LineCol(b *testing.B) {
+	r := New(strings.Repeat("hello world\n", benchSize/12))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.LineCol(i * 7919 % r.Len())
+	}
+}
@@ -0,0 +1,161 @@
+package set
+
+import (
+	"godev/basic"
+)
+
+// Iterator interface for sets
+type Iterator = basic.Iterator
+
+// Set struct is a hash set of any comparable values
+type Set struct {
+	m map[interface{}]void
+}
+
+// NewSet returns a new Set with input values
+func NewSet(values ...interface{}) *Set {
+	s := &Set{
+		m: make(map[interface{}]void, len(values)),
+	}
+	for _, v := range values {
+		s.m[v] = void{}
+	}
+	return s
+}
+
+// Add adds values into the set
+func (s *Set) Add(values ...interface{}) {
+	for _, v := range values {
+		s.m[v] = void{}
+	}
+}
+
+// Delete deletes values from the set
+func (s *Set) Delete(values ...interface{}) {
+	for _, v := range values {
+		delete(s.m, v)
+	}
+}
+
+// Contains returns true if all input values found in the set
+func (s *Set) Contains(values ...interface{}) bool {
+	for _, v := range values {
+		if _, ok := s.m[v]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Size returns the number of values inside the set
+func (s *Set) Size() int {
+	return len(s.m)
+}
+
+// Empty returns true if no value inside the set
+func (s *Set) Empty() bool {
+	return len(s.m) == 0
+}
+
+// Clear clears the set
+func (s *Set) Clear() {
+	s.m = make(map[interface{}]void)
+}
+
+// Values returns values stored inside the set, no order guaranteed
+func (s *Set) Values() []interface{} {
+	values := make([]interface{}, 0, len(s.m))
+	for k := range s.m {
+		values = append(values, k)
+	}
+	return values
+}
+
+// Union returns a new set with values in either set
+func (s *Set) Union(other *Set) *Set {
+	ns := &Set{
+		m: make(map[interface{}]void, len(s.m)+len(other.m)),
+	}
+	for k := range s.m {
+		ns.m[k] = void{}
+	}
+	for k := range other.m {
+		ns.m[k] = void{}
+	}
+	return ns
+}
+
+// Intersection returns a new set with values in both sets
+func (s *Set) Intersection(other *Set) *Set {
+	small, large := s, other
+	if small.Size() > large.Size() {
+		small, large = large, small
+	}
+	ns := NewSet()
+	for k := range small.m {
+		if _, ok := large.m[k]; ok {
+			ns.m[k] = void{}
+		}
+	}
+	return ns
+}
+
+// Difference returns a new set with values in s but not in other
+//	notice: different from IntSet.Difference, which is symmetric difference
+func (s *Set) Difference(other *Set) *Set {
+	ns := NewSet()
+	for k := range s.m {
+		if _, ok := other.m[k]; !ok {
+			ns.m[k] = void{}
+		}
+	}
+	return ns
+}
+
+// SymmetricDifference returns a new set with values in only one of the sets
+func (s *Set) SymmetricDifference(other *Set) *Set {
+	ns := s.Difference(other)
+	for k := range other.m {
+		if _, ok := s.m[k]; !ok {
+			ns.m[k] = void{}
+		}
+	}
+	return ns
+}
+
+// IsSubset returns true if every value of s is in other
+func (s *Set) IsSubset(other *Set) bool {
+	if s.Size() > other.Size() {
+		return false
+	}
+	for k := range s.m {
+		if _, ok := other.m[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset returns true if every value of other is in s
+func (s *Set) IsSuperset(other *Set) bool {
+	return other.IsSubset(s)
+}
+
+// Equal returns true if two sets have same values
+func (s *Set) Equal(other *Set) bool {
+	return s.Size() == other.Size() && s.IsSubset(other)
+}
+
+// Iterator returns iterator over a snapshot of values
+func (s *Set) Iterator() Iterator {
+	return basic.NewSliceIterator(s.Values())
+}
+
+// Each visits values of the set in no particular order until f returns false
+func (s *Set) Each(f func(value interface{}) bool) {
+	for k := range s.m {
+		if !f(k) {
+			return
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package set
+
+import (
+	"godev/basic"
+	"testing"
+)
+
+type point struct {
+	x, y int
+}
+
+func TestNewSet(t *testing.T) {
+	var _ basic.Container = (*Set)(nil)
+
+	s := NewSet()
+	if !s.Empty() || s.Size() != 0 || len(s.Values()) != 0 || s.Iterator().HasNext() {
+		t.Fail()
+	}
+
+	s = NewSet("a", "b", "a", point{1, 2})
+	if s.Size() != 3 || !s.Contains("a", "b", point{1, 2}) || s.Contains("c") || s.Contains(point{2, 1}) {
+		t.Fail()
+	}
+
+	s.Add("c", "d")
+	s.Delete("a", "e")
+	if s.Size() != 4 || s.Contains("a") {
+		t.Fail()
+	}
+
+	cnt := 0
+	it := s.Iterator()
+	for it.HasNext() {
+		if !s.Contains(it.Next()) {
+			t.Fail()
+		}
+		cnt++
+	}
+	if cnt != 4 || it.Next() != nil {
+		t.Fail()
+	}
+
+	s.Clear()
+	if !s.Empty() {
+		t.Fail()
+	}
+}
+
+func TestSet_Algebra(t *testing.T) {
+	a := NewSet(1, 2, 3, 4)
+	b := NewSet(3, 4, 5)
+
+	if !a.Union(b).Equal(NewSet(1, 2, 3, 4, 5)) {
+		t.Fail()
+	}
+	if !a.Intersection(b).Equal(NewSet(3, 4)) || !b.Intersection(a).Equal(NewSet(3, 4)) {
+		t.Fail()
+	}
+	if !a.Difference(b).Equal(NewSet(1, 2)) || !b.Difference(a).Equal(NewSet(5)) {
+		t.Fail()
+	}
+	if !a.SymmetricDifference(b).Equal(NewSet(1, 2, 5)) {
+		t.Fail()
+	}
+
+	if !NewSet(1, 2).IsSubset(a) || b.IsSubset(a) || !a.IsSuperset(NewSet(1, 2)) || !NewSet().IsSubset(a) {
+		t.Fail()
+	}
+	if a.Equal(b) || !a.Equal(NewSet(4, 3, 2, 1)) {
+		t.Fail()
+	}
+}
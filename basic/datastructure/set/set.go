@@ -41,6 +41,15 @@ func (s *IntSet) Clear() {
 	*s = *(NewIntSet(s.Size()))
 }
 
+// Each visits values of the set in no particular order until f returns false
+func (s *IntSet) Each(f func(value interface{}) bool) {
+	for k := range *s {
+		if !f(k) {
+			return
+		}
+	}
+}
+
 // Values returns values stored inside the set
 func (s *IntSet) Values() []interface{} {
 	keys := make([]interface{}, 0, s.Size())
@@ -147,6 +156,15 @@ func (s *FloatSet) Clear() {
 	*s = *(NewFloatSet(s.Size()))
 }
 
+// Each visits values of the set in no particular order until f returns false
+func (s *FloatSet) Each(f func(value interface{}) bool) {
+	for k := range *s {
+		if !f(k) {
+			return
+		}
+	}
+}
+
 // Values returns values stored inside the set
 func (s *FloatSet) Values() []interface{} {
 	keys := make([]interface{}, 0, s.Size())
@@ -0,0 +1,187 @@
+package set
+
+import (
+	"godev/basic"
+	"godev/basic/datastructure/tree/rbtree"
+)
+
+// TreeSet struct is a sorted set based on red-black tree
+type TreeSet struct {
+	tree *rbtree.RBTree
+}
+
+// NewTreeSet returns a new TreeSet ordered by comparator with input values
+func NewTreeSet(comparator basic.Comparator, values ...interface{}) *TreeSet {
+	s := &TreeSet{
+		tree: rbtree.NewRBTree(comparator),
+	}
+	s.Add(values...)
+	return s
+}
+
+// newTreeSetFromSorted builds a TreeSet from sorted distinct values
+func newTreeSetFromSorted(comparator basic.Comparator, values []interface{}) *TreeSet {
+	s := NewTreeSet(comparator)
+	for _, v := range values {
+		s.tree.Insert(v, void{})
+	}
+	return s
+}
+
+// Comparator returns comparator of the set
+func (s *TreeSet) Comparator() basic.Comparator {
+	return s.tree.Comparator
+}
+
+// Add adds values into the set
+func (s *TreeSet) Add(values ...interface{}) {
+	for _, v := range values {
+		s.tree.Update(v, void{})
+	}
+}
+
+// Delete deletes values from the set
+func (s *TreeSet) Delete(values ...interface{}) {
+	for _, v := range values {
+		if s.tree.Empty() {
+			return
+		}
+		s.tree.Delete(v)
+	}
+}
+
+// Contains returns true if all input values found in the set
+func (s *TreeSet) Contains(values ...interface{}) bool {
+	for _, v := range values {
+		if _, found := s.tree.Get(v); !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Min returns the minimum value
+func (s *TreeSet) Min() (value interface{}, found bool) {
+	if s.tree.Empty() {
+		return nil, false
+	}
+	return s.tree.MinKey(), true
+}
+
+// Max returns the maximum value
+func (s *TreeSet) Max() (value interface{}, found bool) {
+	if s.tree.Empty() {
+		return nil, false
+	}
+	return s.tree.MaxKey(), true
+}
+
+// Size returns the number of values inside the set
+func (s *TreeSet) Size() int {
+	return s.tree.Size()
+}
+
+// Empty returns true if no value inside the set
+func (s *TreeSet) Empty() bool {
+	return s.tree.Empty()
+}
+
+// Clear clears the set
+func (s *TreeSet) Clear() {
+	s.tree.Clear()
+}
+
+// Values returns sorted values stored inside the set
+func (s *TreeSet) Values() []interface{} {
+	return s.tree.Keys()
+}
+
+// merge walks two sorted value slices, keeps values according to which sides they come from
+func (s *TreeSet) merge(other *TreeSet, keepLeft, keepBoth, keepRight bool) []interface{} {
+	a, b := s.Values(), other.Values()
+	var res []interface{}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch c := s.tree.Comparator(a[i], b[j]); {
+		case c < 0:
+			if keepLeft {
+				res = append(res, a[i])
+			}
+			i++
+		case c > 0:
+			if keepRight {
+				res = append(res, b[j])
+			}
+			j++
+		default:
+			if keepBoth {
+				res = append(res, a[i])
+			}
+			i++
+			j++
+		}
+	}
+	if keepLeft {
+		res = append(res, a[i:]...)
+	}
+	if keepRight {
+		res = append(res, b[j:]...)
+	}
+	return res
+}
+
+// Union returns a new set with values in either set
+//	both sets should use the same comparator
+func (s *TreeSet) Union(other *TreeSet) *TreeSet {
+	return newTreeSetFromSorted(s.tree.Comparator, s.merge(other, true, true, true))
+}
+
+// Intersection returns a new set with values in both sets
+func (s *TreeSet) Intersection(other *TreeSet) *TreeSet {
+	return newTreeSetFromSorted(s.tree.Comparator, s.merge(other, false, true, false))
+}
+
+// Difference returns a new set with values in s but not in other
+func (s *TreeSet) Difference(other *TreeSet) *TreeSet {
+	return newTreeSetFromSorted(s.tree.Comparator, s.merge(other, true, false, false))
+}
+
+// SymmetricDifference returns a new set with values in only one of the sets
+func (s *TreeSet) SymmetricDifference(other *TreeSet) *TreeSet {
+	return newTreeSetFromSorted(s.tree.Comparator, s.merge(other, true, false, true))
+}
+
+// IsSubset returns true if every value of s is in other
+func (s *TreeSet) IsSubset(other *TreeSet) bool {
+	if s.Size() > other.Size() {
+		return false
+	}
+	for _, v := range s.Values() {
+		if !other.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset returns true if every value of other is in s
+func (s *TreeSet) IsSuperset(other *TreeSet) bool {
+	return other.IsSubset(s)
+}
+
+// Equal returns true if two sets have same values
+func (s *TreeSet) Equal(other *TreeSet) bool {
+	return s.Size() == other.Size() && s.IsSubset(other)
+}
+
+// Iterator returns iterator over a snapshot of sorted values
+func (s *TreeSet) Iterator() Iterator {
+	return basic.NewSliceIterator(s.Values())
+}
+
+// Each visits values of the set in ascending order until f returns false
+func (s *TreeSet) Each(f func(value interface{}) bool) {
+	s.tree.Ascend(func(key, _ interface{}) bool {
+		return f(key)
+	})
+}
@@ -0,0 +1,108 @@
+package set
+
+import (
+	"godev/basic"
+	"testing"
+)
+
+func checkTreeSet(s *TreeSet, expected ...int) bool {
+	values := s.Values()
+	if len(values) != len(expected) || s.Size() != len(expected) {
+		return false
+	}
+	for i := range values {
+		if values[i].(int) != expected[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNewTreeSet(t *testing.T) {
+	var _ basic.Container = (*TreeSet)(nil)
+
+	s := NewTreeSet(basic.IntComparator)
+	if !s.Empty() || s.Size() != 0 || len(s.Values()) != 0 || s.Iterator().HasNext() {
+		t.Fail()
+	}
+	if _, found := s.Min(); found {
+		t.Fail()
+	}
+	if _, found := s.Max(); found {
+		t.Fail()
+	}
+	s.Delete(1)
+
+	s = NewTreeSet(basic.IntComparator, 5, 3, 1, 3, 4)
+	if !checkTreeSet(s, 1, 3, 4, 5) || !s.Contains(1, 5) || s.Contains(2) {
+		t.Fail()
+	}
+	if min, _ := s.Min(); min.(int) != 1 {
+		t.Fail()
+	}
+	if max, _ := s.Max(); max.(int) != 5 {
+		t.Fail()
+	}
+
+	s.Add(2, 0)
+	s.Delete(3, 10)
+	if !checkTreeSet(s, 0, 1, 2, 4, 5) {
+		t.Fail()
+	}
+
+	i := 0
+	expected := []int{0, 1, 2, 4, 5}
+	it := s.Iterator()
+	for it.HasNext() {
+		if it.Next().(int) != expected[i] {
+			t.Fail()
+		}
+		i++
+	}
+	if i != len(expected) {
+		t.Fail()
+	}
+
+	s.Clear()
+	if !s.Empty() || s.Comparator() == nil {
+		t.Fail()
+	}
+}
+
+func TestTreeSet_Algebra(t *testing.T) {
+	a := NewTreeSet(basic.IntComparator, 1, 2, 3, 4)
+	b := NewTreeSet(basic.IntComparator, 3, 4, 5)
+
+	if !checkTreeSet(a.Union(b), 1, 2, 3, 4, 5) {
+		t.Fail()
+	}
+	if !checkTreeSet(a.Intersection(b), 3, 4) || !checkTreeSet(b.Intersection(a), 3, 4) {
+		t.Fail()
+	}
+	if !checkTreeSet(a.Difference(b), 1, 2) || !checkTreeSet(b.Difference(a), 5) {
+		t.Fail()
+	}
+	if !checkTreeSet(a.SymmetricDifference(b), 1, 2, 5) {
+		t.Fail()
+	}
+
+	empty := NewTreeSet(basic.IntComparator)
+	if !checkTreeSet(a.Union(empty), 1, 2, 3, 4) || !a.Intersection(empty).Empty() {
+		t.Fail()
+	}
+
+	if !NewTreeSet(basic.IntComparator, 1, 2).IsSubset(a) || b.IsSubset(a) || !a.IsSuperset(empty) {
+		t.Fail()
+	}
+	if a.Equal(b) || !a.Equal(NewTreeSet(basic.IntComparator, 4, 3, 2, 1)) {
+		t.Fail()
+	}
+}
+
+func TestTreeSet_String(t *testing.T) {
+	s := NewTreeSet(basic.StringComparator, "b", "c", "a")
+	values := s.Values()
+	if len(values) != 3 || values[0] != "a" || values[1] != "b" || values[2] != "c" {
+		t.Fail()
+	}
+}
@@ -85,8 +85,8 @@ func (n *node) rightRotate() *node {
 	x.rightTree = n
 	n.leftTree = b
 
-	x.updateHeight()
 	n.updateHeight()
+	x.updateHeight()
 
 	return x
 }
@@ -161,12 +161,12 @@ func (avlTree *AVLTree) set(n *node, key, value interface{}) *node {
 	}
 	// left right
 	if n.getBalance() > 1 && avlTree.Comparator(key, n.leftTree.key) == 1 {
-		n.leftTree.leftRotate()
+		n.leftTree = n.leftTree.leftRotate()
 		return n.rightRotate()
 	}
 	// right left
 	if n.getBalance() < -1 && avlTree.Comparator(key, n.rightTree.key) == -1 {
-		n.rightTree.rightRotate()
+		n.rightTree = n.rightTree.rightRotate()
 		return n.leftRotate()
 	}
 
@@ -205,7 +205,7 @@ func (avlTree *AVLTree) delete(n *node, key interface{}) *node {
 		} else {
 			// two children
 			leftMost := avlTree.leftMost(n.rightTree)
-			n.key = leftMost.key
+			n.key, n.value = leftMost.key, leftMost.value
 			n.rightTree = avlTree.delete(n.rightTree, leftMost.key)
 		}
 	}
@@ -224,12 +224,12 @@ func (avlTree *AVLTree) delete(n *node, key interface{}) *node {
 	}
 	// left right
 	if n.getBalance() > 1 && n.leftTree.getBalance() < 0 {
-		n.leftTree.leftRotate()
+		n.leftTree = n.leftTree.leftRotate()
 		return n.rightRotate()
 	}
 	// right left
 	if n.getBalance() < -1 && n.rightTree.getBalance() > 0 {
-		n.rightTree.rightRotate()
+		n.rightTree = n.rightTree.rightRotate()
 		return n.leftRotate()
 	}
 
@@ -280,6 +280,18 @@ func (avlTree *AVLTree) value(node *node, dataSlice *[]interface{}) {
 	avlTree.value(node.rightTree, dataSlice)
 }
 
+func (avlTree *AVLTree) each(node *node, f func(value interface{}) bool) bool {
+	if node == nil {
+		return true
+	}
+	return avlTree.each(node.leftTree, f) && f(node.value) && avlTree.each(node.rightTree, f)
+}
+
+// Each visits values in keys' order until f returns false
+func (avlTree *AVLTree) Each(f func(value interface{}) bool) {
+	avlTree.each(avlTree.Root, f)
+}
+
 // Keys returns keys of all nodes inside the tree
 func (avlTree *AVLTree) Keys() []interface{} {
 	if avlTree.Root == nil {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"godev/basic"
 	"godev/basic/datastructure/tree"
+	"math/rand"
 	"testing"
 )
 
@@ -84,3 +85,49 @@ func TestAVLTree_Delete(t *testing.T) {
 		t.Fail()
 	}
 }
+
+// checkHeight returns height of the sub-tree, or -1 if it is not a valid AVL tree
+func checkHeight(avlTree *AVLTree, n *node) int {
+	if n == nil {
+		return 0
+	}
+	l, r := checkHeight(avlTree, n.leftTree), checkHeight(avlTree, n.rightTree)
+	if l < 0 || r < 0 || l-r > 1 || r-l > 1 || n.height != maxInt(l, r)+1 {
+		return -1
+	}
+	if n.leftTree != nil && avlTree.Comparator(n.leftTree.key, n.key) >= 0 ||
+		n.rightTree != nil && avlTree.Comparator(n.rightTree.key, n.key) <= 0 {
+		return -1
+	}
+	return n.height
+}
+
+func TestAVLTree_Random(t *testing.T) {
+	avlTree := NewAVLTree(basic.IntComparator)
+	a := rand.Perm(1000)
+	for _, v := range a {
+		avlTree.Set(v, v*2)
+	}
+	if checkHeight(avlTree, avlTree.Root) < 0 || avlTree.Size() != len(a) {
+		t.Fatal("invalid tree after Set")
+	}
+	for _, v := range a[:500] {
+		avlTree.Delete(v)
+	}
+	if checkHeight(avlTree, avlTree.Root) < 0 || avlTree.Size() != 500 {
+		t.Fatal("invalid tree after Delete")
+	}
+	keys := avlTree.Keys()
+	i := 0
+	avlTree.Each(func(value interface{}) bool {
+		k := keys[i]
+		if value.(int) != k.(int)*2 {
+			t.Fatal(k, value)
+		}
+		i++
+		return true
+	})
+	if i != 500 {
+		t.Fail()
+	}
+}
@@ -128,6 +128,19 @@ func (bst *BSTree) Values() []interface{} {
 	return dataSlice
 }
 
+
+func (bst *BSTree) each(node *Node, f func(value interface{}) bool) bool {
+	if node == nil {
+		return true
+	}
+	return bst.each(node.left, f) && f(node.data) && bst.each(node.right, f)
+}
+
+// Each visits values stored inside the tree in increasing order until f returns false
+func (bst *BSTree) Each(f func(value interface{}) bool) {
+	bst.each(bst.Root, f)
+}
+
 // Clear clears the tree by setting root to nil
 func (bst *BSTree) Clear() {
 	bst.Root = nil
@@ -53,6 +53,69 @@ func NewBTree(M int, comparator basic.Comparator) *BTree {
 	}
 }
 
+// BulkLoad creates a BTree from items sorted by key ascending in O(n), much faster than inserting one by one
+//	items with equal keys are allowed, the last one wins (same as Insert), it panics if items are not sorted
+func BulkLoad(M int, comparator basic.Comparator, items []*Item) *BTree {
+	bTree := NewBTree(M, comparator)
+	// remove duplicated keys
+	sorted := make([]*Item, 0, len(items))
+	for _, item := range items {
+		if n := len(sorted); n > 0 {
+			switch comparator(sorted[n-1].Key, item.Key) {
+			case 0:
+				sorted[n-1] = item
+				continue
+			case 1:
+				panic("items should be sorted by key ascending")
+			}
+		}
+		sorted = append(sorted, item)
+	}
+	if len(sorted) == 0 {
+		return bTree
+	}
+	// the lowest height which can hold all items, a tree of height h holds at most M^(h+1) - 1 items
+	height, capacity := 0, M-1
+	for capacity < len(sorted) {
+		height++
+		capacity = capacity*M + M - 1
+	}
+	bTree.Root = bTree.build(sorted, height, capacity)
+	bTree.ItemsNum = len(sorted)
+	return bTree
+}
+
+// build builds a sub-tree of height from items, capacity is the maximum items number of such sub-tree
+//	children are as few as possible and items are spread evenly over them,
+//	which keeps every node above the minimum size
+func (bTree *BTree) build(items []*Item, height, capacity int) *Node {
+	node := &Node{}
+	if height == 0 {
+		node.Items = append([]*Item{}, items...)
+		return node
+	}
+	childCapacity := (capacity+1)/bTree.M - 1
+	childNum := (len(items) + 1 + childCapacity) / (childCapacity + 1)
+	// items of children
+	remain := len(items) - (childNum - 1)
+	start := 0
+	for i := 0; i < childNum; i++ {
+		size := remain / childNum
+		if i < remain%childNum {
+			size++
+		}
+		child := bTree.build(items[start:start+size], height-1, childCapacity)
+		child.Parent = node
+		node.Children = append(node.Children, child)
+		start += size
+		if i < childNum-1 {
+			node.Items = append(node.Items, items[start])
+			start++
+		}
+	}
+	return node
+}
+
 // Empty returns true if no item inside BTree
 func (bTree *BTree) Empty() bool {
 	return bTree.ItemsNum == 0
@@ -266,33 +329,21 @@ func (bTree *BTree) lookupRec(startNode *Node, key interface{}) (node *Node, ind
 	}
 }
 
-// Values returns values of all items inside BTree
+// Values returns values of all items inside BTree in key ascending order
 func (bTree *BTree) Values() []interface{} {
-	values := make([]interface{}, bTree.Size())
-	var items []*Item
-	bTree.items(bTree.Root, &items)
-	for idx, i := range items {
-		values[idx] = i.Value
-	}
+	values := make([]interface{}, 0, bTree.Size())
+	bTree.Ascend(func(item *Item) bool {
+		values = append(values, item.Value)
+		return true
+	})
 	return values
 }
 
-func (bTree *BTree) items(node *Node, items *[]*Item) {
-	if len(node.Children) != 0 {
-		mid := (len(node.Children)-1)/2 + 1
-		// left sub-tree
-		for _, c := range node.Children[:mid] {
-			bTree.items(c, items)
-		}
-		// mid
-		*items = append(*items, node.Items...)
-		// right sub-tree
-		for _, c := range node.Children[mid:] {
-			bTree.items(c, items)
-		}
-	} else {
-		*items = append(*items, node.Items...)
-	}
+// Each visits values in key order until f returns false
+func (bTree *BTree) Each(f func(value interface{}) bool) {
+	bTree.Ascend(func(item *Item) bool {
+		return f(item.Value)
+	})
 }
 
 // Clear clears items inside BTree by creating a new BTree with the same M and Comparator
@@ -305,34 +356,29 @@ func (bTree *BTree) Delete(key interface{}) {
 	node, index, found := bTree.lookupRec(bTree.Root, key)
 	if found {
 		bTree.delete(node, index)
-		bTree.ItemsNum--
 	}
 }
 
-func (bTree *BTree) delete(node *Node, index int) {
+func (bTree *BTree) delete(node *Node, index int) *Item {
 	// two conditions:
 	//	1. delete item from leaf node
 	//	2. delete item from internal node
 	//	one more step after deletion: re-balancing
-
-	// delete item from leaf node
-	if bTree.isLeaf(node) {
-		deletedKey := node.Items[index].Key // use deletedItem's key as reference for re-balancing
-		bTree.deleteItemAtIdx(node, index)  // delete item
-		bTree.rebalance(node, deletedKey)   // re-balance
-		// root?
-	} else {
+	deleted := node.Items[index]
+	if !bTree.isLeaf(node) {
 		// delete item from internal node
-		// 1. choose a new separator (either the largest element in the left subtree or the smallest element in the right subtree),
+		// choose a new separator (the largest element in the left subtree),
 		// remove it from the leaf node it is in, and replace the element to be deleted with the new separator.
 		leftLargestNode := bTree.rightMost(node.Children[index]) // the largest node in the left subtree
 		leftLargestItemIndex := len(leftLargestNode.Items) - 1
-		leftLargestItem := leftLargestNode.Items[leftLargestItemIndex] // put item in internal node as new separator
-		node.Items[index] = leftLargestItem
-		deletedKey := leftLargestItem.Key                            // use deletedItem's key as reference for re-balancing
-		bTree.deleteItemAtIdx(leftLargestNode, leftLargestItemIndex) // delete that item from leftLargestNode
-		bTree.rebalance(node, deletedKey)                            // re-balance
+		node.Items[index] = leftLargestNode.Items[leftLargestItemIndex] // put item in internal node as new separator
+		node, index = leftLargestNode, leftLargestItemIndex
 	}
+	// delete item from leaf node
+	bTree.deleteItemAtIdx(node, index)
+	bTree.rebalance(node)
+	bTree.ItemsNum--
+	return deleted
 }
 
 func (bTree *BTree) deleteItemAtIdx(node *Node, index int) {
@@ -341,20 +387,38 @@ func (bTree *BTree) deleteItemAtIdx(node *Node, index int) {
 	node.Items = node.Items[:len(node.Items)-1]
 }
 
+func (bTree *BTree) leftMost(node *Node) *Node {
+	currentNode := node
+	for !bTree.isLeaf(currentNode) {
+		currentNode = currentNode.Children[0]
+	}
+	return currentNode
+}
+
 func (bTree *BTree) rightMost(node *Node) *Node {
-	// recursively
 	currentNode := node
-	for {
-		// rightMost should be leaf
-		if bTree.isLeaf(currentNode) {
-			return currentNode
-		}
+	// rightMost should be leaf
+	for !bTree.isLeaf(currentNode) {
 		currentNode = currentNode.Children[len(currentNode.Children)-1]
 	}
+	return currentNode
 }
 
-func (bTree *BTree) rebalance(node *Node, deletedKey interface{}) {
+func (bTree *BTree) rebalance(node *Node) {
 	// re-balancing starts from a leaf and proceeds toward the root until the tree is balanced.
+	if node == bTree.Root {
+		// if the root has no elements, then free it and make its only child the new root (tree becomes shallower)
+		if len(node.Items) == 0 {
+			if bTree.isLeaf(node) {
+				bTree.Root = nil
+			} else {
+				bTree.Root = node.Children[0]
+				bTree.Root.Parent = nil
+			}
+		}
+		return
+	}
+
 	// if deleting an element from a node has brought it under the minimum size ([M - 1] / 2),
 	// then some elements must be redistributed to bring all nodes up to the minimum.
 	minItemNum := (bTree.M - 1) / 2
@@ -362,16 +426,24 @@ func (bTree *BTree) rebalance(node *Node, deletedKey interface{}) {
 		return
 	}
 
+	parent := node.Parent
+	idx := childIndex(parent, node)
+	var ls, rs *Node
+	if idx > 0 {
+		ls = parent.Children[idx-1]
+	}
+	if idx+1 < len(parent.Children) {
+		rs = parent.Children[idx+1]
+	}
+
 	// if the deficient node's right sibling exists and has more than the minimum number of elements,
 	// then rotate left
-	rs, rsIdx := bTree.rightSibling(node, deletedKey)
 	if rs != nil && len(rs.Items) > minItemNum {
 		// append parent's separator item into node items
-		separatorIdx := rsIdx - 1
-		node.Items = append(node.Items, node.Parent.Items[separatorIdx])
-		node.Parent.Items[separatorIdx] = rs.Items[0] // leftmost item as new separator, move it to parent
-		bTree.deleteItemAtIdx(rs, 0)                  // delete it from right sibling
-		// move leftmost children of right sibling to parent if it is internal node (leaf node has no children)
+		node.Items = append(node.Items, parent.Items[idx])
+		parent.Items[idx] = rs.Items[0] // leftmost item as new separator, move it to parent
+		bTree.deleteItemAtIdx(rs, 0)    // delete it from right sibling
+		// move leftmost children of right sibling to node if it is internal node (leaf node has no children)
 		if !bTree.isLeaf(rs) {
 			rsLeftMostChild := rs.Children[0]
 			rsLeftMostChild.Parent = node
@@ -383,15 +455,13 @@ func (bTree *BTree) rebalance(node *Node, deletedKey interface{}) {
 
 	// otherwise, if the deficient node's left sibling exists and has more than the minimum number of elements,
 	// then rotate right
-	ls, lsIdx := bTree.leftSibling(node, deletedKey)
 	if ls != nil && len(ls.Items) > minItemNum {
 		// prepend parent's separator item into node items
-		separatorIdx := lsIdx
-		node.Items = append([]*Item{node.Parent.Items[separatorIdx]}, node.Items...)
-		node.Parent.Items[separatorIdx] = ls.Items[len(ls.Items)-1] // rightmost item as new separator, move it to parent
-		bTree.deleteItemAtIdx(ls, len(ls.Items)-1)                  // delete it from left sibling
-		// move rightmost children of left sibling to parent if it is internal node (leaf node has no children)
-		if bTree.isLeaf(ls) {
+		node.Items = append([]*Item{parent.Items[idx-1]}, node.Items...)
+		parent.Items[idx-1] = ls.Items[len(ls.Items)-1] // rightmost item as new separator, move it to parent
+		bTree.deleteItemAtIdx(ls, len(ls.Items)-1)      // delete it from left sibling
+		// move rightmost children of left sibling to node if it is internal node (leaf node has no children)
+		if !bTree.isLeaf(ls) {
 			lsRightMostChild := ls.Children[len(ls.Children)-1]
 			lsRightMostChild.Parent = node
 			// prepend
@@ -404,84 +474,36 @@ func (bTree *BTree) rebalance(node *Node, deletedKey interface{}) {
 	// otherwise, if both immediate siblings have only the minimum number of elements,
 	// then merge with a sibling sandwiching their separator taken off from their parent
 	if rs != nil {
-		// merge with right sibling
-
-		separatorIdx := rsIdx - 1
-
-		// deal with item
-		// append parent's separator item into node items
-		node.Items = append(node.Items, node.Parent.Items[separatorIdx])
-		// append right sibling items into node items
-		node.Items = append(node.Items, rs.Items...)
-		// delete separator item from parent
-		deletedKey = node.Parent.Items[separatorIdx].Key
-		bTree.deleteItemAtIdx(node.Parent, separatorIdx)
-
-		// deal with child
-		// append right sibling's children into node's children
-		node.Children = append(node.Children, node.Parent.Children[rsIdx].Children...)
-		// set their parent to node
-		setParent(node.Parent.Children[rsIdx].Children, node)
-		// delete right sibling from parent
-		bTree.deleteChildAtIdx(node.Parent, rsIdx)
-	} else if ls != nil {
-		// merge with left sibling
-
-		separatorIdx := lsIdx
-
-		// deal with item
-		// append parent's separator item into left sibling items, introduce a tmp items variable to avoid change on ls.Items directly
-		tmpItems := append([]*Item{}, ls.Items...) // ensure result is []*Item
-		tmpItems = append(tmpItems, node.Parent.Items[separatorIdx])
-		// prepend left sibling items into node items
-		node.Items = append(tmpItems, node.Items...)
-		// delete separator item from parent
-		deletedKey = node.Parent.Items[lsIdx].Key
-		bTree.deleteItemAtIdx(node.Parent, lsIdx)
-
-		// deal with child
-		// prepend left sibling's children into node's children
-		temChildren := append([]*Node{}, node.Parent.Children[lsIdx].Children...) // ensure result is []*Node
-		node.Children = append(temChildren, node.Children...)
-		// set their parent to node
-		setParent(node.Parent.Children[lsIdx].Children, node)
-		// delete left sibling from parent
-		bTree.deleteChildAtIdx(node.Parent, lsIdx)
-	}
-
-	// if the parent is the root and now has no elements,
-	// then free it and make the merged node the new root (tree becomes shallower)
-	if node.Parent == bTree.Root && len(bTree.Root.Items) == 0 {
-		bTree.Root = node
-		node.Parent = nil
-		return
+		bTree.merge(node, rs, idx)
+	} else {
+		bTree.merge(ls, node, idx-1)
 	}
 
-	// otherwise, if the parent has fewer than the required number of elements,
-	// then re-balance the parent
-	bTree.rebalance(node.Parent, deletedKey)
+	// if the parent has fewer than the required number of elements, then re-balance the parent
+	bTree.rebalance(parent)
 }
 
-func (bTree *BTree) rightSibling(node *Node, key interface{}) (sibling *Node, siblingIndex int) {
-	if node.Parent != nil {
-		index, _ := bTree.findPositionByKey(node, key)
-		index++ // right sibling index
-		if index < len(node.Parent.Children) {
-			return node.Parent.Children[index], index
-		}
-	}
-	return nil, -1
+// merge merges right node into left node with separator at separatorIdx of their parent
+func (bTree *BTree) merge(left, right *Node, separatorIdx int) {
+	parent := left.Parent
+	// deal with item
+	left.Items = append(left.Items, parent.Items[separatorIdx])
+	left.Items = append(left.Items, right.Items...)
+	bTree.deleteItemAtIdx(parent, separatorIdx)
+	// deal with child
+	left.Children = append(left.Children, right.Children...)
+	setParent(right.Children, left)
+	bTree.deleteChildAtIdx(parent, separatorIdx+1)
 }
 
-func (bTree *BTree) leftSibling(node *Node, key interface{}) (sibling *Node, siblingIndex int) {
-	if node.Parent != nil {
-		index, _ := bTree.findPositionByKey(node, key)
-		index-- // right sibling index
-		if index >= 0 && index < len(node.Parent.Children) {
-			return node.Parent.Children[index], index
+// childIndex returns index of child inside parent's children
+func childIndex(parent, child *Node) int {
+	for i, c := range parent.Children {
+		if c == child {
+			return i
 		}
 	}
-	return nil, -1
+	return -1
 }
 
 func (bTree *BTree) deleteChildAtIdx(node *Node, index int) {
@@ -493,3 +515,37 @@ func (bTree *BTree) deleteChildAtIdx(node *Node, index int) {
 	node.Children[len(node.Children)-1] = nil
 	node.Children = node.Children[:len(node.Children)-1]
 }
+
+// Min returns the item with minimum key, nil if tree is empty
+func (bTree *BTree) Min() *Item {
+	if bTree.Empty() {
+		return nil
+	}
+	return bTree.leftMost(bTree.Root).Items[0]
+}
+
+// Max returns the item with maximum key, nil if tree is empty
+func (bTree *BTree) Max() *Item {
+	if bTree.Empty() {
+		return nil
+	}
+	node := bTree.rightMost(bTree.Root)
+	return node.Items[len(node.Items)-1]
+}
+
+// DeleteMin deletes and returns the item with minimum key, nil if tree is empty
+func (bTree *BTree) DeleteMin() *Item {
+	if bTree.Empty() {
+		return nil
+	}
+	return bTree.delete(bTree.leftMost(bTree.Root), 0)
+}
+
+// DeleteMax deletes and returns the item with maximum key, nil if tree is empty
+func (bTree *BTree) DeleteMax() *Item {
+	if bTree.Empty() {
+		return nil
+	}
+	node := bTree.rightMost(bTree.Root)
+	return bTree.delete(node, len(node.Items)-1)
+}
@@ -5,6 +5,8 @@ import (
 	"godev/basic"
 	"godev/basic/datastructure/tree"
 	"godev/utils"
+	"math/rand"
+	"sort"
 	"strconv"
 	"testing"
 )
@@ -64,6 +66,249 @@ func TestBTree(t *testing.T) {
 	}
 }
 
+// checkTree checks B-tree invariants: sorted keys, node sizes, parent links and leaves depth
+func checkTree(t *testing.T, bTree *BTree) {
+	if bTree.Root == nil {
+		if bTree.Size() != 0 {
+			t.Fatal("nil root with items")
+		}
+		return
+	}
+	if bTree.Root.Parent != nil {
+		t.Fatal("root has parent")
+	}
+	minItemNum := (bTree.M - 1) / 2
+	leafDepth := -1
+	count := 0
+	var check func(node *Node, depth int)
+	check = func(node *Node, depth int) {
+		count += len(node.Items)
+		if len(node.Items) > bTree.M-1 || (node != bTree.Root && len(node.Items) < minItemNum) || len(node.Items) == 0 {
+			t.Fatal("node size", len(node.Items))
+		}
+		if len(node.Children) == 0 {
+			if leafDepth == -1 {
+				leafDepth = depth
+			} else if leafDepth != depth {
+				t.Fatal("leaves depth")
+			}
+			return
+		}
+		if len(node.Children) != len(node.Items)+1 {
+			t.Fatal("children number")
+		}
+		for _, c := range node.Children {
+			if c.Parent != node {
+				t.Fatal("parent link")
+			}
+			check(c, depth+1)
+		}
+	}
+	check(bTree.Root, 0)
+	if count != bTree.Size() {
+		t.Fatal("size", count, bTree.Size())
+	}
+	var prev *Item
+	bTree.Ascend(func(item *Item) bool {
+		if prev != nil && bTree.Comparator(prev.Key, item.Key) >= 0 {
+			t.Fatal("order")
+		}
+		prev = item
+		return true
+	})
+}
+
+func TestBTree_Random(t *testing.T) {
+	for _, m := range []int{3, 4, 5, 8, 33} {
+		r := rand.New(rand.NewSource(int64(m)))
+		bTree := NewBTree(m, basic.IntComparator)
+		ref := map[int]bool{}
+		for i := 0; i < 5000; i++ {
+			k := r.Intn(1000)
+			switch r.Intn(5) {
+			case 0, 1:
+				bTree.Delete(k)
+				delete(ref, k)
+			case 2:
+				if min := bTree.DeleteMin(); min != nil {
+					delete(ref, min.Key.(int))
+				}
+			default:
+				bTree.Insert(&Item{Key: k, Value: k})
+				ref[k] = true
+			}
+			if i%100 == 0 {
+				checkTree(t, bTree)
+			}
+		}
+		checkTree(t, bTree)
+		var keys []int
+		for k := range ref {
+			keys = append(keys, k)
+		}
+		sort.Ints(keys)
+		values := bTree.Values()
+		if len(values) != len(keys) {
+			t.Fatal(m, len(values), len(keys))
+		}
+		for i := range keys {
+			if values[i].(int) != keys[i] {
+				t.Fatal(m, i)
+			}
+		}
+		for !bTree.Empty() {
+			bTree.DeleteMax()
+		}
+		checkTree(t, bTree)
+	}
+}
+
+func TestBTree_MinMax(t *testing.T) {
+	bTree := NewBTree(4, basic.IntComparator)
+	if bTree.Min() != nil || bTree.Max() != nil || bTree.DeleteMin() != nil || bTree.DeleteMax() != nil || len(bTree.Values()) != 0 {
+		t.Fail()
+	}
+	for _, v := range []int{5, 3, 9, 1, 7} {
+		bTree.Insert(&Item{Key: v, Value: v})
+	}
+	if bTree.Min().Key != 1 || bTree.Max().Key != 9 {
+		t.Fail()
+	}
+	if bTree.DeleteMin().Key != 1 || bTree.DeleteMax().Key != 9 || bTree.Min().Key != 3 || bTree.Max().Key != 7 || bTree.Size() != 3 {
+		t.Fail()
+	}
+}
+
+func TestBTree_Cursor(t *testing.T) {
+	bTree := NewBTree(3, basic.IntComparator)
+	c := bTree.Cursor()
+	if c.First() || c.Last() || c.SeekGE(1) || c.SeekLE(1) || c.Valid() || c.Item() != nil || c.Next() || c.Prev() {
+		t.Fail()
+	}
+
+	// even keys 0, 2, ..., 198
+	for i := 0; i < 100; i++ {
+		bTree.Insert(&Item{Key: i * 2, Value: i})
+	}
+
+	// full scan both directions
+	n := 0
+	for ok := c.First(); ok; ok = c.Next() {
+		if c.Item().Key != n*2 {
+			t.Fatal(n)
+		}
+		n++
+	}
+	if n != 100 || c.Valid() {
+		t.Fail()
+	}
+	for ok := c.Last(); ok; ok = c.Prev() {
+		n--
+		if c.Item().Key != n*2 {
+			t.Fatal(n)
+		}
+	}
+	if n != 0 {
+		t.Fail()
+	}
+
+	for k := -1; k <= 200; k++ {
+		ge := (k + 1) / 2 * 2
+		if k < 0 {
+			ge = 0
+		}
+		if found := c.SeekGE(k); found != (ge <= 198) || (found && c.Item().Key != ge) {
+			t.Fatal("SeekGE", k)
+		}
+		le := k / 2 * 2
+		if le > 198 {
+			le = 198
+		}
+		if found := c.SeekLE(k); found != (k >= 0) || (found && c.Item().Key != le) {
+			t.Fatal("SeekLE", k)
+		}
+	}
+}
+
+func TestBTree_Range(t *testing.T) {
+	bTree := NewBTree(5, basic.IntComparator)
+	for i := 0; i < 100; i++ {
+		bTree.Insert(&Item{Key: i, Value: i})
+	}
+
+	var got []int
+	collect := func(item *Item) bool {
+		got = append(got, item.Key.(int))
+		return true
+	}
+	bTree.AscendRange(10, 20, collect)
+	if len(got) != 10 || got[0] != 10 || got[9] != 19 {
+		t.Fail()
+	}
+	got = nil
+	bTree.DescendRange(20, 10, collect)
+	if len(got) != 10 || got[0] != 20 || got[9] != 11 {
+		t.Fail()
+	}
+	got = nil
+	bTree.AscendRange(95, nil, collect)
+	if len(got) != 5 || got[4] != 99 {
+		t.Fail()
+	}
+	got = nil
+	bTree.DescendRange(nil, 95, collect)
+	if len(got) != 4 || got[0] != 99 {
+		t.Fail()
+	}
+	got = nil
+	bTree.AscendRange(50, 40, collect)
+	if len(got) != 0 {
+		t.Fail()
+	}
+
+	// stop early
+	cnt := 0
+	bTree.Descend(func(item *Item) bool {
+		cnt++
+		return cnt < 3
+	})
+	if cnt != 3 {
+		t.Fail()
+	}
+}
+
+func TestBulkLoad(t *testing.T) {
+	for _, m := range []int{3, 4, 7, 64} {
+		for n := 0; n < 300; n += 7 {
+			items := make([]*Item, n)
+			for i := range items {
+				items[i] = &Item{Key: i, Value: i}
+			}
+			bTree := BulkLoad(m, basic.IntComparator, items)
+			checkTree(t, bTree)
+			if bTree.Size() != n {
+				t.Fatal(m, n)
+			}
+			// still works as a normal tree
+			bTree.Insert(&Item{Key: -1, Value: -1})
+			bTree.Delete(n / 2)
+			checkTree(t, bTree)
+		}
+	}
+
+	bTree := BulkLoad(3, basic.IntComparator, []*Item{{Key: 1, Value: "a"}, {Key: 1, Value: "b"}, {Key: 2, Value: "c"}})
+	if v, _ := bTree.Lookup(1); bTree.Size() != 2 || v != "b" {
+		t.Fail()
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fail()
+		}
+	}()
+	BulkLoad(3, basic.IntComparator, []*Item{{Key: 2}, {Key: 1}})
+}
+
 // BenchmarkBTree_Insert-8   	 1000000	      1940 ns/op
 func BenchmarkBTree_Insert(b *testing.B) {
 	bTree := NewBTree(10, basic.IntComparator)
@@ -80,3 +325,14 @@ func BenchmarkBTree_Insert(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkBulkLoad(b *testing.B) {
+	items := make([]*Item, 100000)
+	for i := range items {
+		items[i] = &Item{Key: i, Value: i}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BulkLoad(64, basic.IntComparator, items)
+	}
+}
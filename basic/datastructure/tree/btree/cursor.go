@@ -0,0 +1,197 @@
+package btree
+
+// Cursor points to an item inside BTree and moves over items in key order
+//	a cursor is invalidated by Insert / Delete on the tree, re-position it by First / Last / Seek after modifications
+type Cursor struct {
+	tree  *BTree
+	node  *Node
+	index int
+}
+
+// Cursor returns a new cursor of the tree, it must be positioned by First / Last / Seek before use
+func (bTree *BTree) Cursor() *Cursor {
+	return &Cursor{
+		tree:  bTree,
+		node:  nil,
+		index: -1,
+	}
+}
+
+// Valid returns true if cursor points to an item
+func (c *Cursor) Valid() bool {
+	return c.node != nil
+}
+
+// Item returns the item cursor points to, nil if cursor is not valid
+func (c *Cursor) Item() *Item {
+	if c.node == nil {
+		return nil
+	}
+	return c.node.Items[c.index]
+}
+
+func (c *Cursor) invalidate() bool {
+	c.node, c.index = nil, -1
+	return false
+}
+
+// First moves cursor to the item with minimum key, returns false if tree is empty
+func (c *Cursor) First() bool {
+	if c.tree.Empty() {
+		return c.invalidate()
+	}
+	c.node, c.index = c.tree.leftMost(c.tree.Root), 0
+	return true
+}
+
+// Last moves cursor to the item with maximum key, returns false if tree is empty
+func (c *Cursor) Last() bool {
+	if c.tree.Empty() {
+		return c.invalidate()
+	}
+	c.node = c.tree.rightMost(c.tree.Root)
+	c.index = len(c.node.Items) - 1
+	return true
+}
+
+// SeekGE moves cursor to the first item whose key >= input key, returns false if no such item
+func (c *Cursor) SeekGE(key interface{}) bool {
+	c.invalidate()
+	if c.tree.Empty() {
+		return false
+	}
+	node := c.tree.Root
+	for {
+		index, found := c.tree.findPositionByKey(node, key)
+		// the deepest node where we go left of an item holds the successor
+		if found || index < len(node.Items) {
+			c.node, c.index = node, index
+		}
+		if found || c.tree.isLeaf(node) {
+			return c.Valid()
+		}
+		node = node.Children[index]
+	}
+}
+
+// SeekLE moves cursor to the last item whose key <= input key, returns false if no such item
+func (c *Cursor) SeekLE(key interface{}) bool {
+	c.invalidate()
+	if c.tree.Empty() {
+		return false
+	}
+	node := c.tree.Root
+	for {
+		index, found := c.tree.findPositionByKey(node, key)
+		if found {
+			c.node, c.index = node, index
+			return true
+		}
+		// the deepest node where we go right of an item holds the predecessor
+		if index > 0 {
+			c.node, c.index = node, index-1
+		}
+		if c.tree.isLeaf(node) {
+			return c.Valid()
+		}
+		node = node.Children[index]
+	}
+}
+
+// Next moves cursor to the next item, returns false if there is no next item
+func (c *Cursor) Next() bool {
+	if c.node == nil {
+		return false
+	}
+	if !c.tree.isLeaf(c.node) {
+		// the minimum item of right sub-tree
+		c.node, c.index = c.tree.leftMost(c.node.Children[c.index+1]), 0
+		return true
+	}
+	c.index++
+	// climb up until the node has items on the right
+	for c.index >= len(c.node.Items) {
+		parent := c.node.Parent
+		if parent == nil {
+			return c.invalidate()
+		}
+		c.node, c.index = parent, childIndex(parent, c.node)
+	}
+	return true
+}
+
+// Prev moves cursor to the previous item, returns false if there is no previous item
+func (c *Cursor) Prev() bool {
+	if c.node == nil {
+		return false
+	}
+	if !c.tree.isLeaf(c.node) {
+		// the maximum item of left sub-tree
+		c.node = c.tree.rightMost(c.node.Children[c.index])
+		c.index = len(c.node.Items) - 1
+		return true
+	}
+	c.index--
+	// climb up until the node has items on the left
+	for c.index < 0 {
+		parent := c.node.Parent
+		if parent == nil {
+			return c.invalidate()
+		}
+		c.node, c.index = parent, childIndex(parent, c.node)-1
+	}
+	return true
+}
+
+// ItemIterator is called on items during Ascend / Descend, return false to stop iteration
+type ItemIterator func(item *Item) bool
+
+// Ascend calls iterator on every item in key ascending order
+func (bTree *BTree) Ascend(iterator ItemIterator) {
+	bTree.AscendRange(nil, nil, iterator)
+}
+
+// Descend calls iterator on every item in key descending order
+func (bTree *BTree) Descend(iterator ItemIterator) {
+	bTree.DescendRange(nil, nil, iterator)
+}
+
+// AscendRange calls iterator on items with key in range [greaterOrEqual, lessThan) in ascending order
+//	nil bound means unbounded
+func (bTree *BTree) AscendRange(greaterOrEqual, lessThan interface{}, iterator ItemIterator) {
+	c := bTree.Cursor()
+	if greaterOrEqual == nil {
+		c.First()
+	} else {
+		c.SeekGE(greaterOrEqual)
+	}
+	for ; c.Valid(); c.Next() {
+		item := c.Item()
+		if lessThan != nil && bTree.Comparator(item.Key, lessThan) >= 0 {
+			return
+		}
+		if !iterator(item) {
+			return
+		}
+	}
+}
+
+// DescendRange calls iterator on items with key in range (greaterThan, lessOrEqual] in descending order
+//	nil bound means unbounded
+func (bTree *BTree) DescendRange(lessOrEqual, greaterThan interface{}, iterator ItemIterator) {
+	c := bTree.Cursor()
+	if lessOrEqual == nil {
+		c.Last()
+	} else {
+		c.SeekLE(lessOrEqual)
+	}
+	for ; c.Valid(); c.Prev() {
+		item := c.Item()
+		if greaterThan != nil && bTree.Comparator(item.Key, greaterThan) <= 0 {
+			return
+		}
+		if !iterator(item) {
+			return
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package kdtree
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// references:
+// https://en.wikipedia.org/wiki/K-d_tree
+// https://en.wikipedia.org/wiki/Nearest_neighbor_search
+
+// Point stands for a point in k-dimensional space
+type Point []float64
+
+// Distance returns euclidean distance between two points
+func (p Point) Distance(q Point) float64 {
+	return math.Sqrt(p.distanceSquare(q))
+}
+
+func (p Point) distanceSquare(q Point) float64 {
+	d := 0.0
+	for i := range p {
+		diff := p[i] - q[i]
+		d += diff * diff
+	}
+	return d
+}
+
+// Item struct stored inside the tree
+type Item struct {
+	Point Point
+	Value interface{}
+}
+
+type node struct {
+	item                Item
+	axis                int
+	leftTree, rightTree *node
+}
+
+// KDTree struct
+type KDTree struct {
+	root    *node
+	dim     int
+	itemNum int
+}
+
+// NewKDTree creates a new empty k-d tree with points of dimension dim
+func NewKDTree(dim int) *KDTree {
+	if dim < 1 {
+		panic("dimension should be at least 1")
+	}
+	return &KDTree{
+		root:    nil,
+		dim:     dim,
+		itemNum: 0,
+	}
+}
+
+// Build creates a balanced k-d tree from items by splitting on median
+func Build(dim int, items []Item) *KDTree {
+	t := NewKDTree(dim)
+	for i := range items {
+		t.checkDim(items[i].Point)
+	}
+	cp := make([]Item, len(items))
+	copy(cp, items)
+	t.root = t.build(cp, 0)
+	t.itemNum = len(cp)
+	return t
+}
+
+func (t *KDTree) build(items []Item, depth int) *node {
+	if len(items) == 0 {
+		return nil
+	}
+	axis := depth % t.dim
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Point[axis] < items[j].Point[axis]
+	})
+	mid := len(items) / 2
+	// keep equal coordinates on the right side, same as Insert
+	for mid > 0 && items[mid-1].Point[axis] == items[mid].Point[axis] {
+		mid--
+	}
+	return &node{
+		item:      items[mid],
+		axis:      axis,
+		leftTree:  t.build(items[:mid], depth+1),
+		rightTree: t.build(items[mid+1:], depth+1),
+	}
+}
+
+func (t *KDTree) checkDim(p Point) {
+	if len(p) != t.dim {
+		panic("point dimension mismatch")
+	}
+}
+
+// Dim returns dimension of the tree
+func (t *KDTree) Dim() int {
+	return t.dim
+}
+
+// Insert inserts a point with its value
+func (t *KDTree) Insert(p Point, value interface{}) {
+	t.checkDim(p)
+	item := Item{Point: p, Value: value}
+	t.itemNum++
+	if t.root == nil {
+		t.root = &node{item: item, axis: 0}
+		return
+	}
+	n := t.root
+	for {
+		if p[n.axis] < n.item.Point[n.axis] {
+			if n.leftTree == nil {
+				n.leftTree = &node{item: item, axis: (n.axis + 1) % t.dim}
+				return
+			}
+			n = n.leftTree
+		} else {
+			if n.rightTree == nil {
+				n.rightTree = &node{item: item, axis: (n.axis + 1) % t.dim}
+				return
+			}
+			n = n.rightTree
+		}
+	}
+}
+
+// Nearest returns the nearest item of input point
+func (t *KDTree) Nearest(p Point) (item Item, found bool) {
+	items := t.KNearest(p, 1)
+	if len(items) == 0 {
+		return Item{}, false
+	}
+	return items[0], true
+}
+
+// candidate max heap, farthest on top
+type candidates []candidate
+
+type candidate struct {
+	item Item
+	dist float64
+}
+
+func (c candidates) Len() int            { return len(c) }
+func (c candidates) Less(i, j int) bool  { return c[i].dist > c[j].dist }
+func (c candidates) Swap(i, j int)       { c[i], c[j] = c[j], c[i] }
+func (c *candidates) Push(x interface{}) { *c = append(*c, x.(candidate)) }
+func (c *candidates) Pop() interface{} {
+	old := *c
+	x := old[len(old)-1]
+	*c = old[:len(old)-1]
+	return x
+}
+
+// KNearest returns at most k nearest items of input point, sorted by distance ascending
+func (t *KDTree) KNearest(p Point, k int) []Item {
+	t.checkDim(p)
+	if k <= 0 || t.root == nil {
+		return nil
+	}
+	h := make(candidates, 0, k)
+	t.kNearest(t.root, p, k, &h)
+
+	// pop farthest first
+	items := make([]Item, h.Len())
+	for i := len(items) - 1; i >= 0; i-- {
+		items[i] = heap.Pop(&h).(candidate).item
+	}
+	return items
+}
+
+func (t *KDTree) kNearest(n *node, p Point, k int, h *candidates) {
+	if n == nil {
+		return
+	}
+	d := p.distanceSquare(n.item.Point)
+	if h.Len() < k {
+		heap.Push(h, candidate{item: n.item, dist: d})
+	} else if d < (*h)[0].dist {
+		(*h)[0] = candidate{item: n.item, dist: d}
+		heap.Fix(h, 0)
+	}
+
+	diff := p[n.axis] - n.item.Point[n.axis]
+	near, far := n.leftTree, n.rightTree
+	if diff >= 0 {
+		near, far = far, near
+	}
+	t.kNearest(near, p, k, h)
+	// only search the other side if the splitting plane is closer than the farthest candidate
+	if h.Len() < k || diff*diff < (*h)[0].dist {
+		t.kNearest(far, p, k, h)
+	}
+}
+
+// Radius returns all items whose distance to input point <= r
+func (t *KDTree) Radius(p Point, r float64) []Item {
+	t.checkDim(p)
+	var items []Item
+	t.radius(t.root, p, r*r, &items)
+	return items
+}
+
+func (t *KDTree) radius(n *node, p Point, r2 float64, items *[]Item) {
+	if n == nil {
+		return
+	}
+	if p.distanceSquare(n.item.Point) <= r2 {
+		*items = append(*items, n.item)
+	}
+	diff := p[n.axis] - n.item.Point[n.axis]
+	if diff < 0 || diff*diff <= r2 {
+		t.radius(n.leftTree, p, r2, items)
+	}
+	if diff >= 0 || diff*diff <= r2 {
+		t.radius(n.rightTree, p, r2, items)
+	}
+}
+
+// Items returns all items inside the tree in pre-order
+func (t *KDTree) Items() []Item {
+	items := make([]Item, 0, t.itemNum)
+	t.items(t.root, &items)
+	return items
+}
+
+func (t *KDTree) items(n *node, items *[]Item) {
+	if n == nil {
+		return
+	}
+	*items = append(*items, n.item)
+	t.items(n.leftTree, items)
+	t.items(n.rightTree, items)
+}
+
+func (t *KDTree) each(n *node, f func(value interface{}) bool) bool {
+	if n == nil {
+		return true
+	}
+	return f(n.item.Value) && t.each(n.leftTree, f) && t.each(n.rightTree, f)
+}
+
+// Each visits values in the same order as Values until f returns false
+func (t *KDTree) Each(f func(value interface{}) bool) {
+	t.each(t.root, f)
+}
+
+// Rebalance rebuilds the tree by median splitting, useful after many Insert
+func (t *KDTree) Rebalance() {
+	t.root = t.build(t.Items(), 0)
+}
+
+// Size returns number of items inside the tree
+func (t *KDTree) Size() int {
+	return t.itemNum
+}
+
+// Empty returns true if the tree has no item
+func (t *KDTree) Empty() bool {
+	return t.itemNum == 0
+}
+
+// Clear clears the tree
+func (t *KDTree) Clear() {
+	*t = *NewKDTree(t.dim)
+}
+
+// Values returns values of all items inside the tree in pre-order
+func (t *KDTree) Values() []interface{} {
+	items := t.Items()
+	values := make([]interface{}, 0, len(items))
+	for i := range items {
+		values = append(values, items[i].Value)
+	}
+	return values
+}
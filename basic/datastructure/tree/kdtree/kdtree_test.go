@@ -0,0 +1,129 @@
+package kdtree
+
+import (
+	"godev/basic"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func randomItems(n, dim int) []Item {
+	items := make([]Item, n)
+	for i := range items {
+		p := make(Point, dim)
+		for j := range p {
+			p[j] = float64(rand.Intn(100))
+		}
+		items[i] = Item{Point: p, Value: i}
+	}
+	return items
+}
+
+// bruteKNearest returns distances of k nearest items
+func bruteKNearest(items []Item, p Point, k int) []float64 {
+	dist := make([]float64, len(items))
+	for i := range items {
+		dist[i] = p.Distance(items[i].Point)
+	}
+	sort.Float64s(dist)
+	if k > len(dist) {
+		k = len(dist)
+	}
+	return dist[:k]
+}
+
+func TestNewKDTree(t *testing.T) {
+	var _ basic.Container = (*KDTree)(nil)
+
+	tree := NewKDTree(2)
+	if !tree.Empty() || tree.Size() != 0 || len(tree.Values()) != 0 || tree.Dim() != 2 {
+		t.Fail()
+	}
+	if _, found := tree.Nearest(Point{0, 0}); found {
+		t.Fail()
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fail()
+		}
+	}()
+	tree.Insert(Point{1, 2, 3}, nil)
+}
+
+func TestKDTree_KNearest(t *testing.T) {
+	items := randomItems(1000, 3)
+	built := Build(3, items)
+	inserted := NewKDTree(3)
+	for i := range items {
+		inserted.Insert(items[i].Point, items[i].Value)
+	}
+	if built.Size() != len(items) || inserted.Size() != len(items) || len(built.Values()) != len(items) {
+		t.Fail()
+	}
+
+	for q := 0; q < 50; q++ {
+		p := randomItems(1, 3)[0].Point
+		expected := bruteKNearest(items, p, 10)
+		for _, tree := range []*KDTree{built, inserted} {
+			res := tree.KNearest(p, 10)
+			if len(res) != len(expected) {
+				t.Fatal(len(res))
+			}
+			for i := range res {
+				if p.Distance(res[i].Point) != expected[i] {
+					t.Fatal(i, p.Distance(res[i].Point), expected[i])
+				}
+			}
+			if nearest, _ := tree.Nearest(p); p.Distance(nearest.Point) != expected[0] {
+				t.Fail()
+			}
+		}
+	}
+
+	if len(built.KNearest(Point{0, 0, 0}, 2000)) != len(items) || built.KNearest(Point{0, 0, 0}, 0) != nil {
+		t.Fail()
+	}
+
+	inserted.Rebalance()
+	if len(inserted.Items()) != len(items) {
+		t.Fail()
+	}
+	inserted.Clear()
+	if !inserted.Empty() {
+		t.Fail()
+	}
+}
+
+func TestKDTree_Radius(t *testing.T) {
+	items := randomItems(1000, 2)
+	tree := Build(2, items)
+
+	for q := 0; q < 50; q++ {
+		p := randomItems(1, 2)[0].Point
+		r := float64(rand.Intn(30))
+		cnt := 0
+		for i := range items {
+			if p.Distance(items[i].Point) <= r {
+				cnt++
+			}
+		}
+		res := tree.Radius(p, r)
+		if len(res) != cnt {
+			t.Fatal(len(res), cnt)
+		}
+		for i := range res {
+			if p.Distance(res[i].Point) > r {
+				t.Fail()
+			}
+		}
+	}
+}
+
+func BenchmarkKDTree_KNearest(b *testing.B) {
+	tree := Build(2, randomItems(100000, 2))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.KNearest(Point{50, 50}, 10)
+	}
+}
@@ -0,0 +1,316 @@
+package persistenttree
+
+import (
+	"godev/basic"
+	"godev/basic/datastructure/maps"
+)
+
+// references:
+// https://en.wikipedia.org/wiki/Persistent_data_structure#Trees
+// https://en.wikipedia.org/wiki/AVL_tree
+
+// node struct is never modified after creation, so it can be shared by many versions
+type node struct {
+	key, value          interface{}
+	leftTree, rightTree *node
+	height              int
+}
+
+func newNode(key, value interface{}, left, right *node) *node {
+	return &node{
+		key:       key,
+		value:     value,
+		leftTree:  left,
+		rightTree: right,
+		height:    maxInt(getHeight(left), getHeight(right)) + 1,
+	}
+}
+
+func maxInt(x, y int) int {
+	if x > y {
+		return x
+	}
+	return y
+}
+
+func getHeight(n *node) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// balance builds a balanced node from key, value and two (balanced) sub-trees
+//	sub-trees' heights differ at most 2, rotations copy nodes instead of modifying them
+func balance(key, value interface{}, left, right *node) *node {
+	hl, hr := getHeight(left), getHeight(right)
+	if hl > hr+1 {
+		// left left
+		if getHeight(left.leftTree) >= getHeight(left.rightTree) {
+			return newNode(left.key, left.value, left.leftTree, newNode(key, value, left.rightTree, right))
+		}
+		// left right
+		lr := left.rightTree
+		return newNode(lr.key, lr.value,
+			newNode(left.key, left.value, left.leftTree, lr.leftTree),
+			newNode(key, value, lr.rightTree, right))
+	}
+	if hr > hl+1 {
+		// right right
+		if getHeight(right.rightTree) >= getHeight(right.leftTree) {
+			return newNode(right.key, right.value, newNode(key, value, left, right.leftTree), right.rightTree)
+		}
+		// right left
+		rl := right.leftTree
+		return newNode(rl.key, rl.value,
+			newNode(key, value, left, rl.leftTree),
+			newNode(right.key, right.value, rl.rightTree, right.rightTree))
+	}
+	return newNode(key, value, left, right)
+}
+
+// Tree struct is an immutable (persistent) AVL tree
+//	every modification returns a new version sharing unchanged sub-trees with the old one,
+//	so any version can be read concurrently without locks
+type Tree struct {
+	root       *node
+	comparator basic.Comparator
+	size       int
+}
+
+// NewTree creates a new empty persistent tree
+func NewTree(comparator basic.Comparator) *Tree {
+	return &Tree{
+		root:       nil,
+		comparator: comparator,
+		size:       0,
+	}
+}
+
+// Comparator returns the comparator of the tree
+func (t *Tree) Comparator() basic.Comparator {
+	return t.comparator
+}
+
+// Get returns value with input key when found key inside the tree
+func (t *Tree) Get(key interface{}) (value interface{}, found bool) {
+	n := t.root
+	for n != nil {
+		switch c := t.comparator(key, n.key); {
+		case c < 0:
+			n = n.leftTree
+		case c > 0:
+			n = n.rightTree
+		default:
+			return n.value, true
+		}
+	}
+	return nil, false
+}
+
+// Set returns a new version with key set to value, the receiver is unchanged
+func (t *Tree) Set(key, value interface{}) *Tree {
+	root, added := t.set(t.root, key, value)
+	size := t.size
+	if added {
+		size++
+	}
+	return &Tree{
+		root:       root,
+		comparator: t.comparator,
+		size:       size,
+	}
+}
+
+func (t *Tree) set(n *node, key, value interface{}) (*node, bool) {
+	if n == nil {
+		return newNode(key, value, nil, nil), true
+	}
+	switch c := t.comparator(key, n.key); {
+	case c < 0:
+		left, added := t.set(n.leftTree, key, value)
+		return balance(n.key, n.value, left, n.rightTree), added
+	case c > 0:
+		right, added := t.set(n.rightTree, key, value)
+		return balance(n.key, n.value, n.leftTree, right), added
+	default:
+		// equal keys, only copy this node with new value
+		return newNode(key, value, n.leftTree, n.rightTree), false
+	}
+}
+
+// Delete returns a new version without key and true if key found,
+//	otherwise returns the receiver itself and false
+func (t *Tree) Delete(key interface{}) (*Tree, bool) {
+	root, found := t.delete(t.root, key)
+	if !found {
+		return t, false
+	}
+	return &Tree{
+		root:       root,
+		comparator: t.comparator,
+		size:       t.size - 1,
+	}, true
+}
+
+func (t *Tree) delete(n *node, key interface{}) (*node, bool) {
+	if n == nil {
+		return nil, false
+	}
+	switch c := t.comparator(key, n.key); {
+	case c < 0:
+		left, found := t.delete(n.leftTree, key)
+		if !found {
+			return n, false
+		}
+		return balance(n.key, n.value, left, n.rightTree), true
+	case c > 0:
+		right, found := t.delete(n.rightTree, key)
+		if !found {
+			return n, false
+		}
+		return balance(n.key, n.value, n.leftTree, right), true
+	default:
+		if n.leftTree == nil {
+			return n.rightTree, true
+		}
+		if n.rightTree == nil {
+			return n.leftTree, true
+		}
+		// two children: replace with left most (min) in right sub-tree
+		min := leftMost(n.rightTree)
+		right := deleteMin(n.rightTree)
+		return balance(min.key, min.value, n.leftTree, right), true
+	}
+}
+
+// left most (min) in sub-trees
+func leftMost(n *node) *node {
+	for n.leftTree != nil {
+		n = n.leftTree
+	}
+	return n
+}
+
+// right most (max) in sub-trees
+func rightMost(n *node) *node {
+	for n.rightTree != nil {
+		n = n.rightTree
+	}
+	return n
+}
+
+func deleteMin(n *node) *node {
+	if n.leftTree == nil {
+		return n.rightTree
+	}
+	return balance(n.key, n.value, deleteMin(n.leftTree), n.rightTree)
+}
+
+// Min returns the minimum key and its value
+func (t *Tree) Min() (key, value interface{}, found bool) {
+	if t.root == nil {
+		return nil, nil, false
+	}
+	n := leftMost(t.root)
+	return n.key, n.value, true
+}
+
+// Max returns the maximum key and its value
+func (t *Tree) Max() (key, value interface{}, found bool) {
+	if t.root == nil {
+		return nil, nil, false
+	}
+	n := rightMost(t.root)
+	return n.key, n.value, true
+}
+
+// Snapshot returns the tree itself, since a version never changes, it is free to hold it as a snapshot
+func (t *Tree) Snapshot() *Tree {
+	return t
+}
+
+// Empty returns true if the tree has no k, v pair inside
+func (t *Tree) Empty() bool {
+	return t.size == 0
+}
+
+// Size returns number of k, v pairs inside the tree
+func (t *Tree) Size() int {
+	return t.size
+}
+
+// Height returns height of the tree
+func (t *Tree) Height() int {
+	return getHeight(t.root)
+}
+
+// Keys returns keys of all nodes inside the tree in-order
+func (t *Tree) Keys() []interface{} {
+	keys := make([]interface{}, 0, t.size)
+	it := t.Iterator()
+	for it.HasNext() {
+		k, _ := it.Next()
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Values returns values of all nodes inside the tree
+//	notice: values follows keys' order!
+func (t *Tree) Values() []interface{} {
+	values := make([]interface{}, 0, t.size)
+	it := t.Iterator()
+	for it.HasNext() {
+		_, v := it.Next()
+		values = append(values, v)
+	}
+	return values
+}
+
+// Each visits values in key order until f returns false
+func (t *Tree) Each(f func(value interface{}) bool) {
+	it := t.Iterator()
+	for it.HasNext() {
+		if _, v := it.Next(); !f(v) {
+			return
+		}
+	}
+}
+
+// iterator struct does in-order traversal with a stack, no copy of nodes
+type iterator struct {
+	stack []*node
+}
+
+func (iter *iterator) pushLeft(n *node) {
+	for n != nil {
+		iter.stack = append(iter.stack, n)
+		n = n.leftTree
+	}
+}
+
+// HasNext to meet Iterator interface
+func (iter *iterator) HasNext() bool {
+	return len(iter.stack) != 0
+}
+
+// Next to meet Iterator interface
+func (iter *iterator) Next() (key, value interface{}) {
+	if !iter.HasNext() {
+		return nil, nil
+	}
+	n := iter.stack[len(iter.stack)-1]
+	iter.stack = iter.stack[:len(iter.stack)-1]
+	iter.pushLeft(n.rightTree)
+	return n.key, n.value
+}
+
+// Iterator returns an in-order iterator over this version
+func (t *Tree) Iterator() maps.Iterator {
+	iter := &iterator{
+		stack: make([]*node, 0, getHeight(t.root)),
+	}
+	iter.pushLeft(t.root)
+	return iter
+}
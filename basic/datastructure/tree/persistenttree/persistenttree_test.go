@@ -0,0 +1,191 @@
+package persistenttree
+
+import (
+	"godev/basic"
+	"math/rand"
+	"testing"
+)
+
+// checkBalance returns height of the sub-tree, or -1 if it is not a valid AVL tree
+func checkBalance(t *Tree, n *node) int {
+	if n == nil {
+		return 0
+	}
+	if n.leftTree != nil && t.comparator(n.leftTree.key, n.key) >= 0 {
+		return -1
+	}
+	if n.rightTree != nil && t.comparator(n.rightTree.key, n.key) <= 0 {
+		return -1
+	}
+	hl, hr := checkBalance(t, n.leftTree), checkBalance(t, n.rightTree)
+	if hl < 0 || hr < 0 || hl-hr > 1 || hr-hl > 1 || n.height != maxInt(hl, hr)+1 {
+		return -1
+	}
+	return n.height
+}
+
+func TestNewTree(t *testing.T) {
+	tree := NewTree(basic.IntComparator)
+
+	if !tree.Empty() || tree.Size() != 0 || len(tree.Keys()) != 0 || len(tree.Values()) != 0 || tree.Height() != 0 {
+		t.Fail()
+	}
+	if _, _, found := tree.Min(); found {
+		t.Fail()
+	}
+	if _, _, found := tree.Max(); found {
+		t.Fail()
+	}
+	if tree.Iterator().HasNext() {
+		t.Fail()
+	}
+}
+
+func TestTree_Set(t *testing.T) {
+	tree := NewTree(basic.IntComparator)
+
+	a := []int{12, 7, 25, 15, 28, 33, 41, 1}
+	aSorted := []int{1, 7, 12, 15, 25, 28, 33, 41}
+
+	versions := make([]*Tree, 0, len(a))
+	for i, k := range a {
+		tree = tree.Set(k, i)
+		versions = append(versions, tree)
+	}
+
+	if tree.Size() != len(a) || checkBalance(tree, tree.root) < 0 {
+		t.Fail()
+	}
+	for i, k := range tree.Keys() {
+		if k.(int) != aSorted[i] {
+			t.Fail()
+		}
+	}
+	for i, v := range tree.Values() {
+		if a[v.(int)] != aSorted[i] {
+			t.Fail()
+		}
+	}
+
+	// old versions are untouched
+	for i, version := range versions {
+		if version.Size() != i+1 {
+			t.Fail()
+		}
+		for j, k := range a {
+			v, found := version.Get(k)
+			if j <= i && (!found || v.(int) != j) {
+				t.Fail()
+			}
+			if j > i && found {
+				t.Fail()
+			}
+		}
+	}
+
+	// update value
+	updated := tree.Set(12, 100)
+	if v, _ := updated.Get(12); v.(int) != 100 || updated.Size() != tree.Size() {
+		t.Fail()
+	}
+	if v, _ := tree.Get(12); v.(int) != 0 {
+		t.Fail()
+	}
+
+	if k, _, _ := tree.Min(); k.(int) != 1 {
+		t.Fail()
+	}
+	if k, _, _ := tree.Max(); k.(int) != 41 {
+		t.Fail()
+	}
+	if tree.Snapshot() != tree {
+		t.Fail()
+	}
+}
+
+func TestTree_Delete(t *testing.T) {
+	tree := NewTree(basic.IntComparator)
+
+	a := []int{12, 7, 25, 15, 28, 33, 41, 1}
+	for i := range a {
+		tree = tree.Set(a[i], i)
+	}
+	full := tree
+
+	if same, ok := tree.Delete(100); ok || same != tree {
+		t.Fail()
+	}
+
+	for i := range a {
+		var ok bool
+		tree, ok = tree.Delete(a[i])
+		if !ok || tree.Size() != len(a)-i-1 || checkBalance(tree, tree.root) < 0 {
+			t.Fail()
+		}
+		if _, found := tree.Get(a[i]); found {
+			t.Fail()
+		}
+	}
+
+	if !tree.Empty() {
+		t.Fail()
+	}
+	if full.Size() != len(a) || len(full.Keys()) != len(a) {
+		t.Fail()
+	}
+}
+
+func TestTree_Random(t *testing.T) {
+	tree := NewTree(basic.IntComparator)
+	m := make(map[int]int)
+
+	for i := 0; i < 5000; i++ {
+		k := rand.Intn(1000)
+		if rand.Intn(3) == 0 {
+			var ok bool
+			tree, ok = tree.Delete(k)
+			if _, found := m[k]; found != ok {
+				t.Fatal(k)
+			}
+			delete(m, k)
+		} else {
+			tree = tree.Set(k, i)
+			m[k] = i
+		}
+	}
+
+	if tree.Size() != len(m) || checkBalance(tree, tree.root) < 0 {
+		t.Fail()
+	}
+	for k, v := range m {
+		if value, found := tree.Get(k); !found || value.(int) != v {
+			t.Fail()
+		}
+	}
+}
+
+func BenchmarkTree_Set(b *testing.B) {
+	tree := NewTree(basic.IntComparator)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree = tree.Set(i, i)
+	}
+}
+
+func TestTree_Each(t *testing.T) {
+	tr := NewTree(basic.IntComparator)
+	for _, v := range rand.Perm(50) {
+		tr = tr.Set(v, v*2)
+	}
+	i := 0
+	tr.Each(func(value interface{}) bool {
+		if value.(int) != i*2 {
+			t.Fail()
+		}
+		i++
+		return i < 20
+	})
+	if i != 20 {
+		t.Fail()
+	}
+}
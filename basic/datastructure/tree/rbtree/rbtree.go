@@ -138,6 +138,28 @@ func (rbTree *RBTree) Keys() []interface{} {
 	return keys
 }
 
+func (rbTree *RBTree) ascend(node *Node, f func(key, value interface{}) bool) bool {
+	if node.key == nil {
+		return true
+	}
+	return rbTree.ascend(node.leftTree, f) && f(node.key, node.value) && rbTree.ascend(node.rightTree, f)
+}
+
+// Ascend visits key value pairs in ascending key order until f returns false
+func (rbTree *RBTree) Ascend(f func(key, value interface{}) bool) {
+	if rbTree.Root == nil {
+		return
+	}
+	rbTree.ascend(rbTree.Root, f)
+}
+
+// Each visits values in keys' order until f returns false
+func (rbTree *RBTree) Each(f func(value interface{}) bool) {
+	rbTree.Ascend(func(_, value interface{}) bool {
+		return f(value)
+	})
+}
+
 /*
 // this recursive way is replaced by add itemNum filed to record size
 func (rbTree *RBTree) size(node *Node) int {
@@ -172,6 +194,18 @@ func (rbTree *RBTree) MinKey() interface{} {
 	return rbTree.minKey(rbTree.Root)
 }
 
+func (rbTree *RBTree) maxKey(node *Node) interface{} {
+	if node.rightTree == NIL {
+		return node.key
+	}
+	return rbTree.maxKey(node.rightTree)
+}
+
+// MaxKey returns the maximum key inside nodes of the tree
+func (rbTree *RBTree) MaxKey() interface{} {
+	return rbTree.maxKey(rbTree.Root)
+}
+
 // NewRBTree creates a new red-black tree
 func NewRBTree(comparator basic.Comparator) *RBTree {
 	rbTree := &RBTree{Comparator: comparator}
@@ -369,6 +403,7 @@ func (rbTree *RBTree) deleteChild(node *Node, key interface{}) bool {
 		}
 		smallestNode := rbTree.getSmallestChild(node.rightTree)
 		smallestNode.key, node.key = node.key, smallestNode.key
+		smallestNode.value, node.value = node.value, smallestNode.value
 		rbTree.deleteOneChild(smallestNode)
 		return true
 	}
@@ -523,12 +558,13 @@ func (rbTree *RBTree) Update(key, value interface{}) {
 
 // Iterator struct
 type Iterator struct {
-	nodes       []*Node
-	cursor, end int
+	nodes  []*Node
+	cursor int
 }
 
 func (rbTree *RBTree) allNodes(node *Node, nodes *[]*Node) {
-	if node.key == nil {
+	// empty tree has no root
+	if node == nil || node.key == nil {
 		return
 	}
 	rbTree.allNodes(node.leftTree, nodes)
@@ -544,13 +580,12 @@ func (rbTree *RBTree) Iterator() *Iterator {
 	return &Iterator{
 		nodes:  allNodes,
 		cursor: 0,
-		end:    len(allNodes) - 1,
 	}
 }
 
 // HasNext returns true if iterator can still iterate
 func (it *Iterator) HasNext() bool {
-	return it.cursor != it.end
+	return it.cursor < len(it.nodes)
 }
 
 // Next returns key, value stored in the tree, used by Iterator
@@ -30,6 +30,9 @@ func TestRBTree(t *testing.T) {
 	if rbTree.MinKey().(int) != -8 {
 		t.Fail()
 	}
+	if rbTree.MaxKey().(int) != 8 {
+		t.Fail()
+	}
 
 	fmt.Println(rbTree)
 
@@ -121,8 +124,8 @@ func TestRBTree_Iterator(t *testing.T) {
 
 	it := rbTree.Iterator()
 	i := 0
-	expectedKeys := []int{-8, -5, -3, 1, 2, 3, 4, 5, 6}
-	expectedValues := []int{6, 3, 4, 0, 1, 2, 8, 5, 9}
+	expectedKeys := []int{-8, -5, -3, 1, 2, 3, 4, 5, 6, 8}
+	expectedValues := []int{6, 3, 4, 0, 1, 2, 8, 5, 9, 7}
 	for it.HasNext() {
 		k, v := it.Next()
 		if k.(int) != expectedKeys[i] || v.(int) != expectedValues[i] {
@@ -131,6 +134,13 @@ func TestRBTree_Iterator(t *testing.T) {
 		}
 		i++
 	}
+	// the last entry is visited too
+	if i != len(expectedKeys) {
+		t.Fatal(i)
+	}
+	if NewRBTree(basic.IntComparator).Iterator().HasNext() {
+		t.Fail()
+	}
 }
 
 // BenchmarkRBTree_Insert-8   	 1000000	      1386 ns/op
@@ -147,3 +157,19 @@ func BenchmarkRBTree_Insert(b *testing.B) {
 		rbTree.Insert(data[i], data[i])
 	}
 }
+
+func TestRBTree_DeleteKeepsValues(t *testing.T) {
+	rbTree := NewRBTree(basic.IntComparator)
+	for i := 0; i < 10; i++ {
+		rbTree.Insert(i, i*10)
+	}
+
+	if !rbTree.Delete(3) {
+		t.Fail()
+	}
+	for _, k := range rbTree.Keys() {
+		if v, found := rbTree.Get(k); !found || v.(int) != k.(int)*10 {
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,542 @@
+package rtree
+
+import (
+	"math"
+	"reflect"
+	"sort"
+)
+
+// references:
+// https://en.wikipedia.org/wiki/R-tree
+// Guttman, A. (1984). "R-Trees: A Dynamic Index Structure for Spatial Searching"
+// Leutenegger, S. T. et al. (1997). "STR: A Simple and Efficient Algorithm for R-Tree Packing"
+
+// Rect stands for an axis-aligned bounding box, Min[i] <= Max[i] for every dimension i
+type Rect struct {
+	Min, Max []float64
+}
+
+// NewRect creates a new rect from two corner points, coordinates are reordered if needed
+func NewRect(p, q []float64) Rect {
+	if len(p) != len(q) {
+		panic("point dimension mismatch")
+	}
+	r := Rect{
+		Min: make([]float64, len(p)),
+		Max: make([]float64, len(p)),
+	}
+	for i := range p {
+		r.Min[i], r.Max[i] = math.Min(p[i], q[i]), math.Max(p[i], q[i])
+	}
+	return r
+}
+
+// NewPointRect creates a rect with zero size on the point
+func NewPointRect(p []float64) Rect {
+	return NewRect(p, p)
+}
+
+// Intersects returns true if two rects overlap (boundaries included)
+func (r Rect) Intersects(o Rect) bool {
+	for i := range r.Min {
+		if r.Min[i] > o.Max[i] || o.Min[i] > r.Max[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains returns true if o is inside r
+func (r Rect) Contains(o Rect) bool {
+	for i := range r.Min {
+		if o.Min[i] < r.Min[i] || o.Max[i] > r.Max[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal returns true if two rects have same coordinates
+func (r Rect) Equal(o Rect) bool {
+	for i := range r.Min {
+		if r.Min[i] != o.Min[i] || r.Max[i] != o.Max[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Area returns area (volume) of the rect
+func (r Rect) Area() float64 {
+	a := 1.0
+	for i := range r.Min {
+		a *= r.Max[i] - r.Min[i]
+	}
+	return a
+}
+
+// Union returns the minimum rect covering both rects
+func (r Rect) Union(o Rect) Rect {
+	u := Rect{
+		Min: make([]float64, len(r.Min)),
+		Max: make([]float64, len(r.Max)),
+	}
+	for i := range r.Min {
+		u.Min[i], u.Max[i] = math.Min(r.Min[i], o.Min[i]), math.Max(r.Max[i], o.Max[i])
+	}
+	return u
+}
+
+func (r Rect) center(axis int) float64 {
+	return (r.Min[axis] + r.Max[axis]) / 2
+}
+
+// enlargement needed for r to cover o
+func (r Rect) enlargement(o Rect) float64 {
+	return r.Union(o).Area() - r.Area()
+}
+
+// Entry struct stored inside the tree
+type Entry struct {
+	Rect  Rect
+	Value interface{}
+}
+
+type entry struct {
+	rect  Rect
+	child *node       // internal node entry
+	value interface{} // leaf node entry
+}
+
+type node struct {
+	leaf    bool
+	entries []entry
+	parent  *node
+}
+
+func (n *node) bound() Rect {
+	r := n.entries[0].rect
+	for i := 1; i < len(n.entries); i++ {
+		r = r.Union(n.entries[i].rect)
+	}
+	return r
+}
+
+// index of entry in parent pointing to n
+func (n *node) indexInParent() int {
+	for i := range n.parent.entries {
+		if n.parent.entries[i].child == n {
+			return i
+		}
+	}
+	return -1
+}
+
+// RTree struct
+type RTree struct {
+	root                   *node
+	dim                    int
+	minEntries, maxEntries int
+	itemNum                int
+}
+
+// NewRTree creates a new empty r-tree
+//	dim is dimension of rects, maxEntries is maximum number of entries on each node (at least 4),
+//	minimum number of entries is maxEntries / 2 when deleting
+func NewRTree(dim, maxEntries int) *RTree {
+	if dim < 1 {
+		panic("dimension should be at least 1")
+	}
+	if maxEntries < 4 {
+		panic("Maximum number of entries on each node should be at least 4")
+	}
+	return &RTree{
+		root:       &node{leaf: true},
+		dim:        dim,
+		minEntries: maxEntries / 2,
+		maxEntries: maxEntries,
+		itemNum:    0,
+	}
+}
+
+func (t *RTree) checkDim(r Rect) {
+	if len(r.Min) != t.dim || len(r.Max) != t.dim {
+		panic("rect dimension mismatch")
+	}
+}
+
+// Insert inserts a rect with its value
+func (t *RTree) Insert(r Rect, value interface{}) {
+	t.checkDim(r)
+	t.insert(entry{rect: r, value: value})
+	t.itemNum++
+}
+
+func (t *RTree) insert(e entry) {
+	leaf := t.chooseLeaf(t.root, e.rect)
+	leaf.entries = append(leaf.entries, e)
+	var split *node
+	if len(leaf.entries) > t.maxEntries {
+		split = t.splitNode(leaf)
+	}
+	t.adjustTree(leaf, split)
+}
+
+// chooseLeaf selects leaf whose rect needs least enlargement, resolves ties by smallest area
+func (t *RTree) chooseLeaf(n *node, r Rect) *node {
+	for !n.leaf {
+		best, bestEnlargement, bestArea := 0, math.Inf(1), math.Inf(1)
+		for i := range n.entries {
+			area := n.entries[i].rect.Area()
+			enlargement := n.entries[i].rect.enlargement(r)
+			if enlargement < bestEnlargement || (enlargement == bestEnlargement && area < bestArea) {
+				best, bestEnlargement, bestArea = i, enlargement, area
+			}
+		}
+		n = n.entries[best].child
+	}
+	return n
+}
+
+// adjustTree walks from n up to root, fixing covering rects and propagating splits
+func (t *RTree) adjustTree(n, split *node) {
+	for n != t.root {
+		parent := n.parent
+		parent.entries[n.indexInParent()].rect = n.bound()
+		if split != nil {
+			split.parent = parent
+			parent.entries = append(parent.entries, entry{rect: split.bound(), child: split})
+			split = nil
+			if len(parent.entries) > t.maxEntries {
+				split = t.splitNode(parent)
+			}
+		}
+		n = parent
+	}
+	if split != nil {
+		// root split, grow the tree
+		root := &node{leaf: false}
+		for _, child := range []*node{n, split} {
+			child.parent = root
+			root.entries = append(root.entries, entry{rect: child.bound(), child: child})
+		}
+		t.root = root
+	}
+}
+
+// splitNode splits entries of n into n and a new node by quadratic split, returns the new node
+func (t *RTree) splitNode(n *node) *node {
+	entries := n.entries
+
+	// pick seeds: pair wasting most area if put together
+	s1, s2, worst := 0, 1, math.Inf(-1)
+	for i := 0; i < len(entries); i++ {
+		for j := i + 1; j < len(entries); j++ {
+			d := entries[i].rect.Union(entries[j].rect).Area() - entries[i].rect.Area() - entries[j].rect.Area()
+			if d > worst {
+				s1, s2, worst = i, j, d
+			}
+		}
+	}
+
+	g1 := []entry{entries[s1]}
+	g2 := []entry{entries[s2]}
+	r1, r2 := entries[s1].rect, entries[s2].rect
+
+	rest := make([]entry, 0, len(entries)-2)
+	for i := range entries {
+		if i != s1 && i != s2 {
+			rest = append(rest, entries[i])
+		}
+	}
+
+	for len(rest) > 0 {
+		// one group must take all the rest to reach minEntries
+		if len(g1)+len(rest) == t.minEntries {
+			g1 = append(g1, rest...)
+			break
+		}
+		if len(g2)+len(rest) == t.minEntries {
+			g2 = append(g2, rest...)
+			break
+		}
+		// pick next: entry with greatest preference for one group
+		next, maxDiff := 0, math.Inf(-1)
+		for i := range rest {
+			d := math.Abs(r1.enlargement(rest[i].rect) - r2.enlargement(rest[i].rect))
+			if d > maxDiff {
+				next, maxDiff = i, d
+			}
+		}
+		e := rest[next]
+		rest = append(rest[:next], rest[next+1:]...)
+
+		d1, d2 := r1.enlargement(e.rect), r2.enlargement(e.rect)
+		if d1 < d2 || (d1 == d2 && (r1.Area() < r2.Area() || (r1.Area() == r2.Area() && len(g1) <= len(g2)))) {
+			g1 = append(g1, e)
+			r1 = r1.Union(e.rect)
+		} else {
+			g2 = append(g2, e)
+			r2 = r2.Union(e.rect)
+		}
+	}
+
+	n.entries = g1
+	split := &node{leaf: n.leaf, entries: g2, parent: n.parent}
+	if !n.leaf {
+		for i := range split.entries {
+			split.entries[i].child.parent = split
+		}
+	}
+	return split
+}
+
+// Delete deletes entry with equal rect and value, returns true if found
+//	values of uncomparable types such as slices, maps and funcs never equal, delete them with DeleteFunc
+func (t *RTree) Delete(r Rect, value interface{}) bool {
+	return t.DeleteFunc(r, func(v interface{}) bool {
+		return valueEqual(v, value)
+	})
+}
+
+// DeleteFunc deletes the first entry with equal rect whose value satisfies match, returns true if found
+func (t *RTree) DeleteFunc(r Rect, match func(value interface{}) bool) bool {
+	t.checkDim(r)
+	leaf, idx := t.findLeaf(t.root, r, match)
+	if leaf == nil {
+		return false
+	}
+	leaf.entries = append(leaf.entries[:idx], leaf.entries[idx+1:]...)
+	t.condenseTree(leaf)
+	t.itemNum--
+	return true
+}
+
+// valueEqual returns a == b without panicking on uncomparable dynamic types, which are never equal
+func valueEqual(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	ta := reflect.TypeOf(a)
+	if ta != reflect.TypeOf(b) || !ta.Comparable() {
+		return false
+	}
+	return a == b
+}
+
+func (t *RTree) findLeaf(n *node, r Rect, match func(value interface{}) bool) (*node, int) {
+	if n.leaf {
+		for i := range n.entries {
+			if n.entries[i].rect.Equal(r) && match(n.entries[i].value) {
+				return n, i
+			}
+		}
+		return nil, -1
+	}
+	for i := range n.entries {
+		if n.entries[i].rect.Contains(r) {
+			if leaf, idx := t.findLeaf(n.entries[i].child, r, match); leaf != nil {
+				return leaf, idx
+			}
+		}
+	}
+	return nil, -1
+}
+
+// condenseTree removes under-full nodes on the path from n to root and reinserts their entries
+func (t *RTree) condenseTree(n *node) {
+	var orphans []entry
+	for n != t.root {
+		parent := n.parent
+		idx := n.indexInParent()
+		if len(n.entries) < t.minEntries {
+			parent.entries = append(parent.entries[:idx], parent.entries[idx+1:]...)
+			collectLeafEntries(n, &orphans)
+		} else {
+			parent.entries[idx].rect = n.bound()
+		}
+		n = parent
+	}
+
+	// shorten the tree
+	for !t.root.leaf && len(t.root.entries) == 1 {
+		t.root = t.root.entries[0].child
+		t.root.parent = nil
+	}
+	if !t.root.leaf && len(t.root.entries) == 0 {
+		t.root = &node{leaf: true}
+	}
+
+	for i := range orphans {
+		t.insert(orphans[i])
+	}
+}
+
+func collectLeafEntries(n *node, entries *[]entry) {
+	if n.leaf {
+		*entries = append(*entries, n.entries...)
+		return
+	}
+	for i := range n.entries {
+		collectLeafEntries(n.entries[i].child, entries)
+	}
+}
+
+func eachLeafValue(n *node, f func(value interface{}) bool) bool {
+	if n.leaf {
+		for i := range n.entries {
+			if !f(n.entries[i].value) {
+				return false
+			}
+		}
+		return true
+	}
+	for i := range n.entries {
+		if !eachLeafValue(n.entries[i].child, f) {
+			return false
+		}
+	}
+	return true
+}
+
+// Search returns all entries whose rect intersects with r
+func (t *RTree) Search(r Rect) []Entry {
+	t.checkDim(r)
+	var entries []Entry
+	t.search(t.root, r, &entries)
+	return entries
+}
+
+func (t *RTree) search(n *node, r Rect, entries *[]Entry) {
+	for i := range n.entries {
+		if !n.entries[i].rect.Intersects(r) {
+			continue
+		}
+		if n.leaf {
+			*entries = append(*entries, Entry{Rect: n.entries[i].rect, Value: n.entries[i].value})
+		} else {
+			t.search(n.entries[i].child, r, entries)
+		}
+	}
+}
+
+// BulkLoad packs existing and input entries into a new tree by Sort-Tile-Recursive,
+//	much faster and better packed than inserting one by one
+func (t *RTree) BulkLoad(entries []Entry) {
+	all := make([]entry, 0, t.itemNum+len(entries))
+	collectLeafEntries(t.root, &all)
+	for i := range entries {
+		t.checkDim(entries[i].Rect)
+		all = append(all, entry{rect: entries[i].Rect, value: entries[i].Value})
+	}
+	t.itemNum = len(all)
+	if len(all) == 0 {
+		t.root = &node{leaf: true}
+		return
+	}
+
+	leaf := true
+	for {
+		var nodes []*node
+		for _, group := range t.tile(all, 0) {
+			n := &node{leaf: leaf, entries: group}
+			if !leaf {
+				for i := range group {
+					group[i].child.parent = n
+				}
+			}
+			nodes = append(nodes, n)
+		}
+		if len(nodes) == 1 {
+			t.root = nodes[0]
+			t.root.parent = nil
+			return
+		}
+		all = make([]entry, len(nodes))
+		for i, n := range nodes {
+			all[i] = entry{rect: n.bound(), child: n}
+		}
+		leaf = false
+	}
+}
+
+// tile sorts entries by center on axis, cuts them into slabs and recurses on next axis,
+//	on last axis slabs are cut into groups of maxEntries
+func (t *RTree) tile(entries []entry, axis int) [][]entry {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].rect.center(axis) < entries[j].rect.center(axis)
+	})
+	if axis == t.dim-1 || len(entries) <= t.maxEntries {
+		groups := make([][]entry, 0, (len(entries)+t.maxEntries-1)/t.maxEntries)
+		for i := 0; i < len(entries); i += t.maxEntries {
+			end := i + t.maxEntries
+			if end > len(entries) {
+				end = len(entries)
+			}
+			groups = append(groups, entries[i:end:end])
+		}
+		return groups
+	}
+	pages := math.Ceil(float64(len(entries)) / float64(t.maxEntries))
+	slabs := math.Ceil(math.Pow(pages, 1/float64(t.dim-axis)))
+	slabSize := int(math.Ceil(pages/slabs)) * t.maxEntries
+	var groups [][]entry
+	for i := 0; i < len(entries); i += slabSize {
+		end := i + slabSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+		groups = append(groups, t.tile(entries[i:end], axis+1)...)
+	}
+	return groups
+}
+
+// Height returns height of the tree, all leaves are on the same level
+func (t *RTree) Height() int {
+	h := 1
+	for n := t.root; !n.leaf; n = n.entries[0].child {
+		h++
+	}
+	return h
+}
+
+// Entries returns all entries inside the tree
+func (t *RTree) Entries() []Entry {
+	var all []entry
+	collectLeafEntries(t.root, &all)
+	entries := make([]Entry, len(all))
+	for i := range all {
+		entries[i] = Entry{Rect: all[i].rect, Value: all[i].value}
+	}
+	return entries
+}
+
+// Size returns number of entries inside the tree
+func (t *RTree) Size() int {
+	return t.itemNum
+}
+
+// Empty returns true if the tree has no entry
+func (t *RTree) Empty() bool {
+	return t.itemNum == 0
+}
+
+// Clear clears the tree
+func (t *RTree) Clear() {
+	*t = *NewRTree(t.dim, t.maxEntries)
+}
+
+// Values returns values of all entries inside the tree
+func (t *RTree) Values() []interface{} {
+	entries := t.Entries()
+	values := make([]interface{}, len(entries))
+	for i := range entries {
+		values[i] = entries[i].Value
+	}
+	return values
+}
+
+// Each visits values of all entries in the same order as Values until f returns false
+func (t *RTree) Each(f func(value interface{}) bool) {
+	eachLeafValue(t.root, f)
+}
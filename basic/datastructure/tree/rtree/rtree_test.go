@@ -0,0 +1,234 @@
+package rtree
+
+import (
+	"godev/basic"
+	"math/rand"
+	"testing"
+)
+
+func randomEntries(n int) []Entry {
+	entries := make([]Entry, n)
+	for i := range entries {
+		x, y := rand.Float64()*1000, rand.Float64()*1000
+		entries[i] = Entry{
+			Rect:  NewRect([]float64{x, y}, []float64{x + rand.Float64()*10, y + rand.Float64()*10}),
+			Value: i,
+		}
+	}
+	return entries
+}
+
+func bruteSearch(entries []Entry, r Rect) int {
+	cnt := 0
+	for i := range entries {
+		if entries[i].Rect.Intersects(r) {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+// checkTree checks covering rects, parent pointers and leaves depth
+func checkTree(t *testing.T, tree *RTree, n *node, depth int, leafDepth *int) {
+	if n.leaf {
+		if *leafDepth == -1 {
+			*leafDepth = depth
+		} else if *leafDepth != depth {
+			t.Fatal("leaves not on same level")
+		}
+		return
+	}
+	for i := range n.entries {
+		child := n.entries[i].child
+		if child.parent != n || !n.entries[i].rect.Equal(child.bound()) {
+			t.Fatal("invalid internal entry")
+		}
+		if len(child.entries) > tree.maxEntries {
+			t.Fatal("node overflow")
+		}
+		checkTree(t, tree, child, depth+1, leafDepth)
+	}
+}
+
+func TestRect(t *testing.T) {
+	a := NewRect([]float64{2, 2}, []float64{0, 0})
+	b := NewRect([]float64{1, 1}, []float64{3, 3})
+	c := NewPointRect([]float64{5, 5})
+
+	if a.Area() != 4 || c.Area() != 0 || !a.Intersects(b) || a.Intersects(c) {
+		t.Fail()
+	}
+	if u := a.Union(b); u.Area() != 9 || !u.Contains(a) || !u.Contains(b) || u.Contains(c) {
+		t.Fail()
+	}
+	if !a.Equal(NewRect([]float64{0, 0}, []float64{2, 2})) || a.Equal(b) {
+		t.Fail()
+	}
+}
+
+func TestNewRTree(t *testing.T) {
+	var _ basic.Container = (*RTree)(nil)
+
+	tree := NewRTree(2, 8)
+	if !tree.Empty() || tree.Size() != 0 || len(tree.Values()) != 0 || tree.Height() != 1 {
+		t.Fail()
+	}
+	if len(tree.Search(NewRect([]float64{0, 0}, []float64{1000, 1000}))) != 0 {
+		t.Fail()
+	}
+}
+
+func TestRTree_Insert(t *testing.T) {
+	tree := NewRTree(2, 8)
+	entries := randomEntries(2000)
+	for i := range entries {
+		tree.Insert(entries[i].Rect, entries[i].Value)
+	}
+	if tree.Size() != len(entries) || len(tree.Entries()) != len(entries) || len(tree.Values()) != len(entries) {
+		t.Fail()
+	}
+	leafDepth := -1
+	checkTree(t, tree, tree.root, 0, &leafDepth)
+
+	for q := 0; q < 100; q++ {
+		x, y := rand.Float64()*1000, rand.Float64()*1000
+		r := NewRect([]float64{x, y}, []float64{x + 50, y + 50})
+		if len(tree.Search(r)) != bruteSearch(entries, r) {
+			t.Fail()
+		}
+	}
+
+	tree.Clear()
+	if !tree.Empty() {
+		t.Fail()
+	}
+}
+
+func TestRTree_Delete(t *testing.T) {
+	tree := NewRTree(2, 4)
+	entries := randomEntries(1000)
+	for i := range entries {
+		tree.Insert(entries[i].Rect, entries[i].Value)
+	}
+
+	if tree.Delete(entries[0].Rect, -1) {
+		t.Fail()
+	}
+
+	rand.Shuffle(len(entries), func(i, j int) { entries[i], entries[j] = entries[j], entries[i] })
+	for i := 0; i < 500; i++ {
+		if !tree.Delete(entries[i].Rect, entries[i].Value) {
+			t.Fatal(i)
+		}
+	}
+	entries = entries[500:]
+	if tree.Size() != len(entries) || len(tree.Entries()) != len(entries) {
+		t.Fail()
+	}
+	leafDepth := -1
+	checkTree(t, tree, tree.root, 0, &leafDepth)
+
+	all := NewRect([]float64{0, 0}, []float64{2000, 2000})
+	if len(tree.Search(all)) != len(entries) {
+		t.Fail()
+	}
+
+	for i := range entries {
+		if !tree.Delete(entries[i].Rect, entries[i].Value) {
+			t.Fatal(i)
+		}
+	}
+	if !tree.Empty() || tree.Height() != 1 || len(tree.Search(all)) != 0 {
+		t.Fail()
+	}
+}
+
+func TestRTree_DeleteUncomparable(t *testing.T) {
+	tree := NewRTree(2, 4)
+	r := NewPointRect([]float64{1, 1})
+	tree.Insert(r, []int{1})
+	tree.Insert(r, map[string]int{"a": 1})
+	tree.Insert(r, 1)
+	tree.Insert(r, nil)
+
+	// never equal, no panic
+	if tree.Delete(r, []int{1}) || tree.Delete(r, map[string]int{"a": 1}) || tree.Delete(r, "1") {
+		t.Fail()
+	}
+	if !tree.Delete(r, 1) || !tree.Delete(r, nil) || tree.Delete(r, nil) || tree.Size() != 2 {
+		t.Fail()
+	}
+	deleted := tree.DeleteFunc(r, func(value interface{}) bool {
+		s, ok := value.([]int)
+		return ok && len(s) == 1 && s[0] == 1
+	})
+	if !deleted || tree.Size() != 1 {
+		t.Fail()
+	}
+	if tree.DeleteFunc(NewPointRect([]float64{2, 2}), func(interface{}) bool { return true }) {
+		t.Fail()
+	}
+	if !tree.DeleteFunc(r, func(interface{}) bool { return true }) || !tree.Empty() {
+		t.Fail()
+	}
+}
+
+func TestRTree_BulkLoad(t *testing.T) {
+	for _, dim := range []int{1, 2, 3} {
+		tree := NewRTree(dim, 16)
+		entries := make([]Entry, 5000)
+		for i := range entries {
+			p := make([]float64, dim)
+			for j := range p {
+				p[j] = rand.Float64() * 1000
+			}
+			entries[i] = Entry{Rect: NewPointRect(p), Value: i}
+		}
+		tree.Insert(entries[0].Rect, entries[0].Value)
+		tree.BulkLoad(entries[1:])
+
+		if tree.Size() != len(entries) {
+			t.Fail()
+		}
+		leafDepth := -1
+		checkTree(t, tree, tree.root, 0, &leafDepth)
+
+		min, max := make([]float64, dim), make([]float64, dim)
+		for j := range max {
+			min[j], max[j] = 200, 400
+		}
+		r := NewRect(min, max)
+		if len(tree.Search(r)) != bruteSearch(entries, r) {
+			t.Fail()
+		}
+
+		// still mutable after bulk loading
+		for i := 0; i < 100; i++ {
+			if !tree.Delete(entries[i].Rect, entries[i].Value) {
+				t.Fatal(i)
+			}
+		}
+		if len(tree.Search(r)) != bruteSearch(entries[100:], r) {
+			t.Fail()
+		}
+	}
+}
+
+func BenchmarkRTree_Insert(b *testing.B) {
+	entries := randomEntries(b.N)
+	tree := NewRTree(2, 16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Insert(entries[i].Rect, entries[i].Value)
+	}
+}
+
+func BenchmarkRTree_Search(b *testing.B) {
+	tree := NewRTree(2, 16)
+	tree.BulkLoad(randomEntries(100000))
+	r := NewRect([]float64{500, 500}, []float64{550, 550})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Search(r)
+	}
+}
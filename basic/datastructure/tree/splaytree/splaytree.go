@@ -29,10 +29,19 @@ func (e KeyNotExistError) Error() string {
 	return fmt.Sprintf("delete error: key %v does NOT exist", e.key)
 }
 
+// JoinOrderError error
+type JoinOrderError struct{}
+
+// Error to meet error interface
+func (e JoinOrderError) Error() string {
+	return "join error: keys of the joined tree should be greater than keys of this tree"
+}
+
 // node struct used inside tree
 type node struct {
 	key, value          interface{}
 	left, right, parent *node
+	size                int // number of nodes of the sub-tree
 }
 
 func newNode(k, v interface{}) *node {
@@ -42,7 +51,20 @@ func newNode(k, v interface{}) *node {
 		left:   nil,
 		right:  nil,
 		parent: nil,
+		size:   1,
+	}
+}
+
+func sizeOf(x *node) int {
+	if x == nil {
+		return 0
 	}
+	return x.size
+}
+
+// update recomputes size of x from its children, called after rotation bottom-up
+func (x *node) update() {
+	x.size = sizeOf(x.left) + sizeOf(x.right) + 1
 }
 
 // SplayTree struct
@@ -74,6 +96,7 @@ right rotate (p):
 a   b                 b   c
 */
 func (st *SplayTree) zig(x *node) {
+	p := x.parent
 	x.parent.left = x.right // p.left = b
 	if x.right != nil {     // if b != nil
 		x.right.parent = x.parent // b.parent = p
@@ -83,6 +106,8 @@ func (st *SplayTree) zig(x *node) {
 
 	x.parent = nil
 	st.root = x
+	p.update()
+	x.update()
 }
 
 /*
@@ -96,6 +121,7 @@ left rotate (p):
     a   b         c   b
 */
 func (st *SplayTree) zag(x *node) {
+	p := x.parent
 	x.parent.right = x.left // p.right = a
 	if x.left != nil {      // if a != nil
 		x.left.parent = x.parent // a.parent = p
@@ -105,6 +131,8 @@ func (st *SplayTree) zag(x *node) {
 
 	x.parent = nil
 	st.root = x
+	p.update()
+	x.update()
 }
 
 /*
@@ -122,7 +150,8 @@ right rotate (gp) ----->  right rotate (p)
 a   b                                           c    d
 */
 func (st *SplayTree) zigzig(x *node) {
-	ggp := x.parent.parent.parent
+	p, gp := x.parent, x.parent.parent
+	ggp := gp.parent
 
 	isLeftChild := false
 	if ggp != nil {
@@ -150,6 +179,9 @@ func (st *SplayTree) zigzig(x *node) {
 	} else {
 		ggp.right = x
 	}
+	gp.update()
+	p.update()
+	x.update()
 }
 
 /*
@@ -167,7 +199,8 @@ left rotate (gp) ----->  left rotate (p)
          a   b                                 d    c
 */
 func (st *SplayTree) zagzap(x *node) {
-	ggp := x.parent.parent.parent
+	p, gp := x.parent, x.parent.parent
+	ggp := gp.parent
 
 	isLeftChild := false
 	if ggp != nil {
@@ -195,6 +228,9 @@ func (st *SplayTree) zagzap(x *node) {
 	} else {
 		ggp.right = x
 	}
+	gp.update()
+	p.update()
+	x.update()
 }
 
 /*
@@ -212,7 +248,8 @@ right rotate (p) ----->  left rotate (x)
      a   b                  b   c
 */
 func (st *SplayTree) zigzag(x *node) {
-	ggp := x.parent.parent.parent
+	p, gp := x.parent, x.parent.parent
+	ggp := gp.parent
 
 	isLeftChild := false
 	if ggp != nil {
@@ -240,6 +277,9 @@ func (st *SplayTree) zigzag(x *node) {
 	} else {
 		ggp.right = x
 	}
+	gp.update()
+	p.update()
+	x.update()
 }
 
 /*
@@ -257,7 +297,8 @@ left rotate (x) ----->  right rotate (g)
     a   b          c   a
 */
 func (st *SplayTree) zagzig(x *node) {
-	ggp := x.parent.parent.parent
+	p, gp := x.parent, x.parent.parent
+	ggp := gp.parent
 
 	isLeftChild := false
 	if ggp != nil {
@@ -285,6 +326,9 @@ func (st *SplayTree) zagzig(x *node) {
 	} else {
 		ggp.right = x
 	}
+	gp.update()
+	p.update()
+	x.update()
 }
 
 func (st *SplayTree) splay(x *node) {
@@ -383,41 +427,80 @@ func (st *SplayTree) Delete(k interface{}) error {
 	}
 
 	st.splay(x)
-
-	if x.left == nil {
-		st.replace(x, x.right)
-	} else if x.right == nil {
-		st.replace(x, x.left)
-	} else {
-		y := leftMost(x.right)
-		if y.parent != x {
-			st.replace(y, y.right)
-			y.right = x.right
-			y.right.parent = y
-		}
-		st.replace(x, y)
-		y.left = x.left
-		y.left.parent = y
-	}
+	st.root = st.join(x.left, x.right)
 	st.size--
 	return nil
 }
 
-func (st *SplayTree) replace(x, y *node) {
-	if x.parent == nil {
-		st.root = y
-	} else if x == x.parent.left {
-		x.parent.left = y
-	} else {
-		x.parent.right = y
+// join joins two detached sub-trees, all keys of left are less than keys of right
+//	the maximum of left is splayed to the top, then right becomes its right child
+func (st *SplayTree) join(left, right *node) *node {
+	if right != nil {
+		right.parent = nil
+	}
+	if left == nil {
+		return right
+	}
+	left.parent = nil
+	st.root = left
+	m := rightMost(left)
+	st.splay(m)
+	m.right = right
+	if right != nil {
+		right.parent = m
+	}
+	m.update()
+	return m
+}
+
+// Split moves keys >= k into a new splay tree and returns it, keys < k remain in st
+func (st *SplayTree) Split(k interface{}) *SplayTree {
+	nst := NewSplayTree(st.comparator)
+	// find the minimum key >= k
+	var successor, last *node
+	for x := st.root; x != nil; {
+		last = x
+		if st.comparator(x.key, k) >= 0 {
+			successor = x
+			x = x.left
+		} else {
+			x = x.right
+		}
+	}
+	if successor == nil {
+		// all keys < k, splay the last accessed node anyway to keep amortized bound
+		if last != nil {
+			st.splay(last)
+		}
+		return nst
 	}
-	if y != nil {
-		y.parent = x.parent
+	st.splay(successor)
+	left := successor.left
+	successor.left = nil
+	successor.update()
+	if left != nil {
+		left.parent = nil
 	}
-	x = nil
+	st.root = left
+	st.size = sizeOf(left)
+
+	nst.root = successor
+	nst.size = sizeOf(successor)
+	return nst
+}
+
+// Join moves all keys of other into st, other becomes empty
+//	all keys of other must be greater than keys of st, otherwise JoinOrderError is returned and nothing changes
+func (st *SplayTree) Join(other *SplayTree) error {
+	if st.root != nil && other.root != nil && st.comparator(rightMost(st.root).key, leftMost(other.root).key) >= 0 {
+		return JoinOrderError{}
+	}
+	st.root = st.join(st.root, other.root)
+	st.size += other.size
+	other.Clear()
+	return nil
 }
 
-/*
 // rightMost returns max
 func rightMost(x *node) *node {
 	if x.right == nil {
@@ -425,7 +508,6 @@ func rightMost(x *node) *node {
 	}
 	return rightMost(x.right)
 }
-*/
 
 // leftMost returns min
 func leftMost(x *node) *node {
@@ -498,3 +580,15 @@ func (st *SplayTree) value(x *node, values *[]interface{}) {
 	st.value(x.left, values)
 	st.value(x.right, values)
 }
+
+func (st *SplayTree) each(x *node, f func(value interface{}) bool) bool {
+	if x == nil {
+		return true
+	}
+	return f(x.value) && st.each(x.left, f) && st.each(x.right, f)
+}
+
+// Each visits values in the same order as Values until f returns false
+func (st *SplayTree) Each(f func(value interface{}) bool) {
+	st.each(st.root, f)
+}
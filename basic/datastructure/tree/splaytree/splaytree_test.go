@@ -4,6 +4,7 @@ import (
 	"godev/basic"
 	"godev/basic/datastructure/tree"
 	"godev/utils"
+	"math/rand"
 	"testing"
 )
 
@@ -211,6 +212,85 @@ func TestKeyNotExistError_Error(t *testing.T) {
 }
 
 // BenchmarkSplayTree_Insert-4   	 1000000	      2609 ns/op
+// checkSizes checks parent links, bst order and sub-tree sizes
+func checkSizes(t *testing.T, st *SplayTree) {
+	var walk func(x *node) int
+	walk = func(x *node) int {
+		if x == nil {
+			return 0
+		}
+		if x.left != nil && (x.left.parent != x || st.comparator(x.left.key, x.key) >= 0) {
+			t.Fatal("left")
+		}
+		if x.right != nil && (x.right.parent != x || st.comparator(x.right.key, x.key) <= 0) {
+			t.Fatal("right")
+		}
+		size := walk(x.left) + walk(x.right) + 1
+		if size != x.size {
+			t.Fatal("size")
+		}
+		return size
+	}
+	if st.root != nil && st.root.parent != nil {
+		t.Fatal("root parent")
+	}
+	if walk(st.root) != st.Size() {
+		t.Fatal("tree size")
+	}
+}
+
+func TestSplayTree_SplitJoin(t *testing.T) {
+	st := NewSplayTree(basic.IntComparator)
+	for _, i := range rand.Perm(100) {
+		if err := st.Insert(i, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 100; i += 3 {
+		if err := st.Delete(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkSizes(t, st)
+	size := st.Size()
+
+	right := st.Split(50)
+	checkSizes(t, st)
+	checkSizes(t, right)
+	if st.Size()+right.Size() != size || right.Search(49) || !right.Search(50) || st.Search(50) || !st.Search(49) {
+		t.Fail()
+	}
+
+	// wrong order
+	if err := right.Join(st); err == nil || err.Error() != (JoinOrderError{}).Error() {
+		t.Fail()
+	}
+
+	if err := st.Join(right); err != nil || !right.Empty() || st.Size() != size {
+		t.Fail()
+	}
+	checkSizes(t, st)
+	for i := 0; i < 100; i++ {
+		if st.Search(i) != (i%3 != 0) {
+			t.Fatal(i)
+		}
+	}
+
+	// split beyond bounds
+	all := st.Split(-1)
+	if !st.Empty() || all.Size() != size {
+		t.Fail()
+	}
+	none := all.Split(1000)
+	if !none.Empty() || all.Size() != size {
+		t.Fail()
+	}
+	if err := none.Join(all); err != nil || none.Size() != size {
+		t.Fail()
+	}
+	checkSizes(t, none)
+}
+
 func BenchmarkSplayTree_Insert(b *testing.B) {
 	st := NewSplayTree(basic.IntComparator)
 	data := make([]int, b.N)
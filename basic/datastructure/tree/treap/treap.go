@@ -0,0 +1,344 @@
+package treap
+
+import (
+	"godev/basic"
+	"math/rand"
+	"time"
+)
+
+// references:
+// https://en.wikipedia.org/wiki/Treap
+// https://cp-algorithms.com/data_structures/treap.html
+
+type node struct {
+	key, value  interface{}
+	priority    uint32
+	size        int // number of nodes of the sub-tree
+	left, right *node
+}
+
+func size(n *node) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *node) update() {
+	n.size = size(n.left) + size(n.right) + 1
+}
+
+// Treap struct
+//	a binary search tree on keys and a heap on random priorities at the same time,
+//	which keeps expected height O(log n) without rotations bookkeeping,
+//	all operations are built on split and merge, so Split / Join are O(log n) as well
+type Treap struct {
+	root       *node
+	comparator basic.Comparator
+	rand       *rand.Rand
+}
+
+// NewTreap creates a new empty treap
+func NewTreap(comparator basic.Comparator) *Treap {
+	return &Treap{
+		root:       nil,
+		comparator: comparator,
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// split splits sub-tree n into keys < k and keys >= k
+func (t *Treap) split(n *node, k interface{}) (left, right *node) {
+	if n == nil {
+		return nil, nil
+	}
+	if t.comparator(n.key, k) < 0 {
+		n.right, right = t.split(n.right, k)
+		n.update()
+		return n, right
+	}
+	left, n.left = t.split(n.left, k)
+	n.update()
+	return left, n
+}
+
+// splitAt splits sub-tree n into the first i nodes and the rest
+func splitAt(n *node, i int) (left, right *node) {
+	if n == nil {
+		return nil, nil
+	}
+	if size(n.left) < i {
+		n.right, right = splitAt(n.right, i-size(n.left)-1)
+		n.update()
+		return n, right
+	}
+	left, n.left = splitAt(n.left, i)
+	n.update()
+	return left, n
+}
+
+// merge merges two sub-trees, all keys of left must be less than keys of right
+func merge(left, right *node) *node {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	if left.priority > right.priority {
+		left.right = merge(left.right, right)
+		left.update()
+		return left
+	}
+	right.left = merge(left, right.left)
+	right.update()
+	return right
+}
+
+func (t *Treap) get(k interface{}) *node {
+	n := t.root
+	for n != nil {
+		switch c := t.comparator(k, n.key); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// Get returns value of key if found
+func (t *Treap) Get(k interface{}) (value interface{}, found bool) {
+	if n := t.get(k); n != nil {
+		return n.value, true
+	}
+	return nil, false
+}
+
+// Set inserts key value pair, or updates value if key exists
+func (t *Treap) Set(k, v interface{}) {
+	if n := t.get(k); n != nil {
+		n.value = v
+		return
+	}
+	left, right := t.split(t.root, k)
+	n := &node{key: k, value: v, priority: t.rand.Uint32(), size: 1}
+	t.root = merge(merge(left, n), right)
+}
+
+// Delete deletes key, returns false if key not found
+func (t *Treap) Delete(k interface{}) bool {
+	var parent *node
+	n := t.root
+	for n != nil {
+		c := t.comparator(k, n.key)
+		if c == 0 {
+			break
+		}
+		parent = n
+		if c < 0 {
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	if n == nil {
+		return false
+	}
+	merged := merge(n.left, n.right)
+	if parent == nil {
+		t.root = merged
+	} else if parent.left == n {
+		parent.left = merged
+	} else {
+		parent.right = merged
+	}
+	// fix sizes on the path
+	for x := t.root; x != merged; {
+		x.size--
+		if t.comparator(k, x.key) < 0 {
+			x = x.left
+		} else {
+			x = x.right
+		}
+	}
+	return true
+}
+
+// Split moves keys >= k into a new treap and returns it, keys < k remain in t
+func (t *Treap) Split(k interface{}) *Treap {
+	left, right := t.split(t.root, k)
+	t.root = left
+	return t.with(right)
+}
+
+// SplitAt moves all but the first i keys into a new treap and returns it
+func (t *Treap) SplitAt(i int) *Treap {
+	left, right := splitAt(t.root, i)
+	t.root = left
+	return t.with(right)
+}
+
+func (t *Treap) with(root *node) *Treap {
+	nt := NewTreap(t.comparator)
+	nt.root = root
+	return nt
+}
+
+// JoinOrderError error
+type JoinOrderError struct{}
+
+// Error to meet error interface
+func (e JoinOrderError) Error() string {
+	return "join error: keys of the joined tree should be greater than keys of this tree"
+}
+
+// Join moves all keys of other into t, other becomes empty
+//	all keys of other must be greater than keys of t, otherwise JoinOrderError is returned and nothing changes
+func (t *Treap) Join(other *Treap) error {
+	if t.root != nil && other.root != nil && t.comparator(t.max().key, other.min().key) >= 0 {
+		return JoinOrderError{}
+	}
+	t.root = merge(t.root, other.root)
+	other.root = nil
+	return nil
+}
+
+func (t *Treap) min() *node {
+	n := t.root
+	for n != nil && n.left != nil {
+		n = n.left
+	}
+	return n
+}
+
+func (t *Treap) max() *node {
+	n := t.root
+	for n != nil && n.right != nil {
+		n = n.right
+	}
+	return n
+}
+
+// Min returns the minimum key and its value
+func (t *Treap) Min() (key, value interface{}, found bool) {
+	if n := t.min(); n != nil {
+		return n.key, n.value, true
+	}
+	return nil, nil, false
+}
+
+// Max returns the maximum key and its value
+func (t *Treap) Max() (key, value interface{}, found bool) {
+	if n := t.max(); n != nil {
+		return n.key, n.value, true
+	}
+	return nil, nil, false
+}
+
+// At returns the i-th (0 based) smallest key and its value
+func (t *Treap) At(i int) (key, value interface{}, found bool) {
+	if i < 0 || i >= size(t.root) {
+		return nil, nil, false
+	}
+	n := t.root
+	for {
+		switch ls := size(n.left); {
+		case i < ls:
+			n = n.left
+		case i > ls:
+			i -= ls + 1
+			n = n.right
+		default:
+			return n.key, n.value, true
+		}
+	}
+}
+
+// Rank returns number of keys < k
+func (t *Treap) Rank(k interface{}) int {
+	rank := 0
+	n := t.root
+	for n != nil {
+		if t.comparator(n.key, k) < 0 {
+			rank += size(n.left) + 1
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return rank
+}
+
+// Height returns height of the treap, expected O(log n)
+func (t *Treap) Height() int {
+	var height func(n *node) int
+	height = func(n *node) int {
+		if n == nil {
+			return 0
+		}
+		l, r := height(n.left), height(n.right)
+		if l > r {
+			return l + 1
+		}
+		return r + 1
+	}
+	return height(t.root)
+}
+
+// Size returns number of keys inside the treap
+func (t *Treap) Size() int {
+	return size(t.root)
+}
+
+// Empty returns true if no key inside the treap
+func (t *Treap) Empty() bool {
+	return t.root == nil
+}
+
+// Clear clears the treap
+func (t *Treap) Clear() {
+	t.root = nil
+}
+
+// Keys returns keys in ascending order
+func (t *Treap) Keys() []interface{} {
+	keys := make([]interface{}, 0, t.Size())
+	t.inOrder(t.root, func(n *node) {
+		keys = append(keys, n.key)
+	})
+	return keys
+}
+
+// Values returns values in key ascending order
+func (t *Treap) Values() []interface{} {
+	values := make([]interface{}, 0, t.Size())
+	t.inOrder(t.root, func(n *node) {
+		values = append(values, n.value)
+	})
+	return values
+}
+
+func (t *Treap) inOrder(n *node, f func(n *node)) {
+	if n == nil {
+		return
+	}
+	t.inOrder(n.left, f)
+	f(n)
+	t.inOrder(n.right, f)
+}
+
+func (t *Treap) each(n *node, f func(value interface{}) bool) bool {
+	if n == nil {
+		return true
+	}
+	return t.each(n.left, f) && f(n.value) && t.each(n.right, f)
+}
+
+// Each visits values in key order until f returns false
+func (t *Treap) Each(f func(value interface{}) bool) {
+	t.each(t.root, f)
+}
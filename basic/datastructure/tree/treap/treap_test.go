@@ -0,0 +1,181 @@
+package treap
+
+import (
+	"godev/basic"
+	"godev/basic/datastructure/tree"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// check checks bst order, heap order and sizes
+func check(t *testing.T, tp *Treap) {
+	var walk func(n *node) int
+	walk = func(n *node) int {
+		if n == nil {
+			return 0
+		}
+		if n.left != nil && (n.left.priority > n.priority || tp.comparator(n.left.key, n.key) >= 0) {
+			t.Fatal("left")
+		}
+		if n.right != nil && (n.right.priority > n.priority || tp.comparator(n.right.key, n.key) <= 0) {
+			t.Fatal("right")
+		}
+		s := walk(n.left) + walk(n.right) + 1
+		if s != n.size {
+			t.Fatal("size")
+		}
+		return s
+	}
+	walk(tp.root)
+	keys := tp.Keys()
+	if !sort.SliceIsSorted(keys, func(i, j int) bool { return keys[i].(int) < keys[j].(int) }) {
+		t.Fatal("order")
+	}
+}
+
+func TestTreap(t *testing.T) {
+	var _ tree.Tree = (*Treap)(nil)
+
+	tp := NewTreap(basic.IntComparator)
+	if !tp.Empty() || tp.Size() != 0 || tp.Height() != 0 {
+		t.Fail()
+	}
+	if _, _, found := tp.Min(); found {
+		t.Fail()
+	}
+	if _, _, found := tp.At(0); found {
+		t.Fail()
+	}
+
+	ref := map[int]int{}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		k := r.Intn(1000)
+		if r.Intn(3) == 0 {
+			_, found := ref[k]
+			if tp.Delete(k) != found {
+				t.Fatal(i)
+			}
+			delete(ref, k)
+		} else {
+			tp.Set(k, i)
+			ref[k] = i
+		}
+	}
+	check(t, tp)
+	if tp.Size() != len(ref) || len(tp.Values()) != len(ref) {
+		t.Fail()
+	}
+	for k, v := range ref {
+		if got, found := tp.Get(k); !found || got.(int) != v {
+			t.Fatal(k)
+		}
+	}
+	if _, found := tp.Get(-1); found {
+		t.Fail()
+	}
+	// expected height is about 2-3 * log2(n)
+	if tp.Height() > 40 {
+		t.Fail()
+	}
+
+	keys := make([]int, 0, len(ref))
+	for k := range ref {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	for i, k := range keys {
+		if key, _, _ := tp.At(i); key.(int) != k || tp.Rank(k) != i {
+			t.Fatal(i)
+		}
+	}
+	if k, _, _ := tp.Min(); k.(int) != keys[0] {
+		t.Fail()
+	}
+	if k, _, _ := tp.Max(); k.(int) != keys[len(keys)-1] {
+		t.Fail()
+	}
+
+	tp.Clear()
+	if !tp.Empty() {
+		t.Fail()
+	}
+}
+
+func TestTreap_SplitJoin(t *testing.T) {
+	tp := NewTreap(basic.IntComparator)
+	for i := 0; i < 100; i++ {
+		tp.Set(i, i)
+	}
+
+	right := tp.Split(40)
+	check(t, tp)
+	check(t, right)
+	if tp.Size() != 40 || right.Size() != 60 {
+		t.Fail()
+	}
+	if k, _, _ := tp.Max(); k.(int) != 39 {
+		t.Fail()
+	}
+	if k, _, _ := right.Min(); k.(int) != 40 {
+		t.Fail()
+	}
+
+	// wrong order
+	if err := right.Join(tp); err == nil || err.Error() != (JoinOrderError{}).Error() || right.Size() != 60 || tp.Size() != 40 {
+		t.Fail()
+	}
+
+	tail := right.SplitAt(10)
+	if right.Size() != 10 || tail.Size() != 50 {
+		t.Fail()
+	}
+	if k, _, _ := tail.Min(); k.(int) != 50 {
+		t.Fail()
+	}
+
+	if tp.Join(right) != nil || tp.Join(tail) != nil || !right.Empty() || !tail.Empty() || tp.Size() != 100 {
+		t.Fail()
+	}
+	check(t, tp)
+	for i := 0; i < 100; i++ {
+		if v, found := tp.Get(i); !found || v.(int) != i {
+			t.Fatal(i)
+		}
+	}
+
+	// split beyond bounds
+	all := tp.Split(-1)
+	if !tp.Empty() || all.Size() != 100 {
+		t.Fail()
+	}
+	none := all.Split(1000)
+	if !none.Empty() || all.Size() != 100 {
+		t.Fail()
+	}
+	if err := none.Join(all); err != nil || none.Size() != 100 {
+		t.Fail()
+	}
+}
+
+func BenchmarkTreap_Set(b *testing.B) {
+	tp := NewTreap(basic.IntComparator)
+	data := rand.Perm(b.N)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tp.Set(data[i], i)
+	}
+}
+
+func BenchmarkTreap_SplitJoin(b *testing.B) {
+	tp := NewTreap(basic.IntComparator)
+	for i := 0; i < 100000; i++ {
+		tp.Set(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		right := tp.Split(i % 100000)
+		_ = tp.Join(right)
+	}
+}
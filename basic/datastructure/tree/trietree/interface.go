@@ -4,7 +4,7 @@ import "godev/basic"
 
 // Trie tree
 type Trie interface {
-	basic.Container
+	basic.Iterable
 
 	Get(key string) interface{}
 	Put(key string, value interface{}) (newlyCreated bool)
@@ -1,6 +1,7 @@
 package trietree
 
 import (
+	"errors"
 	"strings"
 )
 
@@ -163,6 +164,18 @@ func (t *trie) Values() []interface{} {
 	return values
 }
 
+// errStopWalk stops Walk inside Each
+var errStopWalk = errors.New("stop walk")
+
+func (t *trie) Each(f func(value interface{}) bool) {
+	_ = t.Walk(func(key string, value interface{}) error {
+		if !f(value) {
+			return errStopWalk
+		}
+		return nil
+	})
+}
+
 func (n *tNode) walk(key string, wf WalkFunc) error {
 	// skip internal created path
 	if n.value != nil {
@@ -0,0 +1,86 @@
+package basic
+
+// Map returns f applied to every value of container
+func Map(container Container, f func(value interface{}) interface{}) []interface{} {
+	res := make([]interface{}, 0, container.Size())
+	Each(container, func(value interface{}) bool {
+		res = append(res, f(value))
+		return true
+	})
+	return res
+}
+
+// Filter returns values of container which satisfy predicate
+func Filter(container Container, predicate func(value interface{}) bool) []interface{} {
+	var res []interface{}
+	Each(container, func(value interface{}) bool {
+		if predicate(value) {
+			res = append(res, value)
+		}
+		return true
+	})
+	return res
+}
+
+// Reduce folds values of container into initial with f
+func Reduce(container Container, initial interface{}, f func(acc, value interface{}) interface{}) interface{} {
+	acc := initial
+	Each(container, func(value interface{}) bool {
+		acc = f(acc, value)
+		return true
+	})
+	return acc
+}
+
+// Any returns true if at least one value satisfies predicate, it stops at the first match
+func Any(container Container, predicate func(value interface{}) bool) bool {
+	_, found := Find(container, predicate)
+	return found
+}
+
+// All returns true if every value satisfies predicate, it stops at the first mismatch
+//	All of an empty container is true
+func All(container Container, predicate func(value interface{}) bool) bool {
+	all := true
+	Each(container, func(value interface{}) bool {
+		all = predicate(value)
+		return all
+	})
+	return all
+}
+
+// Find returns the first value which satisfies predicate
+func Find(container Container, predicate func(value interface{}) bool) (value interface{}, found bool) {
+	Each(container, func(v interface{}) bool {
+		if predicate(v) {
+			value, found = v, true
+		}
+		return !found
+	})
+	return
+}
+
+// Chunk splits values of container into slices of size, the last one may be shorter
+//	size < 1 panics
+func Chunk(container Container, size int) [][]interface{} {
+	if size < 1 {
+		panic("basic: chunk size must be positive")
+	}
+	var res [][]interface{}
+	var chunk []interface{}
+	Each(container, func(value interface{}) bool {
+		if chunk == nil {
+			chunk = make([]interface{}, 0, size)
+		}
+		chunk = append(chunk, value)
+		if len(chunk) == size {
+			res = append(res, chunk)
+			chunk = nil
+		}
+		return true
+	})
+	if chunk != nil {
+		res = append(res, chunk)
+	}
+	return res
+}
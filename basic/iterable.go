@@ -0,0 +1,64 @@
+package basic
+
+// Iterator iterates over values of a container
+type Iterator interface {
+	HasNext() bool
+	Next() interface{}
+}
+
+// Iterable is a container which visits its values without copying them into a slice
+//	values are visited in the same order as Values() returns them, Each stops as soon as f returns false
+//	notice: the container must not be modified inside f
+type Iterable interface {
+	Container
+	Each(f func(value interface{}) bool)
+}
+
+// Each visits values of container
+//	it uses container's own Each if container is Iterable, otherwise it ranges over Values()
+func Each(container Container, f func(value interface{}) bool) {
+	if it, ok := container.(Iterable); ok {
+		it.Each(f)
+		return
+	}
+	for _, v := range container.Values() {
+		if !f(v) {
+			return
+		}
+	}
+}
+
+// NewIterator returns an iterator over values of container
+//	containers providing `Iterator() Iterator` are iterated directly, others over a snapshot of Values()
+func NewIterator(container Container) Iterator {
+	if it, ok := container.(interface{ Iterator() Iterator }); ok {
+		return it.Iterator()
+	}
+	return NewSliceIterator(container.Values())
+}
+
+// SliceIterator iterates over a slice of values
+type SliceIterator struct {
+	values []interface{}
+	cursor int
+}
+
+// NewSliceIterator returns iterator over values
+func NewSliceIterator(values []interface{}) *SliceIterator {
+	return &SliceIterator{values: values}
+}
+
+// HasNext returns true if there are values left
+func (iter *SliceIterator) HasNext() bool {
+	return iter.cursor < len(iter.values)
+}
+
+// Next returns next value, nil if no values left
+func (iter *SliceIterator) Next() interface{} {
+	if !iter.HasNext() {
+		return nil
+	}
+	v := iter.values[iter.cursor]
+	iter.cursor++
+	return v
+}
@@ -0,0 +1,321 @@
+package basic_test
+
+import (
+	"fmt"
+	"godev/basic"
+	"godev/basic/algorithm/topk"
+	"godev/basic/datastructure/bag"
+	"godev/basic/datastructure/bitmap/bitset"
+	"godev/basic/datastructure/bitmap/roaring"
+	"godev/basic/datastructure/heap"
+	"godev/basic/datastructure/heap/bheap"
+	"godev/basic/datastructure/heap/dary"
+	"godev/basic/datastructure/heap/fibonacci"
+	"godev/basic/datastructure/heap/minmax"
+	"godev/basic/datastructure/heap/pairing"
+	"godev/basic/datastructure/heap/radix"
+	"godev/basic/datastructure/list"
+	"godev/basic/datastructure/maps/treemap"
+	"godev/basic/datastructure/queue/deque"
+	"godev/basic/datastructure/queue/deque/concurrent"
+	"godev/basic/datastructure/queue/prque/pqbin"
+	"godev/basic/datastructure/queue/prque/pqfibo"
+	"godev/basic/datastructure/queue/prque/pqindexed"
+	"godev/basic/datastructure/set"
+	"godev/basic/datastructure/tree/avltree"
+	"godev/basic/datastructure/tree/bstree"
+	"godev/basic/datastructure/tree/btree"
+	"godev/basic/datastructure/tree/kdtree"
+	"godev/basic/datastructure/tree/rbtree"
+	"godev/basic/datastructure/tree/rtree"
+	"godev/basic/datastructure/tree/splaytree"
+	"godev/basic/datastructure/tree/treap"
+	"godev/basic/datastructure/tree/trietree"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type item int
+
+func (it item) Compare(ait heap.Item) int {
+	return basic.IntComparator(int(it), int(ait.(item)))
+}
+
+func (it item) Key() uint64 {
+	return uint64(it)
+}
+
+func toInt(v interface{}) int {
+	switch v := v.(type) {
+	case item:
+		return int(v)
+	default:
+		return v.(int)
+	}
+}
+
+// iterables returns every iterable container filled with a, and whether Values() order is deterministic
+func iterables(a []int) map[string]struct {
+	c       basic.Iterable
+	ordered bool
+} {
+	res := make(map[string]struct {
+		c       basic.Iterable
+		ordered bool
+	})
+	add := func(name string, c basic.Iterable, ordered bool) {
+		res[name] = struct {
+			c       basic.Iterable
+			ordered bool
+		}{c, ordered}
+	}
+
+	avl := avltree.NewAVLTree(basic.IntComparator)
+	bst := bstree.NewBSTree()
+	bst.Comparator = basic.IntComparator
+	bt := btree.NewBTree(3, basic.IntComparator)
+	kd := kdtree.NewKDTree(1)
+	rb := rbtree.NewRBTree(basic.IntComparator)
+	rt := rtree.NewRTree(2, 4)
+	st := splaytree.NewSplayTree(basic.IntComparator)
+	tp := treap.NewTreap(basic.IntComparator)
+	tr := trietree.New('/')
+	bh := &bheap.MinHeap{Comparator: basic.IntComparator}
+	bh.Init()
+	dh := dary.NewHeap(3)
+	fh := fibonacci.NewHeap()
+	mh := minmax.NewHeap()
+	ph := pairing.NewHeap()
+	rh := radix.NewHeap()
+	sl := list.NewSkipList(8, 4, basic.IntComparator)
+	dq := deque.NewDeque(4)
+	dqRW := concurrent.NewDequeRW(4)
+	pb := pqbin.NewPQBin()
+	pf := pqfibo.NewPQFib()
+	pi := pqindexed.NewPairing(basic.IntComparator)
+	b := bag.NewBag()
+	ob := bag.NewOrderedBag(basic.IntComparator)
+	hs := set.NewSet()
+	ts := set.NewTreeSet(basic.IntComparator)
+	is := set.NewIntSet(len(a))
+	tk := topk.New(10, basic.IntComparator)
+	bs := bitset.NewBitSet(uint(len(a)))
+	rm := roaring.NewBitmap()
+	for _, v := range a {
+		avl.Set(v, v)
+		bst.Insert(v)
+		bt.Insert(&btree.Item{Key: v, Value: v})
+		kd.Insert(kdtree.Point{float64(v)}, v)
+		rb.Insert(v, v)
+		rt.Insert(rtree.NewPointRect([]float64{float64(v), float64(-v)}), v)
+		_ = st.Insert(v, v)
+		tp.Set(v, v)
+		tr.Put(fmt.Sprintf("/%d", v), v)
+		bh.Push(v)
+		dh.Insert(item(v))
+		fh.Insert(item(v))
+		mh.Insert(item(v))
+		ph.Insert(item(v))
+		rh.Insert(item(v))
+		sl.Set(v, v)
+		dq.PushFront(v)
+		dqRW.PushBack(v)
+		pb.Push(v, v)
+		pf.Push(v, v)
+		pi.Push(v, v)
+		b.Add(v)
+		b.Add(v)
+		ob.Add(v)
+		hs.Add(v)
+		ts.Add(v)
+		is.Add(v)
+		tk.Push(v)
+		bs.Set(uint(v))
+		rm.Add(uint32(v))
+	}
+	add("avltree", avl, true)
+	add("bstree", bst, true)
+	add("btree", bt, true)
+	add("kdtree", kd, true)
+	add("rbtree", rb, true)
+	add("rtree", rt, true)
+	add("splaytree", st, true)
+	add("treap", tp, true)
+	add("trie", tr, false)
+	add("bheap", bh, true)
+	add("dary", dh, true)
+	add("fibonacci", fh, true)
+	add("minmax", mh, true)
+	add("pairing", ph, true)
+	add("radix", rh, true)
+	add("skiplist", sl, true)
+	add("deque", dq, true)
+	add("dequeRW", dqRW, true)
+	add("pqbin", pb, true)
+	add("pqfibo", pf, true)
+	add("pqindexed", pi, true)
+	add("bag", b, false)
+	add("orderedbag", ob, true)
+	add("hashset", hs, false)
+	add("treeset", ts, true)
+	add("intset", is, false)
+	add("topk", tk, true)
+	add("bitset", bs, true)
+	add("roaring", rm, true)
+	return res
+}
+
+func TestEach(t *testing.T) {
+	a := rand.Perm(100)
+	for name, tc := range iterables(a) {
+		var visited []interface{}
+		tc.c.Each(func(value interface{}) bool {
+			visited = append(visited, value)
+			return true
+		})
+		values := tc.c.Values()
+		if len(visited) == 0 || len(values) != len(visited) {
+			t.Fatal(name, len(visited), len(values))
+		}
+		if tc.ordered {
+			if !reflect.DeepEqual(visited, values) {
+				t.Fatal(name, visited, values)
+			}
+		} else {
+			x, y := make([]int, len(values)), make([]int, len(values))
+			for i := range values {
+				x[i], y[i] = toInt(visited[i]), toInt(values[i])
+			}
+			sort.Ints(x)
+			sort.Ints(y)
+			if !reflect.DeepEqual(x, y) {
+				t.Fatal(name, x, y)
+			}
+		}
+
+		// stops as soon as f returns false
+		cnt := 0
+		tc.c.Each(func(value interface{}) bool {
+			cnt++
+			return cnt < 7
+		})
+		if cnt != 7 {
+			t.Fatal(name, cnt)
+		}
+
+		// pull iteration, native or over a snapshot
+		var iterated []interface{}
+		for it := basic.NewIterator(tc.c); it.HasNext(); {
+			iterated = append(iterated, it.Next())
+		}
+		if tc.ordered && !reflect.DeepEqual(iterated, values) {
+			t.Fatal(name, iterated, values)
+		}
+	}
+}
+
+func TestEach_Empty(t *testing.T) {
+	for name, tc := range iterables(nil) {
+		tc.c.Each(func(value interface{}) bool {
+			t.Fatal(name)
+			return true
+		})
+		if basic.NewIterator(tc.c).HasNext() {
+			t.Fatal(name)
+		}
+	}
+}
+
+func TestEach_Values(t *testing.T) {
+	// non iterable container falls back to Values()
+	m := treemap.NewMap(basic.IntComparator)
+	for i := 0; i < 10; i++ {
+		m.Set(i, i*i)
+	}
+	var visited []interface{}
+	basic.Each(m, func(value interface{}) bool {
+		visited = append(visited, value)
+		return len(visited) < 5
+	})
+	if !reflect.DeepEqual(visited, []interface{}{0, 1, 4, 9, 16}) {
+		t.Fail()
+	}
+}
+
+func TestFunctional(t *testing.T) {
+	sl := list.NewSkipList(8, 4, basic.IntComparator)
+	for _, v := range rand.Perm(10) {
+		sl.Set(v, v)
+	}
+	even := func(value interface{}) bool {
+		return value.(int)%2 == 0
+	}
+
+	if res := basic.Map(sl, func(value interface{}) interface{} { return value.(int) * 10 }); !reflect.DeepEqual(res, []interface{}{0, 10, 20, 30, 40, 50, 60, 70, 80, 90}) {
+		t.Fatal(res)
+	}
+	if res := basic.Filter(sl, even); !reflect.DeepEqual(res, []interface{}{0, 2, 4, 6, 8}) {
+		t.Fatal(res)
+	}
+	if res := basic.Reduce(sl, 0, func(acc, value interface{}) interface{} { return acc.(int) + value.(int) }); res.(int) != 45 {
+		t.Fatal(res)
+	}
+	if !basic.Any(sl, even) || basic.All(sl, even) {
+		t.Fail()
+	}
+	if basic.Any(sl, func(value interface{}) bool { return value.(int) > 9 }) || !basic.All(sl, func(value interface{}) bool { return value.(int) < 10 }) {
+		t.Fail()
+	}
+	if v, found := basic.Find(sl, func(value interface{}) bool { return value.(int) > 6 }); !found || v.(int) != 7 {
+		t.Fail()
+	}
+	if _, found := basic.Find(sl, func(value interface{}) bool { return value.(int) < 0 }); found {
+		t.Fail()
+	}
+	if res := basic.Chunk(sl, 4); !reflect.DeepEqual(res, [][]interface{}{{0, 1, 2, 3}, {4, 5, 6, 7}, {8, 9}}) {
+		t.Fatal(res)
+	}
+	if res := basic.Chunk(sl, 5); len(res) != 2 || len(res[1]) != 5 {
+		t.Fatal(res)
+	}
+
+	// empty container
+	sl.Clear()
+	if basic.Map(sl, nil) == nil || basic.Filter(sl, even) != nil || basic.Chunk(sl, 3) != nil || basic.Any(sl, even) || !basic.All(sl, even) {
+		t.Fail()
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fail()
+		}
+	}()
+	basic.Chunk(sl, 0)
+}
+
+// BenchmarkEach compares Each with ranging over Values()
+func BenchmarkEach(b *testing.B) {
+	tp := treap.NewTreap(basic.IntComparator)
+	for _, v := range rand.Perm(10000) {
+		tp.Set(v, v)
+	}
+	b.Run("Each", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sum := 0
+			tp.Each(func(value interface{}) bool {
+				sum += value.(int)
+				return true
+			})
+		}
+	})
+	b.Run("Values", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sum := 0
+			for _, v := range tp.Values() {
+				sum += v.(int)
+			}
+		}
+	})
+}
@@ -0,0 +1,275 @@
+package bptree
+
+import (
+	"errors"
+	"godev/storage/mmap"
+	"io"
+	"os"
+	"sync"
+)
+
+// B+tree stored in a single file, accessed through mmap
+//	https://en.wikipedia.org/wiki/B%2B_tree
+//
+// file layout: fixed size pages, page 0 is meta page, others are leaf / branch / free pages
+//	leaves are linked with next / prev sibling for range scans,
+//	freed pages are chained from meta and reused before file grows,
+//	commits are atomic and durable through a write-ahead log file beside the data file (see wal.go)
+//
+// keys are ordered by bytes.Compare, one writer and multiple readers are allowed at the same time
+
+// page size limits
+const (
+	MinPageSize = 512
+	MaxPageSize = 1 << 16
+)
+
+// errors
+var (
+	ErrClosed        = errors.New("bptree: tree closed")
+	ErrCorrupted     = errors.New("bptree: file corrupted")
+	ErrPageSize      = errors.New("bptree: page size should be power of 2 in [512, 65536]")
+	ErrTxNotWritable = errors.New("bptree: tx not writable")
+	ErrKeyRequired   = errors.New("bptree: key required")
+	ErrTooLarge      = errors.New("bptree: key value pair too large for page size")
+)
+
+// Options of Open
+type Options struct {
+	// PageSize is used when creating a new file, ignored for existing files
+	//	default is os page size
+	PageSize int
+}
+
+// Tree is a disk-backed B+tree
+type Tree struct {
+	sync.RWMutex
+	path string
+	mmap *mmap.MMap
+	wal  *os.File
+	meta meta
+	// failure on applying committed pages, the tree must be reopened to recover from wal
+	err error
+}
+
+// Open opens or creates a tree file at path, committed but not applied changes are recovered from wal
+func Open(path string, options *Options) (*Tree, error) {
+	pageSize := os.Getpagesize()
+	if options != nil && options.PageSize != 0 {
+		pageSize = options.PageSize
+	}
+	if !validPageSize(pageSize) {
+		return nil, ErrPageSize
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0666)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	wal, err := os.OpenFile(path+walSuffix, os.O_CREATE|os.O_RDWR, 0666)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tree{path: path, wal: wal}
+	if err := t.open(f, pageSize); err != nil {
+		_ = wal.Close()
+		if t.mmap != nil {
+			_ = t.mmap.Close()
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Tree) open(f *os.File, pageSize int) error {
+	if err := recoverWAL(f, t.wal); err != nil {
+		return err
+	}
+	buf := make([]byte, metaSize)
+	n, err := f.ReadAt(buf, 0)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if n == 0 || isZero(buf) {
+		// new file, or crashed before the first commit
+		return t.create(f, pageSize)
+	}
+	if n < metaSize {
+		return ErrCorrupted
+	}
+	if t.meta, err = decodeMeta(buf); err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.Size() < int64(t.meta.pageCount)*int64(t.meta.pageSize) {
+		return ErrCorrupted
+	}
+	t.mmap, err = mmap.New(t.path, int(fi.Size()), mmap.RDWR, 0, 0)
+	return err
+}
+
+func isZero(buf []byte) bool {
+	for _, b := range buf {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// create initializes meta page and an empty root leaf
+func (t *Tree) create(f *os.File, pageSize int) error {
+	if err := f.Truncate(int64(2 * pageSize)); err != nil {
+		return err
+	}
+	var err error
+	if t.mmap, err = mmap.New(t.path, 2*pageSize, mmap.RDWR, 0, 0); err != nil {
+		return err
+	}
+	t.meta = meta{pageSize: uint32(pageSize), pageCount: 1}
+	tx := newTx(t, true)
+	if tx.meta.root, err = tx.allocate(&node{kind: leafPage}); err != nil {
+		return err
+	}
+	return tx.commit()
+}
+
+// write commits pages through wal, then applies them on the mapped file
+func (t *Tree) write(m meta, pages []walPage) error {
+	if err := writeWAL(t.wal, int(m.pageSize), pages); err != nil {
+		return err
+	}
+	// committed, failure from now on breaks the tree until it is reopened
+	if err := t.apply(m, pages); err != nil {
+		t.err = err
+		return err
+	}
+	t.meta = m
+	if err := t.wal.Truncate(0); err != nil {
+		t.err = err
+		return err
+	}
+	return nil
+}
+
+func (t *Tree) apply(m meta, pages []walPage) error {
+	size := int(m.pageCount) * int(m.pageSize)
+	if size > t.mmap.Len() {
+		// grow file by doubling to reduce remapping
+		if double := 2 * t.mmap.Len(); size < double {
+			size = double
+		}
+		if err := t.mmap.Close(); err != nil {
+			return err
+		}
+		if err := os.Truncate(t.path, int64(size)); err != nil {
+			return err
+		}
+		var err error
+		if t.mmap, err = mmap.New(t.path, size, mmap.RDWR, 0, 0); err != nil {
+			return err
+		}
+	}
+	for _, p := range pages {
+		if _, err := t.mmap.WriteAt(p.data, int64(p.id)*int64(m.pageSize)); err != nil {
+			return err
+		}
+	}
+	return t.mmap.Sync()
+}
+
+// Close closes the tree
+func (t *Tree) Close() error {
+	t.Lock()
+	defer t.Unlock()
+	if t.mmap == nil {
+		return nil
+	}
+	err := t.mmap.Close()
+	if e := t.wal.Close(); err == nil {
+		err = e
+	}
+	t.mmap = nil
+	return err
+}
+
+func (t *Tree) check() error {
+	if t.mmap == nil {
+		return ErrClosed
+	}
+	return t.err
+}
+
+// View runs f inside a read-only transaction
+func (t *Tree) View(f func(tx *Tx) error) error {
+	t.RLock()
+	defer t.RUnlock()
+	if err := t.check(); err != nil {
+		return err
+	}
+	return f(newTx(t, false))
+}
+
+// Update runs f inside a writable transaction, changes are committed if f returns nil, otherwise discarded
+func (t *Tree) Update(f func(tx *Tx) error) error {
+	t.Lock()
+	defer t.Unlock()
+	if err := t.check(); err != nil {
+		return err
+	}
+	tx := newTx(t, true)
+	if err := f(tx); err != nil {
+		return err
+	}
+	return tx.commit()
+}
+
+// Len returns number of keys inside the tree
+func (t *Tree) Len() int {
+	t.RLock()
+	defer t.RUnlock()
+	return int(t.meta.count)
+}
+
+// Get returns value of key
+func (t *Tree) Get(key []byte) (value []byte, found bool, err error) {
+	err = t.View(func(tx *Tx) error {
+		value, found, err = tx.Get(key)
+		return err
+	})
+	return
+}
+
+// Put sets value of key in its own transaction
+func (t *Tree) Put(key, value []byte) error {
+	return t.Update(func(tx *Tx) error {
+		return tx.Put(key, value)
+	})
+}
+
+// Delete deletes key in its own transaction, returns false if key not found
+func (t *Tree) Delete(key []byte) (deleted bool, err error) {
+	err = t.Update(func(tx *Tx) error {
+		deleted, err = tx.Delete(key)
+		return err
+	})
+	return
+}
+
+// AscendRange calls f on key value pairs with key in range [start, end) in ascending order, see Tx.AscendRange
+func (t *Tree) AscendRange(start, end []byte, f func(key, value []byte) bool) error {
+	return t.View(func(tx *Tx) error {
+		return tx.AscendRange(start, end, f)
+	})
+}
+
+// DescendRange calls f on key value pairs with key in range (end, start] in descending order, see Tx.DescendRange
+func (t *Tree) DescendRange(start, end []byte, f func(key, value []byte) bool) error {
+	return t.View(func(tx *Tx) error {
+		return tx.DescendRange(start, end, f)
+	})
+}
@@ -0,0 +1,419 @@
+package bptree
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func tempPath(t testing.TB) (string, func()) {
+	dir, err := ioutil.TempDir("", "bptree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(dir, "tree.db"), func() {
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func key(i int) []byte {
+	return []byte(fmt.Sprintf("key-%06d", i))
+}
+
+// checkTree checks keys order, separators, leaves depth, sibling links and page accounting
+func checkTree(t *testing.T, tree *Tree) {
+	err := tree.View(func(tx *Tx) error {
+		used := map[pgid]bool{metaPageID: true}
+		var leaves []pgid
+		leafDepth := -1
+		count := 0
+		var walk func(id pgid, lo, hi []byte, depth int)
+		walk = func(id pgid, lo, hi []byte, depth int) {
+			n, err := tx.node(id)
+			assert.NoError(t, err)
+			assert.False(t, used[id], "page used twice")
+			used[id] = true
+			assert.True(t, n.size() <= tx.pageSize())
+			for i, k := range n.keys {
+				assert.True(t, lo == nil || bytes.Compare(k, lo) >= 0)
+				assert.True(t, hi == nil || bytes.Compare(k, hi) < 0)
+				assert.True(t, i == 0 || bytes.Compare(n.keys[i-1], k) < 0)
+			}
+			if n.isLeaf() {
+				if leafDepth == -1 {
+					leafDepth = depth
+				}
+				assert.Equal(t, leafDepth, depth)
+				leaves = append(leaves, id)
+				count += len(n.keys)
+				return
+			}
+			assert.Equal(t, branchPage, n.kind)
+			assert.Equal(t, len(n.keys)+1, len(n.children))
+			if id != tx.meta.root {
+				assert.True(t, len(n.keys) > 0)
+			}
+			for i, c := range n.children {
+				clo, chi := lo, hi
+				if i > 0 {
+					clo = n.keys[i-1]
+				}
+				if i < len(n.keys) {
+					chi = n.keys[i]
+				}
+				walk(c, clo, chi, depth+1)
+			}
+		}
+		walk(tx.meta.root, nil, nil, 0)
+		assert.Equal(t, tx.Len(), count)
+
+		// sibling links
+		for i, id := range leaves {
+			n, _ := tx.node(id)
+			if i > 0 {
+				assert.Equal(t, leaves[i-1], n.prev)
+			} else {
+				assert.Equal(t, pgid(0), n.prev)
+			}
+			if i < len(leaves)-1 {
+				assert.Equal(t, leaves[i+1], n.next)
+			} else {
+				assert.Equal(t, pgid(0), n.next)
+			}
+		}
+
+		// every page is either used or free
+		for id := tx.meta.freeHead; id != 0; {
+			assert.False(t, used[id], "free page in use")
+			used[id] = true
+			n, err := tx.node(id)
+			assert.NoError(t, err)
+			assert.Equal(t, freePage, n.kind)
+			id = n.next
+		}
+		assert.Equal(t, int(tx.meta.pageCount), len(used))
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestTree(t *testing.T) {
+	path, clean := tempPath(t)
+	defer clean()
+
+	_, err := Open(path, &Options{PageSize: 1000})
+	assert.Equal(t, ErrPageSize, err)
+
+	tree, err := Open(path, &Options{PageSize: 512})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, tree.Len())
+	_, found, err := tree.Get([]byte("a"))
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.NoError(t, tree.Put([]byte("a"), []byte("1")))
+	assert.NoError(t, tree.Put([]byte("b"), []byte("2")))
+	assert.NoError(t, tree.Put([]byte("a"), []byte("3")))
+	assert.Equal(t, 2, tree.Len())
+	v, found, err := tree.Get([]byte("a"))
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("3"), v)
+
+	deleted, err := tree.Delete([]byte("b"))
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	deleted, err = tree.Delete([]byte("b"))
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+
+	assert.Equal(t, ErrKeyRequired, tree.Put(nil, []byte("x")))
+	assert.Equal(t, ErrTooLarge, tree.Put([]byte("x"), make([]byte, 512)))
+	assert.Equal(t, ErrTxNotWritable, tree.View(func(tx *Tx) error {
+		return tx.Put([]byte("x"), nil)
+	}))
+
+	// rollback when f returns error
+	rollback := fmt.Errorf("rollback")
+	assert.Equal(t, rollback, tree.Update(func(tx *Tx) error {
+		assert.NoError(t, tx.Put([]byte("c"), []byte("4")))
+		_, err := tx.Delete([]byte("a"))
+		assert.NoError(t, err)
+		return rollback
+	}))
+	assert.Equal(t, 1, tree.Len())
+	_, found, _ = tree.Get([]byte("c"))
+	assert.False(t, found)
+
+	// reopen, page size is read from file
+	assert.NoError(t, tree.Close())
+	assert.NoError(t, tree.Close())
+	assert.Equal(t, ErrClosed, tree.Put([]byte("x"), nil))
+	tree, err = Open(path, nil)
+	assert.NoError(t, err)
+	defer tree.Close()
+	assert.Equal(t, uint32(512), tree.meta.pageSize)
+	v, found, err = tree.Get([]byte("a"))
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("3"), v)
+	checkTree(t, tree)
+}
+
+func TestTree_Random(t *testing.T) {
+	path, clean := tempPath(t)
+	defer clean()
+
+	tree, err := Open(path, &Options{PageSize: 512})
+	assert.NoError(t, err)
+	r := rand.New(rand.NewSource(1))
+	ref := map[string][]byte{}
+
+	for round := 0; round < 50; round++ {
+		err := tree.Update(func(tx *Tx) error {
+			for i := 0; i < 100; i++ {
+				k := key(r.Intn(2000))
+				if r.Intn(3) == 0 {
+					_, found := ref[string(k)]
+					deleted, err := tx.Delete(k)
+					assert.NoError(t, err)
+					assert.Equal(t, found, deleted)
+					delete(ref, string(k))
+				} else {
+					// variable size values make splits uneven
+					v := bytes.Repeat([]byte{byte(i)}, r.Intn(100))
+					assert.NoError(t, tx.Put(k, v))
+					ref[string(k)] = v
+				}
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		if round%10 == 9 {
+			checkTree(t, tree)
+			// reopen
+			assert.NoError(t, tree.Close())
+			tree, err = Open(path, nil)
+			assert.NoError(t, err)
+		}
+	}
+	checkTree(t, tree)
+
+	assert.Equal(t, len(ref), tree.Len())
+	for k, v := range ref {
+		got, found, err := tree.Get([]byte(k))
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, v, got)
+	}
+
+	// delete all, pages go to free list and are reused
+	pageCount := tree.meta.pageCount
+	for k := range ref {
+		deleted, err := tree.Delete([]byte(k))
+		assert.NoError(t, err)
+		assert.True(t, deleted)
+	}
+	assert.Equal(t, 0, tree.Len())
+	checkTree(t, tree)
+	assert.NotEqual(t, pgid(0), tree.meta.freeHead)
+	for k, v := range ref {
+		assert.NoError(t, tree.Put([]byte(k), v))
+	}
+	checkTree(t, tree)
+	assert.True(t, tree.meta.pageCount <= pageCount)
+	assert.NoError(t, tree.Close())
+}
+
+func TestTree_VariableKeys(t *testing.T) {
+	path, clean := tempPath(t)
+	defer clean()
+
+	tree, err := Open(path, &Options{PageSize: 512})
+	assert.NoError(t, err)
+	defer tree.Close()
+	// long separators replacing short ones may overflow parents when entries are redistributed
+	r := rand.New(rand.NewSource(1))
+	ref := map[string]bool{}
+	keys := make([][]byte, 400)
+	for i := range keys {
+		keys[i] = make([]byte, 1+r.Intn(110))
+		r.Read(keys[i])
+	}
+
+	for round := 0; round < 3000 && !t.Failed(); round++ {
+		k := keys[r.Intn(len(keys))]
+		if r.Intn(2) == 0 {
+			deleted, err := tree.Delete(k)
+			assert.NoError(t, err)
+			assert.Equal(t, ref[string(k)], deleted)
+			delete(ref, string(k))
+		} else {
+			assert.NoError(t, tree.Put(k, make([]byte, r.Intn(8))))
+			ref[string(k)] = true
+		}
+		checkTree(t, tree)
+	}
+	assert.Equal(t, len(ref), tree.Len())
+	for k := range ref {
+		_, found, err := tree.Get([]byte(k))
+		assert.NoError(t, err)
+		assert.True(t, found)
+	}
+}
+
+func TestTree_Range(t *testing.T) {
+	path, clean := tempPath(t)
+	defer clean()
+
+	tree, err := Open(path, &Options{PageSize: 512})
+	assert.NoError(t, err)
+	defer tree.Close()
+
+	assert.NoError(t, tree.Update(func(tx *Tx) error {
+		// even numbers
+		for i := 0; i < 1000; i += 2 {
+			if err := tx.Put(key(i), key(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	collect := func(scan func(start, end []byte, f func(k, v []byte) bool) error, start, end []byte, limit int) []string {
+		var keys []string
+		assert.NoError(t, scan(start, end, func(k, v []byte) bool {
+			assert.Equal(t, k, v)
+			keys = append(keys, string(k))
+			return len(keys) < limit
+		}))
+		return keys
+	}
+
+	all := collect(tree.AscendRange, nil, nil, 1<<30)
+	assert.Equal(t, 500, len(all))
+	assert.True(t, sort.StringsAreSorted(all))
+
+	keys := collect(tree.AscendRange, key(101), key(111), 1<<30)
+	assert.Equal(t, []string{string(key(102)), string(key(104)), string(key(106)), string(key(108)), string(key(110))}, keys)
+	keys = collect(tree.AscendRange, key(990), nil, 1<<30)
+	assert.Equal(t, 5, len(keys))
+	keys = collect(tree.AscendRange, key(0), nil, 3)
+	assert.Equal(t, 3, len(keys))
+
+	keys = collect(tree.DescendRange, key(111), key(101), 1<<30)
+	assert.Equal(t, []string{string(key(110)), string(key(108)), string(key(106)), string(key(104)), string(key(102))}, keys)
+	keys = collect(tree.DescendRange, nil, nil, 1<<30)
+	assert.Equal(t, 500, len(keys))
+	assert.Equal(t, string(key(998)), keys[0])
+	keys = collect(tree.DescendRange, key(5), nil, 1<<30)
+	assert.Equal(t, []string{string(key(4)), string(key(2)), string(key(0))}, keys)
+	keys = collect(tree.DescendRange, []byte("a"), nil, 1<<30)
+	assert.Equal(t, 0, len(keys))
+}
+
+func TestTree_Recover(t *testing.T) {
+	path, clean := tempPath(t)
+	defer clean()
+
+	tree, err := Open(path, &Options{PageSize: 512})
+	assert.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, tree.Put(key(i), key(i)))
+	}
+
+	// crash after wal is written, before pages are applied
+	crash := func(f func(tx *Tx)) {
+		tx := newTx(tree, true)
+		f(tx)
+		assert.NoError(t, writeWAL(tree.wal, tx.pageSize(), tx.pages()))
+		assert.NoError(t, tree.Close())
+	}
+	crash(func(tx *Tx) {
+		for i := 100; i < 200; i++ {
+			assert.NoError(t, tx.Put(key(i), key(i)))
+		}
+	})
+	tree, err = Open(path, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, tree.Len())
+	checkTree(t, tree)
+
+	// crash while writing wal, transaction is rolled back
+	crash(func(tx *Tx) {
+		for i := 0; i < 200; i++ {
+			_, err := tx.Delete(key(i))
+			assert.NoError(t, err)
+		}
+	})
+	fi, err := os.Stat(path + walSuffix)
+	assert.NoError(t, err)
+	assert.NoError(t, os.Truncate(path+walSuffix, fi.Size()-1))
+	tree, err = Open(path, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, tree.Len())
+	checkTree(t, tree)
+
+	// corrupted meta
+	assert.NoError(t, tree.Close())
+	m, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	m[20]++
+	assert.NoError(t, ioutil.WriteFile(path, m, 0666))
+	_, err = Open(path, nil)
+	assert.Equal(t, ErrCorrupted, err)
+}
+
+func TestTree_ReadError(t *testing.T) {
+	path, clean := tempPath(t)
+	defer clean()
+
+	tree, err := Open(path, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, tree.Put(key(1), key(1)))
+	assert.NoError(t, tree.Close())
+	before, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+
+	// reads of a write only file fail, the database must not be created again
+	f, err := os.OpenFile(path, os.O_WRONLY, 0666)
+	assert.NoError(t, err)
+	wal, err := os.OpenFile(path+walSuffix, os.O_RDWR, 0666)
+	assert.NoError(t, err)
+	tree = &Tree{path: path, wal: wal}
+	assert.Error(t, tree.open(f, 512))
+	assert.NoError(t, f.Close())
+	assert.NoError(t, wal.Close())
+	after, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, before, after)
+}
+
+func BenchmarkTree_Put(b *testing.B) {
+	path, clean := tempPath(b)
+	defer clean()
+
+	tree, err := Open(path, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer tree.Close()
+	b.ResetTimer()
+	// batch puts to amortize fsync
+	for i := 0; i < b.N; i += 1000 {
+		_ = tree.Update(func(tx *Tx) error {
+			for j := i; j < i+1000 && j < b.N; j++ {
+				if err := tx.Put(key(rand.Intn(1<<20)), key(j)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package bptree
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"sort"
+)
+
+// pgid is page id, offset of page inside file = pgid * pageSize
+type pgid uint64
+
+const (
+	magic   = 0x42505431 // "BPT1"
+	version = 1
+
+	// page 0 is meta page, root of a new tree is page 1
+	metaPageID pgid = 0
+
+	metaSize = 52
+
+	// page header: kind(1) pad(1) count(2) pad(4) next(8) prev(8)
+	pageHeaderSize = 24
+	// leaf entry: key length(2) value length(2) key value
+	leafEntryHeaderSize = 4
+	// branch entry: key length(2) key child(8), child 0 is stored right after page header
+	branchEntryHeaderSize = 10
+
+	leafPage   byte = 1
+	branchPage byte = 2
+	freePage   byte = 3
+)
+
+// meta page records the root of tree and page allocation state
+type meta struct {
+	pageSize  uint32
+	root      pgid
+	freeHead  pgid // head of free pages chain, 0 if no free page
+	pageCount pgid // high water mark, pages [0, pageCount) are in use or free
+	count     uint64
+}
+
+func (m *meta) encode(buf []byte) {
+	binary.LittleEndian.PutUint32(buf[0:], magic)
+	binary.LittleEndian.PutUint32(buf[4:], version)
+	binary.LittleEndian.PutUint32(buf[8:], m.pageSize)
+	binary.LittleEndian.PutUint32(buf[12:], 0)
+	binary.LittleEndian.PutUint64(buf[16:], uint64(m.root))
+	binary.LittleEndian.PutUint64(buf[24:], uint64(m.freeHead))
+	binary.LittleEndian.PutUint64(buf[32:], uint64(m.pageCount))
+	binary.LittleEndian.PutUint64(buf[40:], m.count)
+	binary.LittleEndian.PutUint32(buf[48:], crc32.ChecksumIEEE(buf[:48]))
+}
+
+func decodeMeta(buf []byte) (meta, error) {
+	if len(buf) < metaSize ||
+		binary.LittleEndian.Uint32(buf[0:]) != magic ||
+		binary.LittleEndian.Uint32(buf[4:]) != version ||
+		binary.LittleEndian.Uint32(buf[48:]) != crc32.ChecksumIEEE(buf[:48]) {
+		return meta{}, ErrCorrupted
+	}
+	m := meta{
+		pageSize:  binary.LittleEndian.Uint32(buf[8:]),
+		root:      pgid(binary.LittleEndian.Uint64(buf[16:])),
+		freeHead:  pgid(binary.LittleEndian.Uint64(buf[24:])),
+		pageCount: pgid(binary.LittleEndian.Uint64(buf[32:])),
+		count:     binary.LittleEndian.Uint64(buf[40:]),
+	}
+	if !validPageSize(int(m.pageSize)) || m.root == metaPageID || m.root >= m.pageCount {
+		return meta{}, ErrCorrupted
+	}
+	return m, nil
+}
+
+func validPageSize(size int) bool {
+	return size >= MinPageSize && size <= MaxPageSize && size&(size-1) == 0
+}
+
+// node is decoded page
+//	leaf: keys / values, next / prev are sibling leaves
+//	branch: keys / children, len(children) == len(keys) + 1,
+//		keys[i] is the minimum key of sub-tree children[i+1]
+//	free: next is the next free page
+type node struct {
+	kind       byte
+	keys       [][]byte
+	values     [][]byte
+	children   []pgid
+	next, prev pgid
+}
+
+func (n *node) isLeaf() bool {
+	return n.kind == leafPage
+}
+
+// size returns encoded size of the node
+func (n *node) size() int {
+	size := pageHeaderSize
+	if n.isLeaf() {
+		for i := range n.keys {
+			size += leafEntryHeaderSize + len(n.keys[i]) + len(n.values[i])
+		}
+		return size
+	}
+	if n.kind == branchPage {
+		size += 8
+		for i := range n.keys {
+			size += branchEntryHeaderSize + len(n.keys[i])
+		}
+	}
+	return size
+}
+
+// entrySize returns encoded size of the i-th entry
+func (n *node) entrySize(i int) int {
+	if n.isLeaf() {
+		return leafEntryHeaderSize + len(n.keys[i]) + len(n.values[i])
+	}
+	return branchEntryHeaderSize + len(n.keys[i])
+}
+
+// search returns index of the first key >= key in leaf,
+//	or index of the child which may contain key in branch
+func (n *node) search(key []byte) (index int, found bool) {
+	if n.isLeaf() {
+		index = sort.Search(len(n.keys), func(i int) bool {
+			return bytes.Compare(n.keys[i], key) >= 0
+		})
+		return index, index < len(n.keys) && bytes.Equal(n.keys[index], key)
+	}
+	index = sort.Search(len(n.keys), func(i int) bool {
+		return bytes.Compare(n.keys[i], key) > 0
+	})
+	return index, false
+}
+
+// encode writes node into buf which has length of page size
+func (n *node) encode(buf []byte) {
+	for i := range buf {
+		buf[i] = 0
+	}
+	buf[0] = n.kind
+	binary.LittleEndian.PutUint16(buf[2:], uint16(len(n.keys)))
+	binary.LittleEndian.PutUint64(buf[8:], uint64(n.next))
+	binary.LittleEndian.PutUint64(buf[16:], uint64(n.prev))
+	off := pageHeaderSize
+	switch n.kind {
+	case leafPage:
+		for i := range n.keys {
+			binary.LittleEndian.PutUint16(buf[off:], uint16(len(n.keys[i])))
+			binary.LittleEndian.PutUint16(buf[off+2:], uint16(len(n.values[i])))
+			off += leafEntryHeaderSize
+			off += copy(buf[off:], n.keys[i])
+			off += copy(buf[off:], n.values[i])
+		}
+	case branchPage:
+		binary.LittleEndian.PutUint64(buf[off:], uint64(n.children[0]))
+		off += 8
+		for i := range n.keys {
+			binary.LittleEndian.PutUint16(buf[off:], uint16(len(n.keys[i])))
+			off += 2
+			off += copy(buf[off:], n.keys[i])
+			binary.LittleEndian.PutUint64(buf[off:], uint64(n.children[i+1]))
+			off += 8
+		}
+	}
+}
+
+// decodeNode decodes page, keys and values are copied so that the node is still valid after remapping
+func decodeNode(buf []byte) (*node, error) {
+	n := &node{
+		kind: buf[0],
+		next: pgid(binary.LittleEndian.Uint64(buf[8:])),
+		prev: pgid(binary.LittleEndian.Uint64(buf[16:])),
+	}
+	count := int(binary.LittleEndian.Uint16(buf[2:]))
+	off := pageHeaderSize
+	// read returns next l bytes, nil if out of page
+	read := func(l int) []byte {
+		if off+l > len(buf) {
+			return nil
+		}
+		b := buf[off : off+l]
+		off += l
+		return b
+	}
+	switch n.kind {
+	case leafPage:
+		n.keys = make([][]byte, count)
+		n.values = make([][]byte, count)
+		for i := 0; i < count; i++ {
+			h := read(leafEntryHeaderSize)
+			if h == nil {
+				return nil, ErrCorrupted
+			}
+			k := read(int(binary.LittleEndian.Uint16(h)))
+			v := read(int(binary.LittleEndian.Uint16(h[2:])))
+			if k == nil || v == nil {
+				return nil, ErrCorrupted
+			}
+			n.keys[i] = append([]byte{}, k...)
+			n.values[i] = append([]byte{}, v...)
+		}
+	case branchPage:
+		n.keys = make([][]byte, count)
+		n.children = make([]pgid, count+1)
+		c := read(8)
+		if c == nil {
+			return nil, ErrCorrupted
+		}
+		n.children[0] = pgid(binary.LittleEndian.Uint64(c))
+		for i := 0; i < count; i++ {
+			h := read(2)
+			if h == nil {
+				return nil, ErrCorrupted
+			}
+			k := read(int(binary.LittleEndian.Uint16(h)))
+			c := read(8)
+			if k == nil || c == nil {
+				return nil, ErrCorrupted
+			}
+			n.keys[i] = append([]byte{}, k...)
+			n.children[i+1] = pgid(binary.LittleEndian.Uint64(c))
+		}
+	case freePage:
+	default:
+		return nil, ErrCorrupted
+	}
+	return n, nil
+}
+
+// splitIndex returns index to split entries of n into two parts of similar encoded size
+//	leaf: [0, index) and [index, len), branch: [0, index), index goes up, (index, len)
+func (n *node) splitIndex() int {
+	total := 0
+	for i := range n.keys {
+		total += n.entrySize(i)
+	}
+	lo, hi := 1, len(n.keys)-1
+	if !n.isLeaf() {
+		hi = len(n.keys) - 2
+	}
+	acc, index := 0, 0
+	for index < len(n.keys) && acc*2 < total {
+		acc += n.entrySize(index)
+		index++
+	}
+	if index < lo {
+		index = lo
+	}
+	if index > hi {
+		index = hi
+	}
+	return index
+}
@@ -0,0 +1,454 @@
+package bptree
+
+import (
+	"bytes"
+	"sort"
+)
+
+// Tx is a transaction on the tree, it is only valid inside function passed to Tree.View / Tree.Update
+//	changes of a writable transaction are kept in memory and committed atomically when the function returns nil
+type Tx struct {
+	tree     *Tree
+	writable bool
+	meta     meta
+	// decoded pages, modified pages are marked in dirty
+	nodes map[pgid]*node
+	dirty map[pgid]bool
+}
+
+func newTx(tree *Tree, writable bool) *Tx {
+	return &Tx{
+		tree:     tree,
+		writable: writable,
+		meta:     tree.meta,
+		nodes:    make(map[pgid]*node),
+		dirty:    make(map[pgid]bool),
+	}
+}
+
+func (tx *Tx) pageSize() int {
+	return int(tx.meta.pageSize)
+}
+
+// node returns decoded page
+func (tx *Tx) node(id pgid) (*node, error) {
+	if n, found := tx.nodes[id]; found {
+		return n, nil
+	}
+	if id == metaPageID || id >= tx.meta.pageCount {
+		return nil, ErrCorrupted
+	}
+	buf := make([]byte, tx.pageSize())
+	if _, err := tx.tree.mmap.ReadAt(buf, int64(id)*int64(tx.pageSize())); err != nil {
+		return nil, err
+	}
+	n, err := decodeNode(buf)
+	if err != nil {
+		return nil, err
+	}
+	tx.nodes[id] = n
+	return n, nil
+}
+
+func (tx *Tx) markDirty(ids ...pgid) {
+	for _, id := range ids {
+		tx.dirty[id] = true
+	}
+}
+
+// allocate returns a page for node n, free pages are reused first
+func (tx *Tx) allocate(n *node) (pgid, error) {
+	var id pgid
+	if tx.meta.freeHead != 0 {
+		id = tx.meta.freeHead
+		free, err := tx.node(id)
+		if err != nil {
+			return 0, err
+		}
+		if free.kind != freePage {
+			return 0, ErrCorrupted
+		}
+		tx.meta.freeHead = free.next
+	} else {
+		id = tx.meta.pageCount
+		tx.meta.pageCount++
+	}
+	tx.nodes[id] = n
+	tx.markDirty(id)
+	return id, nil
+}
+
+// free puts page into free pages chain
+func (tx *Tx) free(id pgid) {
+	tx.nodes[id] = &node{kind: freePage, next: tx.meta.freeHead}
+	tx.meta.freeHead = id
+	tx.markDirty(id)
+}
+
+// step is a branch on the path from root to leaf
+type step struct {
+	id    pgid
+	node  *node
+	index int
+}
+
+// descend finds the leaf which may contain key, returns path of branches
+func (tx *Tx) descend(key []byte) (path []step, id pgid, n *node, err error) {
+	id = tx.meta.root
+	for {
+		if n, err = tx.node(id); err != nil {
+			return nil, 0, nil, err
+		}
+		if n.isLeaf() {
+			return path, id, n, nil
+		}
+		if n.kind != branchPage || len(n.children) == 0 {
+			return nil, 0, nil, ErrCorrupted
+		}
+		index, _ := n.search(key)
+		path = append(path, step{id: id, node: n, index: index})
+		id = n.children[index]
+	}
+}
+
+// edge finds the leftmost (first is true) or rightmost leaf
+func (tx *Tx) edge(first bool) (pgid, *node, error) {
+	id := tx.meta.root
+	for {
+		n, err := tx.node(id)
+		if err != nil {
+			return 0, nil, err
+		}
+		if n.isLeaf() {
+			return id, n, nil
+		}
+		if n.kind != branchPage || len(n.children) == 0 {
+			return 0, nil, ErrCorrupted
+		}
+		if first {
+			id = n.children[0]
+		} else {
+			id = n.children[len(n.children)-1]
+		}
+	}
+}
+
+// Len returns number of keys inside the tree
+func (tx *Tx) Len() int {
+	return int(tx.meta.count)
+}
+
+// Get returns value of key, the returned slice is a copy owned by caller
+func (tx *Tx) Get(key []byte) (value []byte, found bool, err error) {
+	_, _, n, err := tx.descend(key)
+	if err != nil {
+		return nil, false, err
+	}
+	index, found := n.search(key)
+	if !found {
+		return nil, false, nil
+	}
+	return append([]byte{}, n.values[index]...), true, nil
+}
+
+// maxEntrySize keeps at least 4 entries inside a page, so split always produces two valid pages
+func (tx *Tx) maxEntrySize() int {
+	return (tx.pageSize() - pageHeaderSize) / 4
+}
+
+// Put sets value of key
+func (tx *Tx) Put(key, value []byte) error {
+	if !tx.writable {
+		return ErrTxNotWritable
+	}
+	if len(key) == 0 {
+		return ErrKeyRequired
+	}
+	if leafEntryHeaderSize+len(key)+len(value) > tx.maxEntrySize() {
+		return ErrTooLarge
+	}
+	path, id, n, err := tx.descend(key)
+	if err != nil {
+		return err
+	}
+	value = append([]byte{}, value...)
+	index, found := n.search(key)
+	if found {
+		n.values[index] = value
+	} else {
+		n.keys = append(n.keys, nil)
+		copy(n.keys[index+1:], n.keys[index:])
+		n.keys[index] = append([]byte{}, key...)
+		n.values = append(n.values, nil)
+		copy(n.values[index+1:], n.values[index:])
+		n.values[index] = value
+		tx.meta.count++
+	}
+	tx.markDirty(id)
+	return tx.split(path, id, n)
+}
+
+// split splits overflowed node and inserts separator into parent, up to the root
+func (tx *Tx) split(path []step, id pgid, n *node) error {
+	for n.size() > tx.pageSize() {
+		index := n.splitIndex()
+		right := &node{kind: n.kind}
+		var sep []byte
+		if n.isLeaf() {
+			right.keys = append([][]byte{}, n.keys[index:]...)
+			right.values = append([][]byte{}, n.values[index:]...)
+			n.keys, n.values = n.keys[:index:index], n.values[:index:index]
+			sep = right.keys[0]
+		} else {
+			sep = n.keys[index]
+			right.keys = append([][]byte{}, n.keys[index+1:]...)
+			right.children = append([]pgid{}, n.children[index+1:]...)
+			n.keys, n.children = n.keys[:index:index], n.children[:index+1:index+1]
+		}
+		rightID, err := tx.allocate(right)
+		if err != nil {
+			return err
+		}
+		if n.isLeaf() {
+			// link siblings: n <-> right <-> n.next
+			right.next, right.prev = n.next, id
+			if n.next != 0 {
+				next, err := tx.node(n.next)
+				if err != nil {
+					return err
+				}
+				next.prev = rightID
+				tx.markDirty(n.next)
+			}
+			n.next = rightID
+		}
+
+		if len(path) == 0 {
+			// split root, tree grows higher
+			root := &node{kind: branchPage, keys: [][]byte{sep}, children: []pgid{id, rightID}}
+			rootID, err := tx.allocate(root)
+			if err != nil {
+				return err
+			}
+			tx.meta.root = rootID
+			return nil
+		}
+		parent := path[len(path)-1]
+		p := parent.node
+		p.keys = append(p.keys, nil)
+		copy(p.keys[parent.index+1:], p.keys[parent.index:])
+		p.keys[parent.index] = sep
+		p.children = append(p.children, 0)
+		copy(p.children[parent.index+2:], p.children[parent.index+1:])
+		p.children[parent.index+1] = rightID
+		tx.markDirty(parent.id)
+
+		path, id, n = path[:len(path)-1], parent.id, p
+	}
+	return nil
+}
+
+// Delete deletes key, returns false if key not found
+func (tx *Tx) Delete(key []byte) (bool, error) {
+	if !tx.writable {
+		return false, ErrTxNotWritable
+	}
+	path, id, n, err := tx.descend(key)
+	if err != nil {
+		return false, err
+	}
+	index, found := n.search(key)
+	if !found {
+		return false, nil
+	}
+	n.keys = append(n.keys[:index], n.keys[index+1:]...)
+	n.values = append(n.values[:index], n.values[index+1:]...)
+	tx.meta.count--
+	tx.markDirty(id)
+	return true, tx.rebalance(path, id, n)
+}
+
+// rebalance merges or redistributes underflowed node with its sibling, up to the root
+//	a redistribution changes one separator of parent, which is split if it overflows
+func (tx *Tx) rebalance(path []step, id pgid, n *node) error {
+	for len(path) > 0 {
+		if n.size() >= tx.pageSize()/4 {
+			return nil
+		}
+		parent := path[len(path)-1]
+		// pair of adjacent children: (leftIndex, leftIndex + 1)
+		leftIndex := parent.index
+		if leftIndex == len(parent.node.children)-1 {
+			leftIndex--
+		}
+		merged, err := tx.redistribute(parent.id, parent.node, leftIndex)
+		if err != nil {
+			return err
+		}
+		if !merged {
+			// the new separator may be longer than the old one, split parent up to the root as Put does
+			return tx.split(path[:len(path)-1], parent.id, parent.node)
+		}
+		path, id, n = path[:len(path)-1], parent.id, parent.node
+	}
+	// root branch without key, tree becomes lower
+	if n.kind == branchPage && len(n.keys) == 0 {
+		tx.meta.root = n.children[0]
+		tx.free(id)
+	}
+	return nil
+}
+
+// redistribute merges children leftIndex and leftIndex + 1 of parent if they fit into one page,
+//	otherwise moves entries between them to make similar sizes and replaces their separator, returns true if merged
+//	notice: parent may overflow when not merged
+func (tx *Tx) redistribute(parentID pgid, parent *node, leftIndex int) (merged bool, err error) {
+	leftID, rightID := parent.children[leftIndex], parent.children[leftIndex+1]
+	left, err := tx.node(leftID)
+	if err != nil {
+		return false, err
+	}
+	right, err := tx.node(rightID)
+	if err != nil {
+		return false, err
+	}
+	tx.markDirty(parentID, leftID, rightID)
+
+	all := &node{kind: left.kind}
+	all.keys = append(append([][]byte{}, left.keys...), right.keys...)
+	if left.isLeaf() {
+		all.values = append(append([][]byte{}, left.values...), right.values...)
+	} else {
+		// separator goes down
+		all.keys = append(append(append([][]byte{}, left.keys...), parent.keys[leftIndex]), right.keys...)
+		all.children = append(append([]pgid{}, left.children...), right.children...)
+	}
+
+	if all.size() <= tx.pageSize() {
+		left.keys, left.values, left.children = all.keys, all.values, all.children
+		if left.isLeaf() {
+			left.next = right.next
+			if right.next != 0 {
+				next, err := tx.node(right.next)
+				if err != nil {
+					return false, err
+				}
+				next.prev = leftID
+				tx.markDirty(right.next)
+			}
+		}
+		parent.keys = append(parent.keys[:leftIndex], parent.keys[leftIndex+1:]...)
+		parent.children = append(parent.children[:leftIndex+1], parent.children[leftIndex+2:]...)
+		tx.free(rightID)
+		return true, nil
+	}
+
+	index := all.splitIndex()
+	if left.isLeaf() {
+		left.keys, left.values = all.keys[:index:index], all.values[:index:index]
+		right.keys, right.values = all.keys[index:], all.values[index:]
+		parent.keys[leftIndex] = right.keys[0]
+	} else {
+		left.keys, left.children = all.keys[:index:index], all.children[:index+1:index+1]
+		right.keys, right.children = all.keys[index+1:], all.children[index+1:]
+		parent.keys[leftIndex] = all.keys[index]
+	}
+	return false, nil
+}
+
+// AscendRange calls f on key value pairs with key in range [start, end) in ascending order until f returns false
+//	nil start / end means unbounded, key and value passed to f must not be modified or retained
+func (tx *Tx) AscendRange(start, end []byte, f func(key, value []byte) bool) error {
+	var n *node
+	var err error
+	index := 0
+	if start == nil {
+		_, n, err = tx.edge(true)
+	} else {
+		_, _, n, err = tx.descend(start)
+		if err == nil {
+			index, _ = n.search(start)
+		}
+	}
+	for err == nil {
+		for ; index < len(n.keys); index++ {
+			if end != nil && bytes.Compare(n.keys[index], end) >= 0 {
+				return nil
+			}
+			if !f(n.keys[index], n.values[index]) {
+				return nil
+			}
+		}
+		// follow sibling link
+		if n.next == 0 {
+			return nil
+		}
+		n, err = tx.node(n.next)
+		index = 0
+	}
+	return err
+}
+
+// DescendRange calls f on key value pairs with key in range (end, start] in descending order until f returns false
+//	nil start / end means unbounded, key and value passed to f must not be modified or retained
+func (tx *Tx) DescendRange(start, end []byte, f func(key, value []byte) bool) error {
+	var n *node
+	var err error
+	index := 0
+	if start == nil {
+		_, n, err = tx.edge(false)
+		if err == nil {
+			index = len(n.keys) - 1
+		}
+	} else {
+		_, _, n, err = tx.descend(start)
+		if err == nil {
+			// last key <= start
+			index = sort.Search(len(n.keys), func(i int) bool {
+				return bytes.Compare(n.keys[i], start) > 0
+			}) - 1
+		}
+	}
+	for err == nil {
+		for ; index >= 0; index-- {
+			if end != nil && bytes.Compare(n.keys[index], end) <= 0 {
+				return nil
+			}
+			if !f(n.keys[index], n.values[index]) {
+				return nil
+			}
+		}
+		// follow sibling link
+		if n.prev == 0 {
+			return nil
+		}
+		n, err = tx.node(n.prev)
+		if err == nil {
+			index = len(n.keys) - 1
+		}
+	}
+	return err
+}
+
+// commit writes dirty pages and meta page through wal
+func (tx *Tx) commit() error {
+	if len(tx.dirty) == 0 && tx.meta == tx.tree.meta {
+		return nil
+	}
+	return tx.tree.write(tx.meta, tx.pages())
+}
+
+// pages encodes meta page and dirty pages
+func (tx *Tx) pages() []walPage {
+	pageSize := tx.pageSize()
+	pages := make([]walPage, 0, len(tx.dirty)+1)
+	metaPage := walPage{id: metaPageID, data: make([]byte, pageSize)}
+	tx.meta.encode(metaPage.data)
+	pages = append(pages, metaPage)
+	for id := range tx.dirty {
+		p := walPage{id: id, data: make([]byte, pageSize)}
+		tx.nodes[id].encode(p.data)
+		pages = append(pages, p)
+	}
+	return pages
+}
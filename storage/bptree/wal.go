@@ -0,0 +1,110 @@
+package bptree
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os"
+)
+
+// write-ahead log (redo log) makes commits atomic:
+//	1. full images of all dirty pages are written into wal file with a checksum trailer and fsync-ed
+//	2. pages are copied into the mapped file and msync-ed
+//	3. wal file is truncated
+//	if crash happens before 1 completes, checksum mismatches and the wal is discarded (transaction rolled back),
+//	otherwise wal is replayed on next Open (transaction committed), replaying is idempotent
+//
+// wal layout: magic(4) pageSize(4) {pgid(8) page(pageSize)}... count(8) crc32(4)
+
+const (
+	walSuffix      = "-wal"
+	walHeaderSize  = 8
+	walTrailerSize = 12
+)
+
+type walPage struct {
+	id   pgid
+	data []byte
+}
+
+func writeWAL(f *os.File, pageSize int, pages []walPage) error {
+	buf := make([]byte, walHeaderSize, walHeaderSize+len(pages)*(8+pageSize)+walTrailerSize)
+	binary.LittleEndian.PutUint32(buf[0:], magic)
+	binary.LittleEndian.PutUint32(buf[4:], uint32(pageSize))
+	var id [8]byte
+	for _, p := range pages {
+		binary.LittleEndian.PutUint64(id[:], uint64(p.id))
+		buf = append(buf, id[:]...)
+		buf = append(buf, p.data...)
+	}
+	var trailer [walTrailerSize]byte
+	binary.LittleEndian.PutUint64(trailer[0:], uint64(len(pages)))
+	buf = append(buf, trailer[:8]...)
+	binary.LittleEndian.PutUint32(trailer[8:], crc32.ChecksumIEEE(buf))
+	buf = append(buf, trailer[8:]...)
+
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := f.WriteAt(buf, 0); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// readWAL returns pages of a complete wal, nil if wal is empty or incomplete
+func readWAL(f *os.File) (pageSize int, pages []walPage, err error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, nil, err
+	}
+	size := fi.Size()
+	if size < walHeaderSize+walTrailerSize {
+		return 0, nil, nil
+	}
+	buf := make([]byte, size)
+	if _, err := f.ReadAt(buf, 0); err != nil && err != io.EOF {
+		return 0, nil, err
+	}
+	if binary.LittleEndian.Uint32(buf[0:]) != magic {
+		return 0, nil, nil
+	}
+	pageSize = int(binary.LittleEndian.Uint32(buf[4:]))
+	body := buf[:len(buf)-4]
+	count := binary.LittleEndian.Uint64(body[len(body)-8:])
+	if !validPageSize(pageSize) ||
+		uint64(len(buf)) != walHeaderSize+count*uint64(8+pageSize)+walTrailerSize ||
+		binary.LittleEndian.Uint32(buf[len(buf)-4:]) != crc32.ChecksumIEEE(body) {
+		return 0, nil, nil
+	}
+	off := walHeaderSize
+	for i := uint64(0); i < count; i++ {
+		id := pgid(binary.LittleEndian.Uint64(buf[off:]))
+		off += 8
+		pages = append(pages, walPage{id: id, data: buf[off : off+pageSize]})
+		off += pageSize
+	}
+	return pageSize, pages, nil
+}
+
+// recoverWAL replays complete wal into data file then clears wal
+func recoverWAL(f, wal *os.File) error {
+	pageSize, pages, err := readWAL(wal)
+	if err != nil {
+		return err
+	}
+	for _, p := range pages {
+		if _, err := f.WriteAt(p.data, int64(p.id)*int64(pageSize)); err != nil {
+			return err
+		}
+	}
+	if len(pages) > 0 {
+		if err := f.Sync(); err != nil {
+			return err
+		}
+	}
+	if err := wal.Truncate(0); err != nil {
+		return err
+	}
+	return wal.Sync()
+}
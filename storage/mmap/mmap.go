@@ -6,6 +6,7 @@ import (
 	"os"
 	"runtime"
 	"syscall"
+	"unsafe"
 )
 
 // PROT_EXEC                         = 0x4
@@ -164,3 +165,16 @@ func (m *MMap) WriteAt(p []byte, off int64) (n int, err error) {
 	}
 	return n, nil
 }
+
+// Sync ...
+// flushes changes on the mapped memory back to the underlying file and waits for it to complete (msync)
+func (m *MMap) Sync() error {
+	if m.data == nil {
+		return fmt.Errorf("mmap: closed")
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_MSYNC, uintptr(unsafe.Pointer(&m.data[0])), uintptr(len(m.data)), syscall.MS_SYNC)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -100,6 +100,7 @@ func TestNew(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, n, wn)
 	// change on file
+	assert.NoError(t, m.Sync())
 	fData, err := ioutil.ReadFile(fWithData.Name())
 	assert.NoError(t, err)
 	assert.Equal(t, data, fData)
@@ -108,6 +109,7 @@ func TestNew(t *testing.T) {
 	assert.Equal(t, n-1, wn)
 	err = m.Close()
 	assert.NoError(t, err)
+	assert.Error(t, m.Sync())
 
 	// non-zero offset
 	ps := os.Getpagesize()
@@ -12,11 +12,20 @@ const (
 	LongestCommonSubsequence
 	// Hamming ...
 	Hamming
-	// TODO: Damerau-Levenshtein distance and Jaro distance
-	// DamerauLevenshtein
-	// Jaro
+	// DamerauLevenshtein ...
+	DamerauLevenshtein
+	// Jaro is a similarity, see Similarity
+	Jaro
+	// JaroWinkler is a similarity, see Similarity
+	JaroWinkler
+	// Cosine is a similarity of bigram counts, see Similarity
+	Cosine
+	// SmithWaterman is a local alignment score, see Similarity
+	SmithWaterman
 )
 
+var errUnsupportedType = errors.New("unsupported distance type")
+
 // EditDistance ...
 /*
  * https://en.wikipedia.org/wiki/Edit_distance
@@ -25,6 +34,7 @@ const (
  * The Hamming distance allows only substitution, hence, it only applies to strings of the same length.
  * The Damerau–Levenshtein distance allows insertion, deletion, substitution, and the transposition of two adjacent characters.
  * The Jaro distance allows only transposition.
+ * Bytes are compared, Jaro, JaroWinkler, Cosine and SmithWaterman are not edit distances and return an error.
  */
 func EditDistance(t DistanceType, s1, s2 string) (int, error) {
 	switch t {
@@ -34,8 +44,10 @@ func EditDistance(t DistanceType, s1, s2 string) (int, error) {
 		return lcs(s1, s2), nil
 	case Hamming:
 		return hamming(s1, s2)
+	case DamerauLevenshtein:
+		return damerauLevenshtein(s1, s2), nil
 	default:
-		return -1, errors.New("unsupported distance type")
+		return -1, errUnsupportedType
 	}
 }
 
@@ -67,6 +79,49 @@ func levenshtein(s1, s2 string) int {
 	return column[s1len]
 }
 
+// https://en.wikipedia.org/wiki/Damerau%E2%80%93Levenshtein_distance#Distance_with_adjacent_transpositions
+// unlike the optimal string alignment distance, transposed characters may be edited again, so it is a metric
+// O(nm), O(nm)
+func damerauLevenshtein(s1, s2 string) int {
+	s1len, s2len := len(s1), len(s2)
+	if s1len == 0 || s2len == 0 {
+		return s1len + s2len
+	}
+	// last row of s1 where every byte was seen
+	var da [256]int
+	maxDist := s1len + s2len
+	// d[i+1][j+1] is the distance between s1[:i] and s2[:j], row and column 0 are maxDist
+	d := make([][]int, s1len+2)
+	for i := range d {
+		d[i] = make([]int, s2len+2)
+		d[i][0] = maxDist
+	}
+	for j := 0; j <= s2len; j++ {
+		d[0][j+1] = maxDist
+		d[1][j+1] = j
+	}
+	for i := 1; i <= s1len; i++ {
+		d[i+1][1] = i
+		// last column of s2 matching s1[i-1]
+		db := 0
+		for j := 1; j <= s2len; j++ {
+			k, l := da[s2[j-1]], db
+			cost := 1
+			if s1[i-1] == s2[j-1] {
+				cost = 0
+				db = j
+			}
+			d[i+1][j+1] = min3(d[i][j]+cost, d[i+1][j]+1, d[i][j+1]+1)
+			// s1[k-1] and s2[l-1] transposed, characters between them deleted or inserted
+			if t := d[k][l] + (i - k - 1) + 1 + (j - l - 1); t < d[i+1][j+1] {
+				d[i+1][j+1] = t
+			}
+		}
+		da[s1[i-1]] = i
+	}
+	return d[s1len+1][s2len+1]
+}
+
 func min3(x, y, z int) int {
 	if x < y {
 		if x < z {
@@ -34,3 +34,45 @@ func TestEditDistance(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, -1, d)
 }
+
+func TestEditDistance_DamerauLevenshtein(t *testing.T) {
+	for _, c := range []struct {
+		s1, s2 string
+		d      int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abcd", "acbd", 1},
+		// the optimal string alignment distance is 3
+		{"ca", "abc", 2},
+		{"a cat", "a abct", 2},
+		{"crate", "trace", 2},
+		{"kitten", "sitting", 3},
+	} {
+		d, err := EditDistance(DamerauLevenshtein, c.s1, c.s2)
+		assert.NoError(t, err)
+		assert.Equal(t, c.d, d, c.s1+" "+c.s2)
+	}
+
+	// a metric not greater than levenshtein
+	words := []string{"", "a", "ab", "ba", "abc", "acb", "bca", "cab", "abab", "baba", "cabbage"}
+	for _, x := range words {
+		for _, y := range words {
+			dxy, _ := EditDistance(DamerauLevenshtein, x, y)
+			dyx, _ := EditDistance(DamerauLevenshtein, y, x)
+			assert.Equal(t, dxy, dyx)
+			assert.True(t, dxy <= levenshtein(x, y))
+			for _, z := range words {
+				dxz, _ := EditDistance(DamerauLevenshtein, x, z)
+				dzy, _ := EditDistance(DamerauLevenshtein, z, y)
+				assert.True(t, dxy <= dxz+dzy)
+			}
+		}
+	}
+
+	for _, typ := range []DistanceType{Jaro, JaroWinkler, Cosine, SmithWaterman} {
+		d, err := EditDistance(typ, "a", "b")
+		assert.Error(t, err)
+		assert.Equal(t, -1, d)
+	}
+}
@@ -0,0 +1,214 @@
+package strutils
+
+import (
+	"sort"
+)
+
+// Match is a word found by a fuzzy search
+type Match struct {
+	Word string
+	// Distance is the edit distance to the query for BKTree, Similarity is in [0, 1] for NGramIndex
+	Distance   int
+	Similarity float64
+}
+
+// BKTree is a Burkhard-Keller tree for fuzzy lookup of words within an edit distance
+/*
+ * https://en.wikipedia.org/wiki/BK-tree
+ * Children of a node are keyed by their distance to it. By the triangle inequality, words within d of a query q
+ * are only below children keyed in [dist(q, node) - d, dist(q, node) + d], so a small d visits few nodes.
+ * notice: not thread-safe
+ */
+type BKTree struct {
+	distance func(s1, s2 string) int
+	root     *bkNode
+	size     int
+}
+
+type bkNode struct {
+	word     string
+	children map[int]*bkNode
+}
+
+// NewBKTree creates a BK-tree, t is Levenshtein or DamerauLevenshtein, which are metrics
+func NewBKTree(t DistanceType) (*BKTree, error) {
+	switch t {
+	case Levenshtein:
+		return &BKTree{distance: levenshtein}, nil
+	case DamerauLevenshtein:
+		return &BKTree{distance: damerauLevenshtein}, nil
+	default:
+		return nil, errUnsupportedType
+	}
+}
+
+// Add adds word, returns false if it was added before
+func (t *BKTree) Add(word string) bool {
+	if t.root == nil {
+		t.root = &bkNode{word: word}
+		t.size++
+		return true
+	}
+	node := t.root
+	for {
+		d := t.distance(word, node.word)
+		if d == 0 {
+			return false
+		}
+		child, ok := node.children[d]
+		if !ok {
+			if node.children == nil {
+				node.children = make(map[int]*bkNode)
+			}
+			node.children[d] = &bkNode{word: word}
+			t.size++
+			return true
+		}
+		node = child
+	}
+}
+
+// Len returns number of words
+func (t *BKTree) Len() int {
+	return t.size
+}
+
+// Search returns words within maxDistance of word, sorted by distance then word
+func (t *BKTree) Search(word string, maxDistance int) []Match {
+	var matches []Match
+	if t.root == nil || maxDistance < 0 {
+		return matches
+	}
+	stack := []*bkNode{t.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		d := t.distance(word, node.word)
+		if d <= maxDistance {
+			matches = append(matches, Match{Word: node.word, Distance: d})
+		}
+		for k, child := range node.children {
+			if k >= d-maxDistance && k <= d+maxDistance {
+				stack = append(stack, child)
+			}
+		}
+	}
+	sortMatches(matches)
+	return matches
+}
+
+// Closest returns the nearest word, the smallest one on ties, ok is false if tree is empty
+//	the search radius shrinks to the best distance found so far
+func (t *BKTree) Closest(word string) (match Match, ok bool) {
+	if t.root == nil {
+		return match, false
+	}
+	match.Distance = -1
+	stack := []*bkNode{t.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		d := t.distance(word, node.word)
+		if match.Distance < 0 || d < match.Distance || d == match.Distance && node.word < match.Word {
+			match = Match{Word: node.word, Distance: d}
+		}
+		for k, child := range node.children {
+			if k >= d-match.Distance && k <= d+match.Distance {
+				stack = append(stack, child)
+			}
+		}
+	}
+	return match, true
+}
+
+// NGramIndex finds words sharing the most n-grams with a query, ranked by NGramCosine
+//	an inverted index maps every n-gram to the words containing it, so only words sharing an n-gram are scored,
+//	unlike BKTree it finds words with distant typos or reordered parts
+//	notice: not thread-safe
+type NGramIndex struct {
+	n        int
+	words    []string
+	norms    []float64
+	postings map[string][]posting
+	ids      map[string]int
+}
+
+// posting is count of an n-gram in word id
+type posting struct {
+	id, count int
+}
+
+// NewNGramIndex creates an index of n-grams, n < 1 means 2
+func NewNGramIndex(n int) *NGramIndex {
+	if n < 1 {
+		n = 2
+	}
+	return &NGramIndex{
+		n:        n,
+		postings: make(map[string][]posting),
+		ids:      make(map[string]int),
+	}
+}
+
+// Add adds word, returns false if it was added before
+func (idx *NGramIndex) Add(word string) bool {
+	if _, ok := idx.ids[word]; ok {
+		return false
+	}
+	id := len(idx.words)
+	idx.ids[word] = id
+	idx.words = append(idx.words, word)
+	grams := NGrams(word, idx.n)
+	idx.norms = append(idx.norms, gramNorm(grams))
+	for g, c := range grams {
+		idx.postings[g] = append(idx.postings[g], posting{id: id, count: c})
+	}
+	return true
+}
+
+// Len returns number of words
+func (idx *NGramIndex) Len() int {
+	return len(idx.words)
+}
+
+// Search returns at most k words with NGramCosine similarity to word at least minSimilarity,
+// sorted by similarity from the greatest then word
+//	words sharing no n-gram with word are never returned
+func (idx *NGramIndex) Search(word string, k int, minSimilarity float64) []Match {
+	var matches []Match
+	grams := NGrams(word, idx.n)
+	if k < 1 || len(grams) == 0 {
+		return matches
+	}
+	dots := make(map[int]int)
+	for g, c := range grams {
+		for _, p := range idx.postings[g] {
+			dots[p.id] += c * p.count
+		}
+	}
+	norm := gramNorm(grams)
+	for id, dot := range dots {
+		if sim := float64(dot) / (norm * idx.norms[id]); sim >= minSimilarity {
+			matches = append(matches, Match{Word: idx.words[id], Similarity: sim})
+		}
+	}
+	sortMatches(matches)
+	if len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches
+}
+
+// sortMatches sorts by distance, then similarity from the greatest, then word
+func sortMatches(matches []Match) {
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if a.Similarity != b.Similarity {
+			return a.Similarity > b.Similarity
+		}
+		return a.Word < b.Word
+	})
+}
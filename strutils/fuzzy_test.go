@@ -0,0 +1,141 @@
+package strutils
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+var commands = []string{"add", "bisect", "branch", "checkout", "cherry-pick", "clone", "commit", "config", "diff",
+	"fetch", "grep", "init", "log", "merge", "mv", "pull", "push", "rebase", "remote", "reset", "restore", "revert",
+	"rm", "show", "stash", "status", "switch", "tag"}
+
+func randomWord(n int, alphabet string) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = alphabet[rand.Intn(len(alphabet))]
+	}
+	return string(b)
+}
+
+func TestBKTree(t *testing.T) {
+	_, err := NewBKTree(Jaro)
+	assert.Error(t, err)
+
+	for _, typ := range []DistanceType{Levenshtein, DamerauLevenshtein} {
+		tree, err := NewBKTree(typ)
+		assert.NoError(t, err)
+		_, ok := tree.Closest("a")
+		assert.False(t, ok)
+		assert.Empty(t, tree.Search("a", 3))
+
+		words := make(map[string]bool)
+		for i := 0; i < 500; i++ {
+			w := randomWord(rand.Intn(8), "abcd")
+			assert.Equal(t, !words[w], tree.Add(w))
+			words[w] = true
+		}
+		assert.Equal(t, len(words), tree.Len())
+
+		for i := 0; i < 50; i++ {
+			q := randomWord(rand.Intn(8), "abcd")
+			for _, max := range []int{0, 1, 2} {
+				var want []Match
+				for w := range words {
+					if d, _ := EditDistance(typ, q, w); d <= max {
+						want = append(want, Match{Word: w, Distance: d})
+					}
+				}
+				sortMatches(want)
+				assert.Equal(t, want, tree.Search(q, max))
+			}
+
+			closest, ok := tree.Closest(q)
+			assert.True(t, ok)
+			best := Match{Distance: -1}
+			for w := range words {
+				if d, _ := EditDistance(typ, q, w); best.Distance < 0 || d < best.Distance || d == best.Distance && w < best.Word {
+					best = Match{Word: w, Distance: d}
+				}
+			}
+			assert.Equal(t, best, closest)
+		}
+	}
+}
+
+func TestBKTree_DidYouMean(t *testing.T) {
+	tree, _ := NewBKTree(DamerauLevenshtein)
+	for _, c := range commands {
+		tree.Add(c)
+	}
+	m, _ := tree.Closest("comit")
+	assert.Equal(t, Match{Word: "commit", Distance: 1}, m)
+	m, _ = tree.Closest("stauts")
+	assert.Equal(t, Match{Word: "status", Distance: 1}, m)
+	assert.Equal(t, []Match{{Word: "rm", Distance: 1}, {Word: "mv", Distance: 2}}, tree.Search("pm", 2))
+}
+
+func TestNGramIndex(t *testing.T) {
+	idx := NewNGramIndex(0)
+	assert.Empty(t, idx.Search("commit", 3, 0))
+	for _, c := range commands {
+		assert.True(t, idx.Add(c))
+	}
+	assert.False(t, idx.Add("commit"))
+	assert.Equal(t, len(commands), idx.Len())
+
+	matches := idx.Search("comit", 3, 0.5)
+	assert.Equal(t, "commit", matches[0].Word)
+	assert.InDelta(t, NGramCosine("comit", "commit", 2), matches[0].Similarity, 1e-9)
+	// reordered parts
+	matches = idx.Search("pick-cherry", 1, 0)
+	assert.Equal(t, "cherry-pick", matches[0].Word)
+	assert.Empty(t, idx.Search("zzz", 3, 0))
+	assert.Empty(t, idx.Search("commit", 0, 0))
+
+	// same as scoring every word
+	words := make([]string, 300)
+	idx = NewNGramIndex(2)
+	for i := range words {
+		words[i] = randomWord(1+rand.Intn(7), "abcd")
+		idx.Add(words[i])
+	}
+	for i := 0; i < 50; i++ {
+		q := randomWord(1+rand.Intn(7), "abcd")
+		matches := idx.Search(q, 1000, 0.3)
+		seen := make(map[string]bool)
+		for _, m := range matches {
+			assert.InDelta(t, NGramCosine(q, m.Word, 2), m.Similarity, 1e-9)
+			seen[m.Word] = true
+		}
+		for _, w := range words {
+			if NGramCosine(q, w, 2) >= 0.3 {
+				assert.True(t, seen[w], q+" "+w)
+			}
+		}
+	}
+}
+
+const letters = "abcdefghijklmnopqrstuvwxyz"
+
+func BenchmarkBKTree_Search(b *testing.B) {
+	tree, _ := NewBKTree(Levenshtein)
+	for i := 0; i < 10000; i++ {
+		tree.Add(randomWord(10, letters))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Search(randomWord(10, letters), 1)
+	}
+}
+
+func BenchmarkNGramIndex_Search(b *testing.B) {
+	idx := NewNGramIndex(2)
+	for i := 0; i < 10000; i++ {
+		idx.Add(randomWord(10, letters))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx.Search(randomWord(10, letters), 5, 0.5)
+	}
+}
@@ -0,0 +1,214 @@
+package strutils
+
+import (
+	"math"
+)
+
+const (
+	// winklerPrefixScale boosts Jaro similarity by 0.1 for every common prefix byte, at most winklerPrefixLength
+	winklerPrefixScale  = 0.1
+	winklerPrefixLength = 4
+	// winklerThreshold is the Jaro similarity above which the prefix boost applies
+	winklerThreshold = 0.7
+
+	// Smith-Waterman scores
+	swMatch    = 2
+	swMismatch = -1
+	swGap      = -1
+)
+
+// Similarity returns similarity of s1 and s2 in [0, 1], 1 means equal
+/*
+ * Levenshtein, DamerauLevenshtein and Hamming distances d are normalized as 1 - d / max(len(s1), len(s2)).
+ * https://en.wikipedia.org/wiki/Jaro%E2%80%93Winkler_distance
+ * Jaro counts matching bytes not farther than half the longer string, and transpositions among them.
+ * JaroWinkler favors strings sharing a prefix, which suits typos in command names.
+ * Cosine is the cosine of bigram count vectors, so it ignores word order.
+ * https://en.wikipedia.org/wiki/Smith%E2%80%93Waterman_algorithm
+ * SmithWaterman is the best local alignment score divided by the score of the shorter string aligned with itself,
+ * so a string found inside another one is similar.
+ * LongestCommonSubsequence is not supported.
+ */
+func Similarity(t DistanceType, s1, s2 string) (float64, error) {
+	switch t {
+	case Levenshtein, DamerauLevenshtein, Hamming:
+		d, err := EditDistance(t, s1, s2)
+		if err != nil {
+			return -1, err
+		}
+		longest := len(s1)
+		if len(s2) > longest {
+			longest = len(s2)
+		}
+		if longest == 0 {
+			return 1, nil
+		}
+		return 1 - float64(d)/float64(longest), nil
+	case Jaro:
+		return jaro(s1, s2), nil
+	case JaroWinkler:
+		return jaroWinkler(s1, s2), nil
+	case Cosine:
+		return NGramCosine(s1, s2, 2), nil
+	case SmithWaterman:
+		shortest := len(s1)
+		if len(s2) < shortest {
+			shortest = len(s2)
+		}
+		if shortest == 0 {
+			if len(s1) == len(s2) {
+				return 1, nil
+			}
+			return 0, nil
+		}
+		return float64(smithWaterman(s1, s2)) / float64(swMatch*shortest), nil
+	default:
+		return -1, errUnsupportedType
+	}
+}
+
+// O(nm), O(n+m)
+func jaro(s1, s2 string) float64 {
+	s1len, s2len := len(s1), len(s2)
+	if s1len == 0 || s2len == 0 {
+		if s1len == s2len {
+			return 1
+		}
+		return 0
+	}
+	window := s1len
+	if s2len > window {
+		window = s2len
+	}
+	window = window/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	matched1, matched2 := make([]bool, s1len), make([]bool, s2len)
+	matches := 0
+	for i := 0; i < s1len; i++ {
+		lo, hi := i-window, i+window+1
+		if lo < 0 {
+			lo = 0
+		}
+		if hi > s2len {
+			hi = s2len
+		}
+		for j := lo; j < hi; j++ {
+			if !matched2[j] && s1[i] == s2[j] {
+				matched1[i], matched2[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	// matched bytes out of order, every transposition is counted twice
+	outOfOrder := 0
+	for i, j := 0, 0; i < s1len; i++ {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[j] {
+			j++
+		}
+		if s1[i] != s2[j] {
+			outOfOrder++
+		}
+		j++
+	}
+	m := float64(matches)
+	return (m/float64(s1len) + m/float64(s2len) + (m-float64(outOfOrder)/2)/m) / 3
+}
+
+func jaroWinkler(s1, s2 string) float64 {
+	sim := jaro(s1, s2)
+	if sim <= winklerThreshold {
+		return sim
+	}
+	prefix := 0
+	for prefix < winklerPrefixLength && prefix < len(s1) && prefix < len(s2) && s1[prefix] == s2[prefix] {
+		prefix++
+	}
+	return sim + float64(prefix)*winklerPrefixScale*(1-sim)
+}
+
+// NGramCosine returns cosine similarity of n-gram counts of s1 and s2, a string shorter than n is a single n-gram
+//	n < 1 means 2
+func NGramCosine(s1, s2 string, n int) float64 {
+	g1, g2 := NGrams(s1, n), NGrams(s2, n)
+	if len(g1) == 0 || len(g2) == 0 {
+		if len(g1) == len(g2) {
+			return 1
+		}
+		return 0
+	}
+	dot := 0
+	for g, c := range g1 {
+		dot += c * g2[g]
+	}
+	return float64(dot) / (gramNorm(g1) * gramNorm(g2))
+}
+
+// NGrams returns counts of byte n-grams of s, a non empty string shorter than n is a single n-gram
+//	n < 1 means 2
+func NGrams(s string, n int) map[string]int {
+	if n < 1 {
+		n = 2
+	}
+	grams := make(map[string]int)
+	if s == "" {
+		return grams
+	}
+	if len(s) <= n {
+		grams[s]++
+		return grams
+	}
+	for i := 0; i+n <= len(s); i++ {
+		grams[s[i:i+n]]++
+	}
+	return grams
+}
+
+func gramNorm(grams map[string]int) float64 {
+	sum := 0
+	for _, c := range grams {
+		sum += c * c
+	}
+	return math.Sqrt(float64(sum))
+}
+
+// smithWaterman returns the best local alignment score with linear gap penalty
+//	O(nm), O(m)
+func smithWaterman(s1, s2 string) int {
+	prev, cur := make([]int, len(s2)+1), make([]int, len(s2)+1)
+	best := 0
+	for i := 1; i <= len(s1); i++ {
+		for j := 1; j <= len(s2); j++ {
+			score := swMismatch
+			if s1[i-1] == s2[j-1] {
+				score = swMatch
+			}
+			h := prev[j-1] + score
+			if v := prev[j] + swGap; v > h {
+				h = v
+			}
+			if v := cur[j-1] + swGap; v > h {
+				h = v
+			}
+			if h < 0 {
+				h = 0
+			}
+			cur[j] = h
+			if h > best {
+				best = h
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return best
+}
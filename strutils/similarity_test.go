@@ -0,0 +1,73 @@
+package strutils
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSimilarity(t *testing.T) {
+	for _, c := range []struct {
+		t      DistanceType
+		s1, s2 string
+		sim    float64
+	}{
+		{Levenshtein, "kitten", "sitting", 1 - 3.0/7},
+		{DamerauLevenshtein, "abcd", "acbd", 0.75},
+		{Hamming, "abcd", "abce", 0.75},
+		{Levenshtein, "", "", 1},
+		{Jaro, "MARTHA", "MARHTA", 0.944},
+		{Jaro, "DWAYNE", "DUANE", 0.822},
+		{Jaro, "DIXON", "DICKSONX", 0.767},
+		{Jaro, "abc", "xyz", 0},
+		{JaroWinkler, "MARTHA", "MARHTA", 0.961},
+		{JaroWinkler, "DWAYNE", "DUANE", 0.840},
+		{JaroWinkler, "DIXON", "DICKSONX", 0.813},
+		{Jaro, "", "", 1},
+		{JaroWinkler, "", "a", 0},
+		{Cosine, "night", "nacht", 0.25},
+		{Cosine, "abab", "ab", 2 / (2.236 * 1)},
+		{Cosine, "a", "a", 1},
+		{Cosine, "", "a", 0},
+		{SmithWaterman, "abc", "xxabcxx", 1},
+		// ab, gap, c
+		{SmithWaterman, "abc", "xxabxcxx", 5.0 / 6},
+		{SmithWaterman, "abc", "xyz", 0},
+		{SmithWaterman, "", "", 1},
+		{SmithWaterman, "", "abc", 0},
+	} {
+		sim, err := Similarity(c.t, c.s1, c.s2)
+		assert.NoError(t, err)
+		assert.InDelta(t, c.sim, sim, 1e-3, c.s1+" "+c.s2)
+	}
+
+	// symmetric, in [0, 1], 1 for equal strings
+	words := []string{"", "a", "ab", "ba", "abc", "commit", "comit", "checkout", "cherry-pick"}
+	for _, typ := range []DistanceType{Levenshtein, DamerauLevenshtein, Jaro, JaroWinkler, Cosine, SmithWaterman} {
+		for _, x := range words {
+			for _, y := range words {
+				sxy, err := Similarity(typ, x, y)
+				assert.NoError(t, err)
+				syx, _ := Similarity(typ, y, x)
+				assert.InDelta(t, sxy, syx, 1e-9)
+				assert.True(t, sxy >= 0 && sxy <= 1+1e-9)
+				if x == y {
+					assert.InDelta(t, 1, sxy, 1e-9)
+				}
+			}
+		}
+	}
+
+	_, err := Similarity(LongestCommonSubsequence, "a", "b")
+	assert.Error(t, err)
+	_, err = Similarity(Hamming, "a", "bc")
+	assert.Error(t, err)
+}
+
+func TestNGrams(t *testing.T) {
+	assert.Equal(t, map[string]int{"ab": 2, "ba": 1}, NGrams("abab", 2))
+	assert.Equal(t, map[string]int{"ab": 1}, NGrams("ab", 3))
+	assert.Equal(t, map[string]int{"aba": 1, "bab": 1}, NGrams("abab", 3))
+	assert.Empty(t, NGrams("", 2))
+	assert.Equal(t, NGrams("abc", 2), NGrams("abc", 0))
+	assert.InDelta(t, 1, NGramCosine("abab", "baba", 1), 1e-9)
+}